@@ -8,12 +8,16 @@ import (
 	"niuma-house/internal/model"
 	"niuma-house/internal/mq"
 	"niuma-house/internal/router"
+	"niuma-house/internal/service"
 	"niuma-house/internal/task"
+	"niuma-house/internal/ws"
 	"niuma-house/pkg/cache"
 	"niuma-house/pkg/config"
 	"niuma-house/pkg/database"
+	"niuma-house/pkg/filter"
 	"niuma-house/pkg/jwt"
 	"niuma-house/pkg/queue"
+	"niuma-house/pkg/rbac"
 	"niuma-house/pkg/storage"
 )
 
@@ -37,18 +41,35 @@ func main() {
 		log.Fatalf("Failed to migrate database: %v", err)
 	}
 
+	// 初始化 Casbin
+	rbac.InitCasbin(&cfg.Casbin, db)
+
 	// 初始化 Redis
 	cache.InitRedis(&cfg.Redis)
 
+	// 初始化内容过滤并加载敏感词（依赖 Redis 中的词库版本号）
+	filter.Init(&cfg.ContentFilter)
+	if err := service.ReloadSensitiveWords(true); err != nil {
+		log.Printf("Failed to load sensitive words: %v", err)
+	}
+
+	// 初始化 WebSocket Hub（依赖 Redis）
+	ws.InitHub(&cfg.WS)
+
 	// 初始化 MinIO
 	storage.InitMinIO(&cfg.MinIO)
 
-	// 初始化 RabbitMQ
-	queue.InitRabbitMQ(&cfg.RabbitMQ)
+	// 初始化消息队列（RabbitMQ 或进程内队列）
+	queue.Init(&cfg.Queue, &cfg.RabbitMQ)
 	defer queue.Close()
 
 	// 启动 MQ 消费者
 	go mq.StartExpConsumer()
+	go mq.StartNotificationConsumer()
+	go mq.StartLevelConsumer()
+
+	// 启动 outbox 中继
+	go mq.StartOutboxRelay()
 
 	// 启动定时任务
 	task.StartCronJobs()
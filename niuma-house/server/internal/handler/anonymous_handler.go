@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"niuma-house/internal/middleware"
+	"niuma-house/internal/service"
+	"niuma-house/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// AdminRevealAuthor 查看匿名内容的真实作者，每次查看都会记录审计日志
+func AdminRevealAuthor(c *gin.Context) {
+	var req service.RevealRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, response.CodeInvalidParams, "参数错误: "+err.Error())
+		return
+	}
+
+	adminID := middleware.GetCurrentUserID(c)
+	author, reveal, err := GetAnonymousService().Reveal(adminID, c.ClientIP(), &req)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.Fail(c, response.CodeNotFound, "内容不存在")
+			return
+		}
+		response.Fail(c, response.CodeServerError, "查询失败")
+		return
+	}
+
+	response.Success(c, gin.H{
+		"author": author,
+		"reveal": reveal,
+	})
+}
+
+// AdminGetReveals 查看真实作者的审计记录
+func AdminGetReveals(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
+
+	reveals, total, err := GetAnonymousService().ListReveals(c.Query("target_type"), page, size)
+	if err != nil {
+		response.Fail(c, response.CodeServerError, "获取审计记录失败")
+		return
+	}
+
+	response.Success(c, gin.H{
+		"list":  reveals,
+		"total": total,
+		"page":  page,
+		"size":  size,
+	})
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"niuma-house/internal/repository"
+	"niuma-house/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminGetAuditLogs 审计日志列表
+func AdminGetAuditLogs(c *gin.Context) {
+	filter, ok := bindAuditFilter(c)
+	if !ok {
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
+
+	logs, total, err := GetAuditService().List(filter, page, size)
+	if err != nil {
+		response.Fail(c, response.CodeServerError, "获取审计日志失败")
+		return
+	}
+
+	response.Success(c, gin.H{
+		"list":  logs,
+		"total": total,
+		"page":  page,
+		"size":  size,
+	})
+}
+
+// AdminExportAuditLogs 按筛选条件导出审计日志 CSV
+func AdminExportAuditLogs(c *gin.Context) {
+	filter, ok := bindAuditFilter(c)
+	if !ok {
+		return
+	}
+
+	logs, err := GetAuditService().Export(filter)
+	if err != nil {
+		response.Fail(c, response.CodeServerError, "导出审计日志失败")
+		return
+	}
+
+	filename := fmt.Sprintf("audit-logs-%s.csv", time.Now().Format("20060102150405"))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+
+	// UTF-8 BOM，便于 Excel 正确识别中文
+	c.Writer.WriteString("\xEF\xBB\xBF")
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{"ID", "时间", "操作者ID", "操作者", "角色", "操作", "目标类型", "目标ID", "理由", "IP", "HTTP状态", "业务码", "路径", "操作前", "操作后", "请求参数"})
+	for _, l := range logs {
+		actor := ""
+		if l.Actor != nil {
+			actor = l.Actor.Username
+		}
+		w.Write(csvSafe(
+			strconv.FormatUint(uint64(l.ID), 10),
+			l.CreatedAt.Format("2006-01-02 15:04:05"),
+			strconv.FormatUint(uint64(l.ActorID), 10),
+			actor,
+			l.Role,
+			l.Action,
+			l.TargetType,
+			l.TargetID,
+			l.Reason,
+			l.IP,
+			strconv.Itoa(l.Status),
+			strconv.Itoa(l.Code),
+			l.Method+" "+l.Path,
+			string(l.Before),
+			string(l.After),
+			string(l.Params),
+		))
+	}
+	w.Flush()
+}
+
+// csvSafe 以 = + - @ 或制表符、回车开头的单元格加前缀，避免在表格软件中被当作公式执行
+func csvSafe(fields ...string) []string {
+	for i, f := range fields {
+		if f != "" && strings.ContainsRune("=+-@\t\r", rune(f[0])) {
+			fields[i] = "'" + f
+		}
+	}
+	return fields
+}
+
+// bindAuditFilter 解析筛选条件：actor_id、action、target_type、target_id、start、end（2006-01-02，含当天）
+func bindAuditFilter(c *gin.Context) (*repository.AuditFilter, bool) {
+	actorID, _ := strconv.ParseUint(c.Query("actor_id"), 10, 64)
+	filter := &repository.AuditFilter{
+		ActorID:    uint(actorID),
+		Action:     c.Query("action"),
+		TargetType: c.Query("target_type"),
+		TargetID:   c.Query("target_id"),
+	}
+
+	if start := c.Query("start"); start != "" {
+		since, err := time.ParseInLocation("2006-01-02", start, time.Local)
+		if err != nil {
+			response.Fail(c, response.CodeInvalidParams, "开始日期格式应为 2006-01-02")
+			return nil, false
+		}
+		filter.Since = since
+	}
+	if end := c.Query("end"); end != "" {
+		until, err := time.ParseInLocation("2006-01-02", end, time.Local)
+		if err != nil {
+			response.Fail(c, response.CodeInvalidParams, "结束日期格式应为 2006-01-02")
+			return nil, false
+		}
+		filter.Until = until.AddDate(0, 0, 1)
+	}
+	return filter, true
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"strconv"
+
+	"niuma-house/internal/middleware"
+	"niuma-house/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetBlocks 获取黑名单
+func GetBlocks(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
+
+	blocks, total, err := GetBlockService().List(userID, page, size)
+	if err != nil {
+		response.Fail(c, response.CodeServerError, "获取黑名单失败")
+		return
+	}
+
+	response.Success(c, gin.H{
+		"list":  blocks,
+		"total": total,
+		"page":  page,
+		"size":  size,
+	})
+}
+
+// BlockUser 拉黑用户
+func BlockUser(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+
+	var req struct {
+		UserID uint `json:"user_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, response.CodeInvalidParams, "参数错误")
+		return
+	}
+
+	if err := GetBlockService().Block(userID, req.UserID); err != nil {
+		response.Fail(c, response.CodeServerError, err.Error())
+		return
+	}
+	response.Success(c, nil)
+}
+
+// UnblockUser 取消拉黑
+func UnblockUser(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+	blockedID, _ := strconv.ParseUint(c.Param("user_id"), 10, 64)
+
+	if err := GetBlockService().Unblock(userID, uint(blockedID)); err != nil {
+		response.Fail(c, response.CodeServerError, err.Error())
+		return
+	}
+	response.Success(c, nil)
+}
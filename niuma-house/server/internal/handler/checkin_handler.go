@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"niuma-house/internal/middleware"
+	"niuma-house/internal/service"
+	"niuma-house/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Checkin 每日签到
+func Checkin(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+	result, err := GetCheckinService().Checkin(userID)
+	if err != nil {
+		if errors.Is(err, service.ErrAlreadyCheckedIn) {
+			response.Fail(c, response.CodeConflict, err.Error())
+			return
+		}
+		response.Fail(c, response.CodeServerError, "签到失败")
+		return
+	}
+	response.Success(c, result)
+}
+
+// GetCheckinCalendar 获取签到月历，month 格式为 2006-01，默认当月
+func GetCheckinCalendar(c *gin.Context) {
+	month := time.Now()
+	if m := c.Query("month"); m != "" {
+		parsed, err := time.ParseInLocation("2006-01", m, time.Local)
+		if err != nil {
+			response.Fail(c, response.CodeInvalidParams, "月份格式错误")
+			return
+		}
+		month = parsed
+	}
+
+	userID := middleware.GetCurrentUserID(c)
+	calendar, err := GetCheckinService().Calendar(userID, month)
+	if err != nil {
+		response.Fail(c, response.CodeServerError, "获取签到记录失败")
+		return
+	}
+	response.Success(c, calendar)
+}
@@ -4,6 +4,7 @@ import (
 	"strconv"
 
 	"niuma-house/internal/middleware"
+	"niuma-house/internal/model"
 	"niuma-house/internal/service"
 	"niuma-house/pkg/response"
 
@@ -16,7 +17,7 @@ func GetComments(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
 
-	comments, total, err := GetCommentService().List(uint(postID), page, size)
+	comments, total, err := GetCommentService().List(uint(postID), page, size, middleware.GetCurrentUserID(c))
 	if err != nil {
 		response.Fail(c, response.CodeServerError, "获取评论列表失败")
 		return
@@ -30,6 +31,26 @@ func GetComments(c *gin.Context) {
 	})
 }
 
+// GetCommentReplies 加载评论的更多回复
+func GetCommentReplies(c *gin.Context) {
+	rootID, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "10"))
+
+	replies, total, err := GetCommentService().ListReplies(uint(rootID), page, size, middleware.GetCurrentUserID(c))
+	if err != nil {
+		response.Fail(c, response.CodeNotFound, err.Error())
+		return
+	}
+
+	response.Success(c, gin.H{
+		"list":  replies,
+		"total": total,
+		"page":  page,
+		"size":  size,
+	})
+}
+
 // CreateComment 创建评论
 func CreateComment(c *gin.Context) {
 	postID, _ := strconv.ParseUint(c.Param("id"), 10, 64)
@@ -43,10 +64,14 @@ func CreateComment(c *gin.Context) {
 
 	comment, err := GetCommentService().Create(uint(postID), userID, &req)
 	if err != nil {
-		response.Fail(c, response.CodeServerError, err.Error())
+		contentFail(c, err)
 		return
 	}
 
+	if comment.Status == model.CommentStatusHidden {
+		response.SuccessWithMessage(c, contentHeldMessage, comment)
+		return
+	}
 	response.Success(c, comment)
 }
 
@@ -1,9 +1,12 @@
 package handler
 
 import (
+	"errors"
 	"strconv"
 
 	"niuma-house/internal/middleware"
+	"niuma-house/internal/model"
+	"niuma-house/internal/repository"
 	"niuma-house/internal/service"
 	"niuma-house/pkg/response"
 
@@ -15,7 +18,7 @@ func GetCompanies(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
 
-	companies, total, err := GetCompanyService().List(page, size)
+	companies, total, err := GetCompanyService().List(page, size, middleware.GetCurrentUserID(c))
 	if err != nil {
 		response.Fail(c, response.CodeServerError, "获取公司列表失败")
 		return
@@ -29,13 +32,21 @@ func GetCompanies(c *gin.Context) {
 	})
 }
 
-// SearchCompanies 搜索公司
+// SearchCompanies 搜索公司，支持按城市、标签、最低避雷等级筛选
 func SearchCompanies(c *gin.Context) {
 	keyword := c.Query("keyword")
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
-
-	companies, total, err := GetCompanyService().Search(c.Request.Context(), keyword, page, size)
+	riskLevel, _ := strconv.Atoi(c.Query("risk_level"))
+
+	companies, total, err := GetCompanyService().Search(c.Request.Context(), &repository.SearchQuery{
+		Keyword:   keyword,
+		City:      c.Query("city"),
+		Tag:       c.Query("tag"),
+		RiskLevel: riskLevel,
+		Page:      page,
+		Size:      size,
+	}, middleware.GetCurrentUserID(c))
 	if err != nil {
 		response.Fail(c, response.CodeServerError, "搜索失败")
 		return
@@ -50,11 +61,28 @@ func SearchCompanies(c *gin.Context) {
 	})
 }
 
+// CheckCompanyDuplicates 创建前查重
+func CheckCompanyDuplicates(c *gin.Context) {
+	name := c.Query("name")
+	if name == "" {
+		response.Fail(c, response.CodeInvalidParams, "参数错误")
+		return
+	}
+
+	candidates, err := GetCompanyService().FindDuplicates(name)
+	if err != nil {
+		response.Fail(c, response.CodeServerError, "查重失败")
+		return
+	}
+
+	response.Success(c, gin.H{"candidates": candidates})
+}
+
 // GetCompany 获取公司详情
 func GetCompany(c *gin.Context) {
 	id, _ := strconv.ParseUint(c.Param("id"), 10, 64)
 
-	company, err := GetCompanyService().GetByID(uint(id))
+	company, err := GetCompanyService().GetByID(uint(id), middleware.GetViewerID(c), middleware.GetCurrentUserID(c))
 	if err != nil {
 		response.Fail(c, response.CodeNotFound, "公司不存在")
 		return
@@ -74,10 +102,19 @@ func CreateCompany(c *gin.Context) {
 	userID := middleware.GetCurrentUserID(c)
 	company, err := GetCompanyService().Create(userID, &req)
 	if err != nil {
-		response.Fail(c, response.CodeServerError, err.Error())
+		var dupErr *service.DuplicateCompanyError
+		if errors.As(err, &dupErr) {
+			response.FailWithData(c, response.CodeConflict, err.Error(), gin.H{"candidates": dupErr.Candidates})
+			return
+		}
+		contentFail(c, err)
 		return
 	}
 
+	if company.Status == model.CompanyStatusHidden {
+		response.SuccessWithMessage(c, contentHeldMessage, company)
+		return
+	}
 	response.Success(c, company)
 }
 
@@ -109,3 +146,22 @@ func AdminDeleteCompany(c *gin.Context) {
 	}
 	response.Success(c, nil)
 }
+
+// AdminMergeCompany 管理员合并公司
+func AdminMergeCompany(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+
+	var req struct {
+		TargetID uint `json:"target_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, response.CodeInvalidParams, "参数错误")
+		return
+	}
+
+	if err := GetCompanyService().AdminMerge(uint(id), req.TargetID); err != nil {
+		response.Fail(c, response.CodeServerError, "合并失败: "+err.Error())
+		return
+	}
+	response.Success(c, nil)
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"strconv"
+
+	"niuma-house/internal/middleware"
+	"niuma-house/internal/service"
+	"niuma-house/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetCompanyReviews 获取公司评价列表
+func GetCompanyReviews(c *gin.Context) {
+	companyID, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
+
+	reviews, total, err := GetCompanyReviewService().List(uint(companyID), page, size, middleware.GetCurrentUserID(c))
+	if err != nil {
+		response.Fail(c, response.CodeServerError, "获取评价列表失败")
+		return
+	}
+
+	response.Success(c, gin.H{
+		"list":  reviews,
+		"total": total,
+		"page":  page,
+		"size":  size,
+	})
+}
+
+// SubmitCompanyReview 提交公司评价
+func SubmitCompanyReview(c *gin.Context) {
+	companyID, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+	userID := middleware.GetCurrentUserID(c)
+
+	var req service.ReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, response.CodeInvalidParams, "参数错误: "+err.Error())
+		return
+	}
+
+	review, err := GetCompanyReviewService().Submit(uint(companyID), userID, &req)
+	if err != nil {
+		contentFail(c, err)
+		return
+	}
+
+	response.Success(c, review)
+}
+
+// DeleteCompanyReview 删除自己的公司评价
+func DeleteCompanyReview(c *gin.Context) {
+	companyID, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+	userID := middleware.GetCurrentUserID(c)
+
+	if err := GetCompanyReviewService().Delete(uint(companyID), userID); err != nil {
+		response.Fail(c, response.CodeServerError, err.Error())
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// AdminDeleteCompanyReview 管理员删除公司评价
+func AdminDeleteCompanyReview(c *gin.Context) {
+	companyID, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+	reviewID, _ := strconv.ParseUint(c.Param("review_id"), 10, 64)
+
+	if err := GetCompanyReviewService().AdminDelete(uint(companyID), uint(reviewID)); err != nil {
+		response.Fail(c, response.CodeServerError, err.Error())
+		return
+	}
+
+	response.Success(c, nil)
+}
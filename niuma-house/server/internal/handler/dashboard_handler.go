@@ -2,6 +2,7 @@ package handler
 
 import (
 	"niuma-house/internal/model"
+	"niuma-house/pkg/cache"
 	"niuma-house/pkg/database"
 	"niuma-house/pkg/response"
 
@@ -64,7 +65,7 @@ func GetDashboardStats(c *gin.Context) {
 	var totalUsers, totalPosts, totalCompanies, totalComments int64
 	db.Model(&model.User{}).Count(&totalUsers)
 	db.Model(&model.Post{}).Where("status > 0").Count(&totalPosts)
-	db.Model(&model.Company{}).Where("status > 0").Count(&totalCompanies)
+	db.Model(&model.Company{}).Where("status = ?", model.CompanyStatusNormal).Count(&totalCompanies)
 	db.Model(&model.Comment{}).Where("status = 1").Count(&totalComments)
 
 	response.Success(c, gin.H{
@@ -80,3 +81,8 @@ func GetDashboardStats(c *gin.Context) {
 		},
 	})
 }
+
+// GetCacheStats 获取缓存命中统计
+func GetCacheStats(c *gin.Context) {
+	response.Success(c, gin.H{"list": cache.GetStats()})
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"niuma-house/internal/middleware"
+	"niuma-house/internal/service"
+	"niuma-house/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// 内容需人工审核时的提示
+const contentHeldMessage = "内容需要审核，通过后对其他用户可见"
+
+// contentFail 内容被过滤拒绝时返回参数错误，其余为服务器错误
+func contentFail(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrContentRejected) {
+		response.Fail(c, response.CodeInvalidParams, err.Error())
+		return
+	}
+	response.Fail(c, response.CodeServerError, err.Error())
+}
+
+// AdminGetSensitiveWords 敏感词列表
+func AdminGetSensitiveWords(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
+
+	words, total, err := GetFilterService().ListWords(c.Query("keyword"), c.Query("category"), page, size)
+	if err != nil {
+		response.Fail(c, response.CodeServerError, "获取敏感词失败")
+		return
+	}
+
+	response.Success(c, gin.H{
+		"list":  words,
+		"total": total,
+		"page":  page,
+		"size":  size,
+	})
+}
+
+// AdminAddSensitiveWords 批量添加敏感词
+func AdminAddSensitiveWords(c *gin.Context) {
+	var req service.AddSensitiveWordsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, response.CodeInvalidParams, "参数错误: "+err.Error())
+		return
+	}
+
+	added, err := GetFilterService().AddWords(middleware.GetCurrentUserID(c), &req)
+	if err != nil {
+		response.Fail(c, response.CodeServerError, "添加敏感词失败")
+		return
+	}
+	response.Success(c, gin.H{"added": added})
+}
+
+// AdminUpdateSensitiveWord 修改敏感词的分类和处理方式
+func AdminUpdateSensitiveWord(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+
+	var req service.UpdateSensitiveWordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, response.CodeInvalidParams, "参数错误: "+err.Error())
+		return
+	}
+
+	word, err := GetFilterService().UpdateWord(uint(id), &req)
+	if err != nil {
+		sensitiveWordFail(c, err, "修改敏感词失败")
+		return
+	}
+	response.Success(c, word)
+}
+
+// AdminDeleteSensitiveWord 删除敏感词
+func AdminDeleteSensitiveWord(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err := GetFilterService().DeleteWord(uint(id)); err != nil {
+		sensitiveWordFail(c, err, "删除敏感词失败")
+		return
+	}
+	response.Success(c, nil)
+}
+
+// AdminTestFilter 用当前词库和配置检查一段文本
+func AdminTestFilter(c *gin.Context) {
+	var req service.FilterTestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, response.CodeInvalidParams, "参数错误: "+err.Error())
+		return
+	}
+
+	result := GetFilterService().Test(req.Text)
+	response.Success(c, gin.H{
+		"text":   result.Text,
+		"action": result.Action,
+		"hits":   result.Hits,
+	})
+}
+
+// sensitiveWordFail 敏感词管理错误响应
+func sensitiveWordFail(c *gin.Context, err error, message string) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		response.Fail(c, response.CodeNotFound, "敏感词不存在")
+		return
+	}
+	response.Fail(c, response.CodeServerError, message)
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"niuma-house/internal/middleware"
+	"niuma-house/internal/service"
+	"niuma-house/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetLevels 获取等级列表及各等级权益
+func GetLevels(c *gin.Context) {
+	levels, err := GetLevelService().List()
+	if err != nil {
+		response.Fail(c, response.CodeServerError, "获取等级列表失败")
+		return
+	}
+	response.Success(c, levels)
+}
+
+// GetMyBadges 获取当前用户的徽章
+func GetMyBadges(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+	badges, err := GetLevelService().Badges(userID)
+	if err != nil {
+		response.Fail(c, response.CodeServerError, "获取徽章失败")
+		return
+	}
+	response.Success(c, badges)
+}
+
+// AdminSaveLevel 新增或修改等级
+func AdminSaveLevel(c *gin.Context) {
+	level, err := strconv.Atoi(c.Param("level"))
+	if err != nil || level < 1 {
+		response.Fail(c, response.CodeInvalidParams, "等级参数错误")
+		return
+	}
+
+	var req service.SaveLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, response.CodeInvalidParams, "参数错误: "+err.Error())
+		return
+	}
+
+	saved, err := GetLevelService().Save(level, &req)
+	if err != nil {
+		response.Fail(c, response.CodeInvalidParams, err.Error())
+		return
+	}
+	response.Success(c, saved)
+}
+
+// AdminDeleteLevel 删除等级
+func AdminDeleteLevel(c *gin.Context) {
+	level, err := strconv.Atoi(c.Param("level"))
+	if err != nil {
+		response.Fail(c, response.CodeInvalidParams, "等级参数错误")
+		return
+	}
+
+	if err := GetLevelService().Delete(level); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.Fail(c, response.CodeNotFound, "等级不存在")
+			return
+		}
+		response.Fail(c, response.CodeInvalidParams, err.Error())
+		return
+	}
+	response.Success(c, nil)
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"strconv"
+
+	"niuma-house/pkg/queue"
+	"niuma-house/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminGetDeadLetters 查看死信队列（不会移除消息）
+func AdminGetDeadLetters(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if limit <= 0 {
+		limit = 50
+	}
+
+	letters, total, err := queue.Get().ListDeadLetters(c.Request.Context(), limit)
+	if err != nil {
+		response.Fail(c, response.CodeServerError, "获取死信消息失败: "+err.Error())
+		return
+	}
+
+	response.Success(c, gin.H{
+		"list":  letters,
+		"total": total,
+	})
+}
+
+// ReplayDeadLettersRequest 重放死信请求，message_ids 为空时按 limit 重放队列头部的消息
+type ReplayDeadLettersRequest struct {
+	MessageIDs []string `json:"message_ids"`
+	Limit      int      `json:"limit" binding:"omitempty,min=1,max=200"`
+}
+
+// AdminReplayDeadLetters 重放死信消息
+func AdminReplayDeadLetters(c *gin.Context) {
+	var req ReplayDeadLettersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, response.CodeInvalidParams, "参数错误: "+err.Error())
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = 50
+	}
+
+	replayed, err := queue.Get().ReplayDeadLetters(c.Request.Context(), req.MessageIDs, req.Limit)
+	if err != nil {
+		response.FailWithData(c, response.CodeServerError, "重放死信消息失败: "+err.Error(), gin.H{"replayed": replayed})
+		return
+	}
+
+	response.Success(c, gin.H{"replayed": replayed})
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"strconv"
+
+	"niuma-house/internal/middleware"
+	"niuma-house/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetNotifications 获取通知列表
+func GetNotifications(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
+
+	notifications, total, err := GetNotificationService().List(userID, page, size)
+	if err != nil {
+		response.Fail(c, response.CodeServerError, "获取通知列表失败")
+		return
+	}
+
+	response.Success(c, gin.H{
+		"list":  notifications,
+		"total": total,
+		"page":  page,
+		"size":  size,
+	})
+}
+
+// GetUnreadNotificationCount 获取未读通知数
+func GetUnreadNotificationCount(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+	count := GetNotificationService().GetUnreadCount(userID)
+	response.Success(c, gin.H{"count": count})
+}
+
+// MarkNotificationsAsRead 标记全部通知已读
+func MarkNotificationsAsRead(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+	if err := GetNotificationService().MarkAllAsRead(userID); err != nil {
+		response.Fail(c, response.CodeServerError, "操作失败")
+		return
+	}
+	response.Success(c, nil)
+}
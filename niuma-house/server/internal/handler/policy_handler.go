@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"niuma-house/internal/service"
+	"niuma-house/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminGetPolicies 获取权限策略
+func AdminGetPolicies(c *gin.Context) {
+	list, err := GetPolicyService().List()
+	if err != nil {
+		response.Fail(c, response.CodeServerError, "获取权限策略失败")
+		return
+	}
+	response.Success(c, list)
+}
+
+// AdminAddPolicy 新增权限策略
+func AdminAddPolicy(c *gin.Context) {
+	var req service.PolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, response.CodeInvalidParams, "参数错误: "+err.Error())
+		return
+	}
+
+	if err := GetPolicyService().AddPolicy(&req); err != nil {
+		response.Fail(c, response.CodeServerError, err.Error())
+		return
+	}
+	response.Success(c, nil)
+}
+
+// AdminRemovePolicy 删除权限策略
+func AdminRemovePolicy(c *gin.Context) {
+	var req service.PolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, response.CodeInvalidParams, "参数错误: "+err.Error())
+		return
+	}
+
+	if err := GetPolicyService().RemovePolicy(&req); err != nil {
+		response.Fail(c, response.CodeServerError, err.Error())
+		return
+	}
+	response.Success(c, nil)
+}
+
+// AdminAddRole 新增角色继承
+func AdminAddRole(c *gin.Context) {
+	var req service.RoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, response.CodeInvalidParams, "参数错误: "+err.Error())
+		return
+	}
+
+	if err := GetPolicyService().AddRole(&req); err != nil {
+		response.Fail(c, response.CodeServerError, err.Error())
+		return
+	}
+	response.Success(c, nil)
+}
+
+// AdminRemoveRole 删除角色继承
+func AdminRemoveRole(c *gin.Context) {
+	var req service.RoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, response.CodeInvalidParams, "参数错误: "+err.Error())
+		return
+	}
+
+	if err := GetPolicyService().RemoveRole(&req); err != nil {
+		response.Fail(c, response.CodeServerError, err.Error())
+		return
+	}
+	response.Success(c, nil)
+}
+
+// AdminReloadPolicies 重新加载权限策略
+func AdminReloadPolicies(c *gin.Context) {
+	if err := GetPolicyService().Reload(); err != nil {
+		response.Fail(c, response.CodeServerError, "重新加载失败")
+		return
+	}
+	response.Success(c, nil)
+}
@@ -4,6 +4,8 @@ import (
 	"strconv"
 
 	"niuma-house/internal/middleware"
+	"niuma-house/internal/model"
+	"niuma-house/internal/repository"
 	"niuma-house/internal/service"
 	"niuma-house/pkg/response"
 
@@ -16,7 +18,7 @@ func GetPosts(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
 
-	posts, total, err := GetPostService().List(uint(occupationID), page, size)
+	posts, total, err := GetPostService().List(uint(occupationID), page, size, middleware.GetCurrentUserID(c))
 	if err != nil {
 		response.Fail(c, response.CodeServerError, "获取帖子列表失败")
 		return
@@ -30,12 +32,39 @@ func GetPosts(c *gin.Context) {
 	})
 }
 
+// SearchPosts 搜索帖子，支持按职业分类筛选
+func SearchPosts(c *gin.Context) {
+	keyword := c.Query("keyword")
+	occupationID, _ := strconv.ParseUint(c.Query("occupation_id"), 10, 64)
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
+
+	posts, total, err := GetPostService().Search(c.Request.Context(), &repository.SearchQuery{
+		Keyword:      keyword,
+		OccupationID: uint(occupationID),
+		Page:         page,
+		Size:         size,
+	}, middleware.GetCurrentUserID(c))
+	if err != nil {
+		response.Fail(c, response.CodeServerError, "搜索失败")
+		return
+	}
+
+	response.Success(c, gin.H{
+		"list":    posts,
+		"total":   total,
+		"page":    page,
+		"size":    size,
+		"keyword": keyword,
+	})
+}
+
 // GetPost 获取帖子详情
 func GetPost(c *gin.Context) {
 	id, _ := strconv.ParseUint(c.Param("id"), 10, 64)
 	userID := middleware.GetCurrentUserID(c)
 
-	post, isLiked, isFavorited, err := GetPostService().GetByID(uint(id), userID)
+	post, isLiked, isFavorited, err := GetPostService().GetByID(uint(id), userID, middleware.GetViewerID(c))
 	if err != nil {
 		response.Fail(c, response.CodeNotFound, "帖子不存在")
 		return
@@ -59,10 +88,14 @@ func CreatePost(c *gin.Context) {
 	userID := middleware.GetCurrentUserID(c)
 	post, err := GetPostService().Create(userID, &req)
 	if err != nil {
-		response.Fail(c, response.CodeServerError, err.Error())
+		contentFail(c, err)
 		return
 	}
 
+	if post.Status == model.PostStatusHidden {
+		response.SuccessWithMessage(c, contentHeldMessage, post)
+		return
+	}
 	response.Success(c, post)
 }
 
@@ -77,11 +110,16 @@ func UpdatePost(c *gin.Context) {
 		return
 	}
 
-	if err := GetPostService().Update(uint(id), userID, &req); err != nil {
-		response.Fail(c, response.CodeServerError, err.Error())
+	held, err := GetPostService().Update(uint(id), userID, &req)
+	if err != nil {
+		contentFail(c, err)
 		return
 	}
 
+	if held {
+		response.SuccessWithMessage(c, contentHeldMessage, nil)
+		return
+	}
 	response.Success(c, nil)
 }
 
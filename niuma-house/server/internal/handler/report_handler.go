@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"strconv"
+
+	"niuma-house/internal/middleware"
+	"niuma-house/internal/model"
+	"niuma-house/internal/repository"
+	"niuma-house/internal/service"
+	"niuma-house/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetReportReasons 获取举报理由分类
+func GetReportReasons(c *gin.Context) {
+	response.Success(c, GetReportService().Reasons())
+}
+
+// ReportPost 举报帖子
+func ReportPost(c *gin.Context) {
+	submitReport(c, model.ReportTargetPost)
+}
+
+// ReportComment 举报评论
+func ReportComment(c *gin.Context) {
+	submitReport(c, model.ReportTargetComment)
+}
+
+// ReportCompany 举报公司曝光
+func ReportCompany(c *gin.Context) {
+	submitReport(c, model.ReportTargetCompany)
+}
+
+// ReportUser 举报用户
+func ReportUser(c *gin.Context) {
+	submitReport(c, model.ReportTargetUser)
+}
+
+// ReportMessage 举报收到的私信
+func ReportMessage(c *gin.Context) {
+	submitReport(c, model.ReportTargetMessage)
+}
+
+// submitReport 举报路由参数 :id 指定的内容
+func submitReport(c *gin.Context, targetType string) {
+	targetID, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+	userID := middleware.GetCurrentUserID(c)
+
+	var req service.ReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, response.CodeInvalidParams, "参数错误: "+err.Error())
+		return
+	}
+
+	err := GetReportService().Report(userID, targetType, uint(targetID), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			response.Fail(c, response.CodeNotFound, "内容不存在")
+		case errors.Is(err, repository.ErrAlreadyReported):
+			response.Fail(c, response.CodeConflict, err.Error())
+		case errors.Is(err, service.ErrReportSelf), errors.Is(err, service.ErrInvalidReportReason):
+			response.Fail(c, response.CodeInvalidParams, err.Error())
+		default:
+			response.Fail(c, response.CodeServerError, err.Error())
+		}
+		return
+	}
+
+	response.SuccessWithMessage(c, "举报已提交，我们会尽快处理", nil)
+}
+
+// AdminGetModerationCases 审核队列
+func AdminGetModerationCases(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
+	assigneeID, _ := strconv.ParseUint(c.Query("assignee_id"), 10, 64)
+	if c.Query("mine") == "1" {
+		assigneeID = uint64(middleware.GetCurrentUserID(c))
+	}
+
+	filter := &repository.CaseFilter{
+		Status:     c.Query("status"),
+		TargetType: c.Query("target_type"),
+		AssigneeID: uint(assigneeID),
+	}
+	cases, total, err := GetReportService().ListCases(filter, page, size)
+	if err != nil {
+		response.Fail(c, response.CodeServerError, "获取审核队列失败")
+		return
+	}
+
+	response.Success(c, gin.H{
+		"list":  cases,
+		"total": total,
+		"page":  page,
+		"size":  size,
+	})
+}
+
+// AdminGetModerationCase 工单详情
+func AdminGetModerationCase(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+
+	mc, err := GetReportService().GetCase(uint(id))
+	if err != nil {
+		response.Fail(c, response.CodeNotFound, "工单不存在")
+		return
+	}
+	response.Success(c, mc)
+}
+
+// AdminAssignModerationCase 分配工单，不指定审核员时分配给自己
+func AdminAssignModerationCase(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+
+	var req service.AssignRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.Fail(c, response.CodeInvalidParams, "参数错误: "+err.Error())
+		return
+	}
+
+	if err := GetReportService().Assign(uint(id), middleware.GetCurrentUserID(c), &req); err != nil {
+		moderationFail(c, err)
+		return
+	}
+	response.Success(c, nil)
+}
+
+// AdminResolveModerationCase 结案
+func AdminResolveModerationCase(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+
+	var req service.ResolveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, response.CodeInvalidParams, "参数错误: "+err.Error())
+		return
+	}
+
+	mc, err := GetReportService().Resolve(uint(id), middleware.GetCurrentUserID(c), &req)
+	if err != nil {
+		moderationFail(c, err)
+		return
+	}
+	response.Success(c, mc)
+}
+
+// moderationFail 审核操作的错误响应
+func moderationFail(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		response.Fail(c, response.CodeNotFound, "工单或用户不存在")
+	case errors.Is(err, repository.ErrCaseClosed):
+		response.Fail(c, response.CodeConflict, err.Error())
+	default:
+		response.Fail(c, response.CodeInvalidParams, err.Error())
+	}
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"niuma-house/internal/middleware"
+	"niuma-house/internal/repository"
+	"niuma-house/internal/service"
+	"niuma-house/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// AdminGetSanctions 处罚记录列表，可按用户、类型和是否生效筛选
+func AdminGetSanctions(c *gin.Context) {
+	userID, _ := strconv.ParseUint(c.Query("user_id"), 10, 64)
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
+
+	filter := &repository.SanctionFilter{
+		UserID:     uint(userID),
+		Type:       c.Query("type"),
+		ActiveOnly: c.Query("active") == "1",
+	}
+	sanctions, total, err := GetSanctionService().List(filter, page, size)
+	if err != nil {
+		response.Fail(c, response.CodeServerError, "获取处罚记录失败")
+		return
+	}
+
+	response.Success(c, gin.H{
+		"list":  sanctions,
+		"total": total,
+		"page":  page,
+		"size":  size,
+	})
+}
+
+// AdminIssueSanction 处罚用户（禁言、禁私信或封禁）
+func AdminIssueSanction(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+
+	var req service.IssueSanctionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, response.CodeInvalidParams, "参数错误: "+err.Error())
+		return
+	}
+
+	sanction, err := GetSanctionService().Issue(uint(id), middleware.GetCurrentUserID(c), &req)
+	if err != nil {
+		sanctionFail(c, err, "处罚失败")
+		return
+	}
+	response.Success(c, sanction)
+}
+
+// AdminLiftSanction 提前解除处罚
+func AdminLiftSanction(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err := GetSanctionService().Lift(uint(id), middleware.GetCurrentUserID(c)); err != nil {
+		sanctionFail(c, err, "解除处罚失败")
+		return
+	}
+	response.Success(c, nil)
+}
+
+// sanctionFail 处罚相关错误响应
+func sanctionFail(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		response.Fail(c, response.CodeNotFound, "用户或处罚记录不存在")
+	case errors.Is(err, service.ErrSanctionSelf):
+		response.Fail(c, response.CodeInvalidParams, err.Error())
+	case errors.Is(err, repository.ErrSanctionLifted):
+		response.Fail(c, response.CodeConflict, err.Error())
+	default:
+		response.Fail(c, response.CodeServerError, message)
+	}
+}
@@ -7,17 +7,41 @@ import (
 )
 
 var (
-	userSvc    *service.UserService
-	postSvc    *service.PostService
-	companySvc *service.CompanyService
-	commentSvc *service.CommentService
-	messageSvc *service.MessageService
-
-	userOnce    sync.Once
-	postOnce    sync.Once
-	companyOnce sync.Once
-	commentOnce sync.Once
-	messageOnce sync.Once
+	userSvc     *service.UserService
+	postSvc     *service.PostService
+	companySvc  *service.CompanyService
+	commentSvc  *service.CommentService
+	messageSvc  *service.MessageService
+	policySvc   *service.PolicyService
+	notifySvc   *service.NotificationService
+	reviewSvc   *service.CompanyReviewService
+	blockSvc    *service.BlockService
+	expSvc      *service.ExpService
+	levelSvc    *service.LevelService
+	checkinSvc  *service.CheckinService
+	anonSvc     *service.AnonymousService
+	reportSvc   *service.ReportService
+	auditSvc    *service.AuditService
+	sanctionSvc *service.SanctionService
+	filterSvc   *service.FilterService
+
+	userOnce     sync.Once
+	postOnce     sync.Once
+	companyOnce  sync.Once
+	commentOnce  sync.Once
+	messageOnce  sync.Once
+	policyOnce   sync.Once
+	notifyOnce   sync.Once
+	reviewOnce   sync.Once
+	blockOnce    sync.Once
+	expOnce      sync.Once
+	levelOnce    sync.Once
+	checkinOnce  sync.Once
+	anonOnce     sync.Once
+	reportOnce   sync.Once
+	auditOnce    sync.Once
+	sanctionOnce sync.Once
+	filterOnce   sync.Once
 )
 
 // GetUserService 获取用户服务（懒加载）
@@ -59,3 +83,99 @@ func GetMessageService() *service.MessageService {
 	})
 	return messageSvc
 }
+
+// GetPolicyService 获取权限策略服务（懒加载）
+func GetPolicyService() *service.PolicyService {
+	policyOnce.Do(func() {
+		policySvc = service.NewPolicyService()
+	})
+	return policySvc
+}
+
+// GetNotificationService 获取通知服务（懒加载）
+func GetNotificationService() *service.NotificationService {
+	notifyOnce.Do(func() {
+		notifySvc = service.NewNotificationService()
+	})
+	return notifySvc
+}
+
+// GetCompanyReviewService 获取公司评价服务（懒加载）
+func GetCompanyReviewService() *service.CompanyReviewService {
+	reviewOnce.Do(func() {
+		reviewSvc = service.NewCompanyReviewService()
+	})
+	return reviewSvc
+}
+
+// GetBlockService 获取黑名单服务（懒加载）
+func GetBlockService() *service.BlockService {
+	blockOnce.Do(func() {
+		blockSvc = service.NewBlockService()
+	})
+	return blockSvc
+}
+
+// GetExpService 获取经验值服务（懒加载）
+func GetExpService() *service.ExpService {
+	expOnce.Do(func() {
+		expSvc = service.NewExpService()
+	})
+	return expSvc
+}
+
+// GetLevelService 获取等级服务（懒加载）
+func GetLevelService() *service.LevelService {
+	levelOnce.Do(func() {
+		levelSvc = service.NewLevelService()
+	})
+	return levelSvc
+}
+
+// GetCheckinService 获取签到服务（懒加载）
+func GetCheckinService() *service.CheckinService {
+	checkinOnce.Do(func() {
+		checkinSvc = service.NewCheckinService()
+	})
+	return checkinSvc
+}
+
+// GetAnonymousService 获取匿名内容管理服务（懒加载）
+func GetAnonymousService() *service.AnonymousService {
+	anonOnce.Do(func() {
+		anonSvc = service.NewAnonymousService()
+	})
+	return anonSvc
+}
+
+// GetReportService 获取举报服务（懒加载）
+func GetReportService() *service.ReportService {
+	reportOnce.Do(func() {
+		reportSvc = service.NewReportService()
+	})
+	return reportSvc
+}
+
+// GetAuditService 获取审计日志服务（懒加载）
+func GetAuditService() *service.AuditService {
+	auditOnce.Do(func() {
+		auditSvc = service.NewAuditService()
+	})
+	return auditSvc
+}
+
+// GetSanctionService 获取用户处罚服务（懒加载）
+func GetSanctionService() *service.SanctionService {
+	sanctionOnce.Do(func() {
+		sanctionSvc = service.NewSanctionService()
+	})
+	return sanctionSvc
+}
+
+// GetFilterService 获取内容过滤服务（懒加载）
+func GetFilterService() *service.FilterService {
+	filterOnce.Do(func() {
+		filterSvc = service.NewFilterService()
+	})
+	return filterSvc
+}
@@ -2,8 +2,10 @@ package handler
 
 import (
 	"context"
+	"strings"
 	"time"
 
+	"niuma-house/internal/middleware"
 	"niuma-house/pkg/response"
 	"niuma-house/pkg/storage"
 
@@ -11,6 +13,9 @@ import (
 	"github.com/google/uuid"
 )
 
+// imageExts 支持的图片格式
+var imageExts = map[string]bool{".jpg": true, ".jpeg": true, ".png": true, ".gif": true, ".webp": true}
+
 // GetPresignedURL 获取上传预签名 URL
 func GetPresignedURL(c *gin.Context) {
 	var req struct {
@@ -34,6 +39,19 @@ func GetPresignedURL(c *gin.Context) {
 	}
 	objectName := uuid.New().String() + ext
 
+	// 图片上传需要等级权益
+	if req.FileType == "image" || imageExts[strings.ToLower(ext)] {
+		perks, err := GetLevelService().Perks(middleware.GetCurrentUserID(c))
+		if err != nil {
+			response.Fail(c, response.CodeServerError, "获取等级权益失败")
+			return
+		}
+		if !perks.CanUploadImage {
+			response.Fail(c, response.CodePermissionDeny, "当前等级暂不能上传图片")
+			return
+		}
+	}
+
 	// 生成预签名 URL (有效期 1 小时)
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -80,8 +98,7 @@ func UploadAvatar(c *gin.Context) {
 	}
 
 	// 只允许图片格式
-	allowedExts := map[string]bool{".jpg": true, ".jpeg": true, ".png": true, ".gif": true, ".webp": true}
-	if !allowedExts[ext] {
+	if !imageExts[ext] {
 		response.Fail(c, response.CodeInvalidParams, "只支持 jpg、png、gif、webp 格式的图片")
 		return
 	}
@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"errors"
+	"io"
 	"strconv"
 
 	"niuma-house/internal/middleware"
@@ -45,6 +47,54 @@ func Login(c *gin.Context) {
 	response.Success(c, resp)
 }
 
+// RefreshToken 刷新令牌
+func RefreshToken(c *gin.Context) {
+	var req service.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, response.CodeInvalidParams, "参数错误")
+		return
+	}
+
+	pair, err := GetUserService().Refresh(&req)
+	if err != nil {
+		response.Fail(c, response.CodeTokenInvalid, err.Error())
+		return
+	}
+
+	response.Success(c, pair)
+}
+
+// Logout 退出登录
+func Logout(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+	sessionID := middleware.GetCurrentSessionID(c)
+
+	if err := GetUserService().Logout(userID, sessionID); err != nil {
+		response.Fail(c, response.CodeServerError, "退出登录失败")
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// ChangePassword 修改密码
+func ChangePassword(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+
+	var req service.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, response.CodeInvalidParams, "参数错误: "+err.Error())
+		return
+	}
+
+	if err := GetUserService().ChangePassword(userID, &req); err != nil {
+		response.Fail(c, response.CodeServerError, err.Error())
+		return
+	}
+
+	response.Success(c, nil)
+}
+
 // GetProfile 获取用户资料
 func GetProfile(c *gin.Context) {
 	userID := middleware.GetCurrentUserID(c)
@@ -75,6 +125,44 @@ func UpdateProfile(c *gin.Context) {
 	response.Success(c, nil)
 }
 
+// UpdatePrivacy 更新私信隐私设置
+func UpdatePrivacy(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+
+	var req service.PrivacyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Fail(c, response.CodeInvalidParams, "参数错误: "+err.Error())
+		return
+	}
+
+	if err := GetUserService().UpdatePrivacy(userID, &req); err != nil {
+		response.Fail(c, response.CodeServerError, err.Error())
+		return
+	}
+
+	response.Success(c, nil)
+}
+
+// GetExpHistory 获取经验流水
+func GetExpHistory(c *gin.Context) {
+	userID := middleware.GetCurrentUserID(c)
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	size, _ := strconv.Atoi(c.DefaultQuery("size", "20"))
+
+	entries, total, err := GetExpService().History(userID, page, size)
+	if err != nil {
+		response.Fail(c, response.CodeServerError, "获取经验记录失败")
+		return
+	}
+
+	response.Success(c, gin.H{
+		"list":  entries,
+		"total": total,
+		"page":  page,
+		"size":  size,
+	})
+}
+
 // GetOccupations 获取职业列表
 func GetOccupations(c *gin.Context) {
 	occupations, err := repository.NewOccupationRepository().List()
@@ -104,22 +192,39 @@ func AdminGetUsers(c *gin.Context) {
 	})
 }
 
-// BanUser 封禁用户
+// BanUser 封禁用户，可选填写理由和时长（小时），不填时长为永久封禁
 func BanUser(c *gin.Context) {
 	id, _ := strconv.ParseUint(c.Param("id"), 10, 64)
-	if err := GetUserService().Ban(uint(id)); err != nil {
-		response.Fail(c, response.CodeServerError, "封禁失败")
+
+	var req service.BanRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.Fail(c, response.CodeInvalidParams, "参数错误: "+err.Error())
+		return
+	}
+
+	if _, err := GetSanctionService().Ban(uint(id), middleware.GetCurrentUserID(c), &req); err != nil {
+		sanctionFail(c, err, "封禁失败")
 		return
 	}
 	response.Success(c, nil)
 }
 
-// UnbanUser 解封用户
+// UnbanUser 解封用户，解除其所有生效中的封禁
 func UnbanUser(c *gin.Context) {
 	id, _ := strconv.ParseUint(c.Param("id"), 10, 64)
-	if err := GetUserService().Unban(uint(id)); err != nil {
+	if err := GetSanctionService().Unban(uint(id), middleware.GetCurrentUserID(c)); err != nil {
 		response.Fail(c, response.CodeServerError, "解封失败")
 		return
 	}
 	response.Success(c, nil)
 }
+
+// KickUser 踢用户下线
+func KickUser(c *gin.Context) {
+	id, _ := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err := GetUserService().Kick(uint(id)); err != nil {
+		response.Fail(c, response.CodeServerError, "操作失败")
+		return
+	}
+	response.Success(c, nil)
+}
@@ -0,0 +1,190 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+
+	"niuma-house/internal/model"
+	"niuma-house/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 请求参数最多记录的字节数，超过时只记录理由
+const auditMaxParamsSize = 4096
+
+// 理由最多记录的字符数，与审计日志字段长度一致
+const auditMaxReasonLen = 500
+
+// 响应体最多缓存的字节数，用于读取业务码
+const auditMaxResponseSize = 64 * 1024
+
+// auditRoute 管理端写操作的审计登记
+// table/column 用于读取操作前后的快照，param 为目标 ID 所在的路由参数；bodyTarget 表示目标在请求体中
+type auditRoute struct {
+	action     string
+	targetType string
+	table      string
+	column     string
+	param      string
+	bodyTarget bool
+}
+
+// auditRoutes 按 "方法 路由" 登记的操作，未登记的写操作同样记录，操作名为 "方法 路由"
+var auditRoutes = map[string]auditRoute{
+	"POST /api/admin/users/:id/ban":                      {action: "user.ban", targetType: "user", table: "users", column: "id", param: "id"},
+	"POST /api/admin/users/:id/unban":                    {action: "user.unban", targetType: "user", table: "users", column: "id", param: "id"},
+	"POST /api/admin/users/:id/kick":                     {action: "user.kick", targetType: "user", param: "id"},
+	"POST /api/admin/users/:id/sanctions":                {action: "sanction.issue", targetType: "user", table: "users", column: "id", param: "id"},
+	"POST /api/admin/sanctions/:id/lift":                 {action: "sanction.lift", targetType: "sanction", table: "user_sanctions", column: "id", param: "id"},
+	"POST /api/admin/anonymous/reveal":                   {action: "anonymous.reveal", bodyTarget: true},
+	"PUT /api/admin/levels/:level":                       {action: "level.save", targetType: "level", table: "levels", column: "level", param: "level"},
+	"DELETE /api/admin/levels/:level":                    {action: "level.delete", targetType: "level", table: "levels", column: "level", param: "level"},
+	"DELETE /api/admin/posts/:id":                        {action: "post.delete", targetType: "post", table: "posts", column: "id", param: "id"},
+	"POST /api/admin/posts/:id/top":                      {action: "post.top", targetType: "post", table: "posts", column: "id", param: "id"},
+	"DELETE /api/admin/companies/:id":                    {action: "company.delete", targetType: "company", table: "companies", column: "id", param: "id"},
+	"POST /api/admin/companies/:id/merge":                {action: "company.merge", targetType: "company", table: "companies", column: "id", param: "id"},
+	"DELETE /api/admin/companies/:id/reviews/:review_id": {action: "review.delete", targetType: "review", table: "company_reviews", column: "id", param: "review_id"},
+	"POST /api/admin/moderation/cases/:id/assign":        {action: "moderation.assign", targetType: "moderation_case", table: "moderation_cases", column: "id", param: "id"},
+	"POST /api/admin/moderation/cases/:id/resolve":       {action: "moderation.resolve", targetType: "moderation_case", table: "moderation_cases", column: "id", param: "id"},
+	"POST /api/admin/filter/words":                       {action: "filter.word_add", targetType: "sensitive_word"},
+	"PUT /api/admin/filter/words/:id":                    {action: "filter.word_update", targetType: "sensitive_word", table: "sensitive_words", column: "id", param: "id"},
+	"POST /api/admin/filter/test":                        {action: "filter.test"},
+	"DELETE /api/admin/filter/words/:id":                 {action: "filter.word_delete", targetType: "sensitive_word", table: "sensitive_words", column: "id", param: "id"},
+	"POST /api/admin/mq/dead-letters/replay":             {action: "mq.replay", targetType: "dead_letter"},
+	"POST /api/admin/policies":                           {action: "policy.add", targetType: "policy"},
+	"DELETE /api/admin/policies":                         {action: "policy.remove", targetType: "policy"},
+	"POST /api/admin/policies/roles":                     {action: "policy.add_role", targetType: "policy"},
+	"DELETE /api/admin/policies/roles":                   {action: "policy.remove_role", targetType: "policy"},
+	"POST /api/admin/policies/reload":                    {action: "policy.reload", targetType: "policy"},
+}
+
+// AuditLog 审计中间件，需放在 JWTAuth 和 CasbinAuth 之后
+// 记录每一次写操作的操作者、角色、目标、前后快照、理由、IP 和结果；
+// 理由取自 JSON 请求体的 reason / note 字段，或 URL 编码的 X-Audit-Reason 请求头
+func AuditLog() gin.HandlerFunc {
+	auditRepo := repository.NewAuditRepository()
+
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		route, ok := auditRoutes[c.Request.Method+" "+c.FullPath()]
+		if !ok {
+			route = auditRoute{action: c.Request.Method + " " + c.FullPath()}
+		}
+
+		var body []byte
+		if c.Request.Body != nil {
+			body, _ = io.ReadAll(c.Request.Body)
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		params := auditParams(body)
+
+		entry := &model.AuditLog{
+			ActorID:    GetCurrentUserID(c),
+			Role:       GetCurrentRole(c),
+			Action:     route.action,
+			Method:     c.Request.Method,
+			Path:       c.Request.URL.Path,
+			TargetType: route.targetType,
+			Reason:     auditReason(c, params),
+			IP:         c.ClientIP(),
+		}
+		if route.param != "" {
+			entry.TargetID = c.Param(route.param)
+		}
+		if route.bodyTarget && params["target_type"] != nil && params["target_id"] != nil {
+			entry.TargetType = fmt.Sprint(params["target_type"])
+			entry.TargetID = fmt.Sprint(params["target_id"])
+		}
+		if params != nil && len(body) <= auditMaxParamsSize {
+			entry.Params, _ = json.Marshal(params)
+		}
+
+		snapshot := route.table != "" && entry.TargetID != ""
+		if snapshot {
+			entry.Before, _ = auditRepo.Snapshot(route.table, route.column, entry.TargetID)
+		}
+
+		writer := &auditWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		entry.Status = writer.Status()
+		entry.Code = writer.code()
+		if snapshot && entry.Code == 0 {
+			entry.After, _ = auditRepo.Snapshot(route.table, route.column, entry.TargetID)
+		}
+		if err := auditRepo.Create(entry); err != nil {
+			log.Printf("Failed to write audit log: action=%s, actor=%d, err=%v", entry.Action, entry.ActorID, err)
+		}
+	}
+}
+
+// auditParams 解析 JSON 请求体，去掉敏感字段；非 JSON 对象时返回 nil
+func auditParams(body []byte) map[string]any {
+	if len(body) == 0 {
+		return nil
+	}
+	var params map[string]any
+	if err := json.Unmarshal(body, &params); err != nil {
+		return nil
+	}
+	delete(params, "password")
+	return params
+}
+
+// auditReason 操作理由，请求体中的理由优先
+func auditReason(c *gin.Context, params map[string]any) string {
+	reason, _ := url.QueryUnescape(c.GetHeader("X-Audit-Reason"))
+	for _, key := range []string{"reason", "note"} {
+		if v, ok := params[key].(string); ok && v != "" {
+			reason = v
+			break
+		}
+	}
+	if runes := []rune(reason); len(runes) > auditMaxReasonLen {
+		reason = string(runes[:auditMaxReasonLen])
+	}
+	return reason
+}
+
+// auditWriter 缓存响应体以读取业务码
+type auditWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *auditWriter) Write(data []byte) (int, error) {
+	if w.body.Len() < auditMaxResponseSize {
+		w.body.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *auditWriter) WriteString(s string) (int, error) {
+	if w.body.Len() < auditMaxResponseSize {
+		w.body.WriteString(s)
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+// code 响应中的业务码，无法解析时返回 -1
+func (w *auditWriter) code() int {
+	var resp struct {
+		Code *int `json:"code"`
+	}
+	if err := json.Unmarshal(w.body.Bytes(), &resp); err != nil || resp.Code == nil {
+		return -1
+	}
+	return *resp.Code
+}
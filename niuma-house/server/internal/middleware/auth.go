@@ -1,9 +1,11 @@
 package middleware
 
 import (
+	"fmt"
 	"strings"
 
 	"niuma-house/pkg/jwt"
+	"niuma-house/pkg/rbac"
 	"niuma-house/pkg/response"
 
 	"github.com/gin-gonic/gin"
@@ -36,34 +38,53 @@ func JWTAuth() gin.HandlerFunc {
 		}
 
 		claims, err := jwt.ParseToken(tokenString)
-		if err != nil {
+		if err != nil || claims.TokenType != jwt.TokenTypeAccess {
 			response.Unauthorized(c, "Token 无效或已过期")
 			c.Abort()
 			return
 		}
 
+		// 检查会话是否已被注销（登出、封禁、改密码、踢下线）
+		valid, err := jwt.ValidateSession(claims)
+		if err != nil {
+			response.ServerError(c, "会话校验失败")
+			c.Abort()
+			return
+		}
+		if !valid {
+			response.Unauthorized(c, jwt.ErrSessionRevoked.Error())
+			c.Abort()
+			return
+		}
+
 		// 将用户信息存入上下文
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
 		c.Set("role", claims.Role)
+		c.Set("session_id", claims.SessionID)
 
 		c.Next()
 	}
 }
 
-// AdminAuth 管理员权限中间件
-func AdminAuth() gin.HandlerFunc {
+// CasbinAuth Casbin 权限中间件，按 (角色, 路径, 方法) 校验 RBAC 策略
+func CasbinAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		role, exists := c.Get("role")
-		if !exists {
+		role := GetCurrentRole(c)
+		if role == "" {
 			response.Forbidden(c, "请先登录")
 			c.Abort()
 			return
 		}
 
-		roleStr := role.(string)
-		if roleStr != "admin" && roleStr != "super_admin" && roleStr != "content_admin" {
-			response.Forbidden(c, "无管理员权限")
+		allowed, err := rbac.Enforce(role, c.Request.URL.Path, c.Request.Method)
+		if err != nil {
+			response.ServerError(c, "权限校验失败")
+			c.Abort()
+			return
+		}
+		if !allowed {
+			response.Forbidden(c, "无权访问该资源")
 			c.Abort()
 			return
 		}
@@ -98,3 +119,20 @@ func GetCurrentRole(c *gin.Context) string {
 	}
 	return role.(string)
 }
+
+// GetCurrentSessionID 获取当前会话 ID
+func GetCurrentSessionID(c *gin.Context) string {
+	sessionID, exists := c.Get("session_id")
+	if !exists {
+		return ""
+	}
+	return sessionID.(string)
+}
+
+// GetViewerID 获取访客标识（登录用户优先，否则使用 IP），用于浏览量去重
+func GetViewerID(c *gin.Context) string {
+	if userID := GetCurrentUserID(c); userID > 0 {
+		return fmt.Sprintf("u:%d", userID)
+	}
+	return "ip:" + c.ClientIP()
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"niuma-house/internal/repository"
+	"niuma-house/pkg/ratelimit"
+	"niuma-house/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 接口限流规则，Limit 为 Lv.1 用户的配额，按等级放宽
+var (
+	RateLoginRule    = ratelimit.Rule{Name: "login", IPLimit: 10, Window: time.Minute}
+	RateRegisterRule = ratelimit.Rule{Name: "register", IPLimit: 5, Window: time.Hour}
+	RatePostRule     = ratelimit.Rule{Name: "post", Limit: 5, IPLimit: 20, Window: 10 * time.Minute}
+	RateCommentRule  = ratelimit.Rule{Name: "comment", Limit: 10, IPLimit: 30, Window: time.Minute}
+	RateCompanyRule  = ratelimit.Rule{Name: "company", Limit: 3, IPLimit: 10, Window: time.Hour}
+	RateReviewRule   = ratelimit.Rule{Name: "review", Limit: 10, IPLimit: 30, Window: time.Hour}
+	RateLikeRule     = ratelimit.Rule{Name: "like", Limit: 30, IPLimit: 100, Window: time.Minute}
+	RateUploadRule   = ratelimit.Rule{Name: "upload", Limit: 20, IPLimit: 50, Window: time.Hour}
+	RateReportRule   = ratelimit.Rule{Name: "report", Limit: 10, IPLimit: 30, Window: time.Hour}
+)
+
+// RateLimit 限流中间件，同时按 IP 和当前用户计数，任一超限即返回 429
+// 用户配额按等级放宽；未登录的请求只按 IP 限流
+func RateLimit(rule ratelimit.Rule) gin.HandlerFunc {
+	userRepo := repository.NewUserRepository()
+
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		if rule.IPLimit > 0 {
+			key := fmt.Sprintf("%s:ip:%s", rule.Name, c.ClientIP())
+			if res := ratelimit.Allow(ctx, key, rule.IPLimit, rule.Window); !res.Allowed {
+				response.TooManyRequests(c, res.RetryAfter)
+				c.Abort()
+				return
+			}
+		}
+
+		if userID := GetCurrentUserID(c); rule.Limit > 0 && userID > 0 {
+			level, err := userRepo.GetLevel(userID)
+			if err != nil {
+				level = 1
+			}
+			key := fmt.Sprintf("%s:u:%d", rule.Name, userID)
+			res := ratelimit.Allow(ctx, key, ratelimit.ScaleByLevel(rule.Limit, level), rule.Window)
+			c.Header("X-RateLimit-Limit", strconv.Itoa(res.Limit))
+			c.Header("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			if !res.Allowed {
+				response.TooManyRequests(c, res.RetryAfter)
+				c.Abort()
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
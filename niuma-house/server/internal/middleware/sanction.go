@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+
+	"niuma-house/internal/model"
+	"niuma-house/internal/repository"
+	"niuma-house/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// 处罚提示文案
+var sanctionMessages = map[string]string{
+	model.SanctionMutePost: "你已被禁言",
+	model.SanctionMuteDM:   "你已被禁止发送私信",
+	model.SanctionBan:      "账号已被封禁",
+}
+
+// NotSanctioned 处罚校验中间件，需放在 JWTAuth 之后
+// 当前用户有限制 sanctionType 的生效处罚（含封禁）时拒绝请求，data 中返回该处罚
+func NotSanctioned(sanctionType string) gin.HandlerFunc {
+	sanctionRepo := repository.NewSanctionRepository()
+
+	return func(c *gin.Context) {
+		sanction, err := sanctionRepo.FindActive(GetCurrentUserID(c), sanctionType)
+		if err != nil {
+			// 查询失败时放行，避免数据库抖动导致所有写操作不可用
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				log.Printf("Failed to check sanctions: userID=%d, err=%v", GetCurrentUserID(c), err)
+			}
+			c.Next()
+			return
+		}
+
+		message := sanctionMessages[sanction.Type]
+		if sanction.ExpiresAt != nil {
+			message += "，解除时间 " + sanction.ExpiresAt.Format("2006-01-02 15:04")
+		}
+		response.FailWithData(c, response.CodeUserSanctioned, message, sanction)
+		c.Abort()
+	}
+}
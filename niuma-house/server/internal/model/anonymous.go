@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// AnonymousAuthor 匿名内容对外展示的作者信息
+// 同一主题（帖子及其评论、公司及其评价）内同一用户的化名保持不变，只暴露职业和等级区间
+type AnonymousAuthor struct {
+	Pseudonym   string `json:"pseudonym"`
+	Occupation  string `json:"occupation,omitempty"`
+	LevelBucket string `json:"level_bucket"` // 如 Lv.1-2
+}
+
+// 可查看真实作者的匿名内容类型
+const (
+	AnonymousTargetPost    = "post"
+	AnonymousTargetComment = "comment"
+	AnonymousTargetCompany = "company"
+	AnonymousTargetReview  = "review"
+)
+
+// AnonymousReveal 管理员查看匿名内容真实作者的审计记录
+type AnonymousReveal struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	AdminID    uint      `gorm:"not null;index" json:"admin_id"`
+	Admin      *User     `gorm:"foreignKey:AdminID" json:"admin,omitempty"`
+	TargetType string    `gorm:"size:20;not null;index:idx_reveal_target" json:"target_type"`
+	TargetID   uint      `gorm:"not null;index:idx_reveal_target" json:"target_id"`
+	AuthorID   uint      `gorm:"not null" json:"author_id"`
+	Reason     string    `gorm:"size:500;not null" json:"reason"`
+	IP         string    `gorm:"size:64" json:"ip"`
+	CreatedAt  time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName 表名
+func (AnonymousReveal) TableName() string {
+	return "anonymous_reveals"
+}
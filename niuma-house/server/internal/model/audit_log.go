@@ -0,0 +1,32 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditLog 管理端操作审计日志，记录每一次 /api/admin 下的写操作
+type AuditLog struct {
+	ID         uint            `gorm:"primaryKey" json:"id"`
+	ActorID    uint            `gorm:"not null;index" json:"actor_id"`
+	Actor      *User           `gorm:"foreignKey:ActorID" json:"actor,omitempty"`
+	Role       string          `gorm:"size:20" json:"role"`
+	Action     string          `gorm:"size:50;not null;index" json:"action"` // 如 user.ban、post.delete，未登记的路由为 "方法 路径"
+	Method     string          `gorm:"size:10" json:"method"`
+	Path       string          `gorm:"size:255" json:"path"`
+	TargetType string          `gorm:"size:30;index:idx_audit_target" json:"target_type"`
+	TargetID   string          `gorm:"size:64;index:idx_audit_target" json:"target_id"`
+	Before     json.RawMessage `gorm:"type:json" json:"before,omitempty"` // 操作前的目标快照
+	After      json.RawMessage `gorm:"type:json" json:"after,omitempty"`  // 操作后的目标快照
+	Params     json.RawMessage `gorm:"type:json" json:"params,omitempty"` // 请求参数（JSON 请求体）
+	Reason     string          `gorm:"size:500" json:"reason"`
+	IP         string          `gorm:"size:64" json:"ip"`
+	Status     int             `json:"status"` // HTTP 状态码
+	Code       int             `json:"code"`   // 业务码，0 表示成功
+	CreatedAt  time.Time       `gorm:"index" json:"created_at"`
+}
+
+// TableName 表名
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
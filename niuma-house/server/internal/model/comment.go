@@ -8,20 +8,35 @@ import (
 
 // Comment 评论实体
 type Comment struct {
-	ID        uint           `gorm:"primaryKey" json:"id"`
-	PostID    uint           `gorm:"not null;index" json:"post_id"`
-	Post      *Post          `gorm:"foreignKey:PostID" json:"post,omitempty"`
-	UserID    uint           `gorm:"not null;index" json:"user_id"`
-	User      *User          `gorm:"foreignKey:UserID" json:"user,omitempty"`
-	Content   string         `gorm:"type:text;not null" json:"content"`
-	ParentID  *uint          `gorm:"index" json:"parent_id,omitempty"` // 回复的评论ID
-	Status    int            `gorm:"default:1" json:"status"`          // 1: 正常, 0: 删除
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID            uint           `gorm:"primaryKey" json:"id"`
+	PostID        uint           `gorm:"not null;index" json:"post_id"`
+	Post          *Post          `gorm:"foreignKey:PostID" json:"post,omitempty"`
+	UserID        uint           `gorm:"not null;index" json:"user_id,omitempty"` // 匿名评论对外置零
+	User          *User          `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Content       string         `gorm:"type:text;not null" json:"content"`
+	ParentID      *uint          `gorm:"index" json:"parent_id,omitempty"` // 回复的评论ID
+	RootID        *uint          `gorm:"index" json:"root_id,omitempty"`   // 所属一级评论ID，一级评论为空
+	ReplyToUserID *uint          `json:"reply_to_user_id,omitempty"`       // 被回复的用户ID
+	ReplyToAnon   bool           `gorm:"not null;default:false" json:"-"`  // 被回复的评论是否匿名
+	ReplyToUser   *User          `gorm:"foreignKey:ReplyToUserID" json:"reply_to_user,omitempty"`
+	Status        int            `gorm:"default:1" json:"status"` // 1: 正常, 0: 删除, -1: 举报隐藏
+	Anonymous     bool           `gorm:"not null;default:false" json:"anonymous"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+
+	ReplyCount int64     `gorm:"-" json:"reply_count"`       // 回复数（仅一级评论）
+	Replies    []Comment `gorm:"-" json:"replies,omitempty"` // 回复预览（仅一级评论）
+
+	Author        *AnonymousAuthor `gorm:"-" json:"author,omitempty"`          // 匿名作者信息，替代 User
+	ReplyToAuthor *AnonymousAuthor `gorm:"-" json:"reply_to_author,omitempty"` // 被回复者匿名时替代 ReplyToUser
+	IsMine        bool             `gorm:"-" json:"is_mine,omitempty"`
 }
 
 // TableName 表名
 func (Comment) TableName() string {
 	return "comments"
 }
+
+// CommentStatusHidden 被举报达到阈值后自动隐藏，审核驳回后恢复
+const CommentStatusHidden = -1
@@ -4,7 +4,10 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
+	"regexp"
+	"strings"
 	"time"
+	"unicode"
 
 	"gorm.io/gorm"
 )
@@ -33,22 +36,56 @@ func (s StringArray) Value() (driver.Value, error) {
 	return json.Marshal(s)
 }
 
+// TagCounts 标签计数类型 (JSON存储)
+type TagCounts map[string]int
+
+// Scan 实现 sql.Scanner 接口
+func (t *TagCounts) Scan(value interface{}) error {
+	if value == nil {
+		*t = TagCounts{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to unmarshal TagCounts value")
+	}
+	return json.Unmarshal(bytes, t)
+}
+
+// Value 实现 driver.Valuer 接口
+func (t TagCounts) Value() (driver.Value, error) {
+	if t == nil {
+		return "{}", nil
+	}
+	return json.Marshal(t)
+}
+
 // Company 坑逼公司实体
 type Company struct {
-	ID        uint           `gorm:"primaryKey" json:"id"`
-	Name      string         `gorm:"size:100;not null;index" json:"name"`
-	City      string         `gorm:"size:50" json:"city"`
-	Tags      StringArray    `gorm:"type:json" json:"tags"`       // ["拖欠工资", "暴力裁员"]
-	RiskLevel int            `gorm:"default:1" json:"risk_level"` // 1-5 星避雷等级
-	Evidence  StringArray    `gorm:"type:json" json:"evidence"`   // 证据图片 MinIO Keys
-	Content   string         `gorm:"type:text" json:"content"`    // 详细描述
-	CreatorID uint           `gorm:"not null" json:"creator_id"`
-	Creator   *User          `gorm:"foreignKey:CreatorID" json:"creator,omitempty"`
-	Status    int            `gorm:"default:1;index" json:"status"` // 1: 正常, 0: 删除
-	ViewCount int            `gorm:"default:0" json:"view_count"`
-	CreatedAt time.Time      `gorm:"index" json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID             uint           `gorm:"primaryKey" json:"id"`
+	Name           string         `gorm:"size:100;not null;index" json:"name"`
+	NormalizedName string         `gorm:"size:100;index" json:"-"` // 归一化名称，用于查重
+	City           string         `gorm:"size:50" json:"city"`
+	Tags           StringArray    `gorm:"type:json" json:"tags"`       // 按评价次数排序的标签 ["拖欠工资", "暴力裁员"]
+	RiskLevel      int            `gorm:"default:1" json:"risk_level"` // 1-5 星避雷等级（评价平均值取整）
+	AvgRisk        float64        `gorm:"default:0;index" json:"avg_risk"`
+	TagCounts      TagCounts      `gorm:"type:json" json:"tag_counts"` // {"拖欠工资": 3}
+	SearchTags     string         `gorm:"size:500" json:"-"`           // 空格拼接的标签，供全文索引使用
+	ReviewCount    int            `gorm:"default:0" json:"review_count"`
+	Evidence       StringArray    `gorm:"type:json" json:"evidence"`            // 证据图片 MinIO Keys
+	Content        string         `gorm:"type:text" json:"content"`             // 详细描述
+	CreatorID      uint           `gorm:"not null" json:"creator_id,omitempty"` // 匿名曝光对外置零
+	Creator        *User          `gorm:"foreignKey:CreatorID" json:"creator,omitempty"`
+	Status         int            `gorm:"default:1;index" json:"status"`         // 1: 正常, 0: 删除, 2: 已合并, -1: 举报隐藏
+	MergedIntoID   *uint          `gorm:"index" json:"merged_into_id,omitempty"` // 合并后的目标公司
+	ViewCount      int            `gorm:"default:0" json:"view_count"`
+	Anonymous      bool           `gorm:"not null;default:false" json:"anonymous"`
+	CreatedAt      time.Time      `gorm:"index" json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+
+	Author *AnonymousAuthor `gorm:"-" json:"author,omitempty"` // 匿名曝光者信息，替代 Creator
+	IsMine bool             `gorm:"-" json:"is_mine,omitempty"`
 }
 
 // TableName 表名
@@ -56,6 +93,69 @@ func (Company) TableName() string {
 	return "companies"
 }
 
+// 公司状态
+const (
+	CompanyStatusDeleted = 0
+	CompanyStatusNormal  = 1
+	CompanyStatusMerged  = 2
+	CompanyStatusHidden  = -1 // 被举报达到阈值后自动隐藏，审核驳回后恢复
+)
+
+// BeforeSave 保存前钩子 - 维护归一化名称和全文索引标签
+func (c *Company) BeforeSave(tx *gorm.DB) error {
+	c.NormalizedName = NormalizeCompanyName(c.Name)
+	c.SearchTags = strings.Join(c.Tags, " ")
+	return nil
+}
+
+// companyNameBrackets 名称中的括号部分，通常是城市或分公司 如 "(北京)"
+var companyNameBrackets = regexp.MustCompile(`[(（\[【][^)）\]】]*[)）\]】]`)
+
+// companyNameSuffixes 常见的公司组织形式后缀，按长度从长到短匹配
+var companyNameSuffixes = []string{
+	"股份有限公司",
+	"有限责任公司",
+	"集团有限公司",
+	"有限公司",
+	"集团公司",
+	"分公司",
+	"公司",
+	"集团",
+}
+
+// NormalizeCompanyName 归一化公司名称：全角转半角、去括号、去空白和组织形式后缀
+// 例如 "某某科技(北京)有限公司" -> "某某科技"
+func NormalizeCompanyName(name string) string {
+	// 全角转半角并转小写
+	name = strings.Map(func(r rune) rune {
+		if r == '\u3000' {
+			return ' '
+		}
+		if r >= '\uFF01' && r <= '\uFF5E' {
+			r -= 0xFEE0
+		}
+		return unicode.ToLower(r)
+	}, name)
+
+	name = companyNameBrackets.ReplaceAllString(name, "")
+
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || unicode.IsPunct(r) {
+			return -1
+		}
+		return r
+	}, name)
+
+	for _, suffix := range companyNameSuffixes {
+		if trimmed := strings.TrimSuffix(name, suffix); trimmed != name && trimmed != "" {
+			name = trimmed
+			break
+		}
+	}
+
+	return name
+}
+
 // 预置避雷标签
 var DefaultCompanyTags = []string{
 	"拖欠工资",
@@ -69,3 +169,13 @@ var DefaultCompanyTags = []string{
 	"画大饼",
 	"钱少事多",
 }
+
+// IsValidCompanyTag 是否为预置避雷标签
+func IsValidCompanyTag(tag string) bool {
+	for _, t := range DefaultCompanyTags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package model
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CompanyReview 公司评价实体，每个用户对同一公司只保留一条评价
+type CompanyReview struct {
+	ID        uint        `gorm:"primaryKey" json:"id"`
+	CompanyID uint        `gorm:"not null;uniqueIndex:idx_review_company_user" json:"company_id"`
+	UserID    uint        `gorm:"not null;uniqueIndex:idx_review_company_user;index" json:"user_id,omitempty"` // 匿名评价对外置零
+	User      *User       `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	RiskLevel int         `gorm:"not null" json:"risk_level"` // 1-5 星避雷等级
+	Tags      StringArray `gorm:"type:json" json:"tags"`
+	Content   string      `gorm:"type:text" json:"content"`
+	Evidence  StringArray `gorm:"type:json" json:"evidence"`     // 证据图片 MinIO Keys
+	Status    int         `gorm:"default:1;index" json:"status"` // 1: 正常, 0: 删除
+	Anonymous bool        `gorm:"not null;default:false" json:"anonymous"`
+	CreatedAt time.Time   `gorm:"index" json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+
+	Author *AnonymousAuthor `gorm:"-" json:"author,omitempty"` // 匿名评价者信息，替代 User
+	IsMine bool             `gorm:"-" json:"is_mine,omitempty"`
+}
+
+// TableName 表名
+func (CompanyReview) TableName() string {
+	return "company_reviews"
+}
+
+// ReviewAggregate 公司评价聚合结果
+type ReviewAggregate struct {
+	ReviewCount int
+	AvgRisk     float64
+	RiskLevel   int
+	TagCounts   TagCounts
+	Tags        StringArray // 按出现次数降序
+}
+
+// AggregateReviews 根据有效评价计算公司的避雷等级和标签统计
+func AggregateReviews(reviews []CompanyReview) ReviewAggregate {
+	agg := ReviewAggregate{
+		TagCounts: TagCounts{},
+		Tags:      StringArray{},
+		RiskLevel: 1,
+	}
+
+	total := 0
+	for _, review := range reviews {
+		total += review.RiskLevel
+		for _, tag := range review.Tags {
+			agg.TagCounts[tag]++
+		}
+	}
+
+	agg.ReviewCount = len(reviews)
+	if agg.ReviewCount > 0 {
+		agg.AvgRisk = math.Round(float64(total)/float64(agg.ReviewCount)*100) / 100
+		agg.RiskLevel = int(math.Round(agg.AvgRisk))
+	}
+
+	for tag := range agg.TagCounts {
+		agg.Tags = append(agg.Tags, tag)
+	}
+	sort.Slice(agg.Tags, func(i, j int) bool {
+		ci, cj := agg.TagCounts[agg.Tags[i]], agg.TagCounts[agg.Tags[j]]
+		if ci != cj {
+			return ci > cj
+		}
+		return agg.Tags[i] < agg.Tags[j]
+	})
+
+	return agg
+}
+
+// Columns 聚合结果对应的公司表字段
+func (a ReviewAggregate) Columns() map[string]interface{} {
+	return map[string]interface{}{
+		"review_count": a.ReviewCount,
+		"avg_risk":     a.AvgRisk,
+		"risk_level":   a.RiskLevel,
+		"tag_counts":   a.TagCounts,
+		"tags":         a.Tags,
+		"search_tags":  strings.Join(a.Tags, " "),
+	}
+}
@@ -0,0 +1,33 @@
+package model
+
+import "testing"
+
+func TestNormalizeCompanyName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"华为技术有限公司", "华为技术"},
+		{"腾讯科技(深圳)有限公司", "腾讯科技"},
+		{"腾讯科技（深圳）有限公司", "腾讯科技"},
+		{"【上海】拼多多有限公司", "拼多多"},
+		{"中国平安保险（集团）股份有限公司", "中国平安保险"},
+		{"北京字节跳动科技有限公司", "北京字节跳动科技"},
+		{"字节跳动科技有限公司", "字节跳动科技"},
+		{"网易（杭州）网络有限公司", "网易网络"},
+		{"小米科技有限责任公司", "小米科技"},
+		{"阿里巴巴集团控股有限公司", "阿里巴巴集团控股"},
+		{"美团 - 北京分公司", "美团北京"},
+		{"京东集团", "京东"},
+		{"万科企业股份有限公司", "万科企业"},
+		{"ＡＢＣ　Ｔｅｃｈ有限公司", "abctech"},
+		{"Apple Inc.", "appleinc"},
+		{"公司", "公司"}, // 整个名称都是后缀时保留
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeCompanyName(tt.name); got != tt.want {
+			t.Errorf("NormalizeCompanyName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// ExpLedger 经验值流水，每条记录对应一次 (用户, 行为, 对象) 的经验发放
+// IdemKey 唯一，重复投递的消息不会重复加经验；撤销后同一对象不会再次发放
+type ExpLedger struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	UserID    uint       `gorm:"not null;index:idx_exp_ledger_daily,priority:1" json:"user_id"`
+	Action    string     `gorm:"size:20;not null;index:idx_exp_ledger_daily,priority:3" json:"action"`
+	Subject   string     `gorm:"size:64;not null" json:"subject"` // 来源对象，如 post:1、post:1:like:2、comment:3
+	IdemKey   string     `gorm:"size:120;not null;uniqueIndex" json:"-"`
+	Requested int        `gorm:"not null" json:"requested"` // 行为对应的经验值，为 0 表示先于发放到达的撤销占位
+	Amount    int        `gorm:"not null" json:"amount"`    // 实际发放的经验值，超出每日上限的部分不发放
+	Day       string     `gorm:"size:10;not null;index:idx_exp_ledger_daily,priority:2" json:"day"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `gorm:"index" json:"created_at"`
+}
+
+// TableName 表名
+func (ExpLedger) TableName() string {
+	return "exp_ledger"
+}
+
+// ExpIdemKey 经验流水的幂等键
+func ExpIdemKey(userID uint, action, subject string) string {
+	return fmt.Sprintf("%d:%s:%s", userID, action, subject)
+}
@@ -0,0 +1,73 @@
+package model
+
+import "time"
+
+// Level 等级配置，按经验值阈值划分，管理员可增删改
+// 每个等级附带权益：每日发帖数、私信权限、图片上传，以及达到该等级时授予的徽章
+type Level struct {
+	Level          int       `gorm:"primaryKey;autoIncrement:false" json:"level"`
+	Name           string    `gorm:"size:50;not null" json:"name"`
+	MinExp         int       `gorm:"not null;uniqueIndex" json:"min_exp"`
+	DailyPostQuota int       `gorm:"default:0" json:"daily_post_quota"` // 每日发帖上限，0 表示不限
+	CanMessage     bool      `gorm:"not null" json:"can_message"`       // 是否可以发私信
+	CanUploadImage bool      `gorm:"not null" json:"can_upload_image"`
+	BadgeName      string    `gorm:"size:50" json:"badge_name"` // 为空表示不授予徽章
+	BadgeIcon      string    `gorm:"size:255" json:"badge_icon"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// TableName 表名
+func (Level) TableName() string {
+	return "levels"
+}
+
+// DefaultLevels 预置等级
+var DefaultLevels = []Level{
+	{Level: 1, Name: "普通牛马", MinExp: 0, DailyPostQuota: 5, CanMessage: true},
+	{Level: 2, Name: "内卷牛马", MinExp: 100, DailyPostQuota: 10, CanMessage: true, CanUploadImage: true, BadgeName: "初露锋芒"},
+	{Level: 3, Name: "精英牛马", MinExp: 500, DailyPostQuota: 20, CanMessage: true, CanUploadImage: true, BadgeName: "职场精英"},
+	{Level: 4, Name: "天选牛马", MinExp: 2000, DailyPostQuota: 50, CanMessage: true, CanUploadImage: true, BadgeName: "天选之人"},
+	{Level: 5, Name: "核动力牛马", MinExp: 10000, CanMessage: true, CanUploadImage: true, BadgeName: "核动力"},
+}
+
+// LevelForExp 根据经验值计算等级，levels 需按 MinExp 升序排列
+func LevelForExp(levels []Level, exp int) int {
+	level := 1
+	for _, l := range levels {
+		if exp < l.MinExp {
+			break
+		}
+		level = l.Level
+	}
+	return level
+}
+
+// FindLevel 查找用户所处等级的配置（等级被删除时取不高于它的最近一级）
+func FindLevel(levels []Level, level int) *Level {
+	var found *Level
+	for i := range levels {
+		if levels[i].Level > level {
+			break
+		}
+		found = &levels[i]
+	}
+	if found == nil && len(levels) > 0 {
+		found = &levels[0]
+	}
+	return found
+}
+
+// UserBadge 用户获得的徽章，每个等级的徽章只授予一次
+type UserBadge struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_badge_user_level" json:"user_id"`
+	Level     int       `gorm:"not null;uniqueIndex:idx_badge_user_level" json:"level"`
+	Name      string    `gorm:"size:50;not null" json:"name"`
+	Icon      string    `gorm:"size:255" json:"icon"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName 表名
+func (UserBadge) TableName() string {
+	return "user_badges"
+}
@@ -4,14 +4,17 @@ import "time"
 
 // Message 私信实体
 type Message struct {
-	ID         uint      `gorm:"primaryKey" json:"id"`
-	SenderID   uint      `gorm:"not null;index" json:"sender_id"`
-	Sender     *User     `gorm:"foreignKey:SenderID" json:"sender,omitempty"`
-	ReceiverID uint      `gorm:"not null;index" json:"receiver_id"`
-	Receiver   *User     `gorm:"foreignKey:ReceiverID" json:"receiver,omitempty"`
-	Content    string    `gorm:"type:text;not null" json:"content"`
-	IsRead     bool      `gorm:"default:false" json:"is_read"`
-	CreatedAt  time.Time `gorm:"index" json:"created_at"`
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	SenderID    uint       `gorm:"not null;index;uniqueIndex:idx_message_sender_client,priority:1" json:"sender_id"`
+	Sender      *User      `gorm:"foreignKey:SenderID" json:"sender,omitempty"`
+	ReceiverID  uint       `gorm:"not null;index" json:"receiver_id"`
+	Receiver    *User      `gorm:"foreignKey:ReceiverID" json:"receiver,omitempty"`
+	Content     string     `gorm:"type:text;not null" json:"content"`
+	ClientMsgID *string    `gorm:"size:64;uniqueIndex:idx_message_sender_client" json:"client_msg_id,omitempty"` // 客户端生成的去重键
+	IsRead      bool       `gorm:"default:false" json:"is_read"`
+	DeliveredAt *time.Time `json:"delivered_at,omitempty"` // 接收方确认送达时间
+	ReadAt      *time.Time `json:"read_at,omitempty"`
+	CreatedAt   time.Time  `gorm:"index" json:"created_at"`
 }
 
 // TableName 表名
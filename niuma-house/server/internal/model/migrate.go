@@ -2,6 +2,7 @@ package model
 
 import (
 	"log"
+	"strings"
 
 	"gorm.io/gorm"
 )
@@ -15,8 +16,21 @@ func AutoMigrate(db *gorm.DB) error {
 		&PostLike{},
 		&PostFavorite{},
 		&Company{},
+		&CompanyReview{},
 		&Comment{},
 		&Message{},
+		&Notification{},
+		&UserBlock{},
+		&ExpLedger{},
+		&OutboxEvent{},
+		&Level{},
+		&UserBadge{},
+		&AnonymousReveal{},
+		&Report{},
+		&ModerationCase{},
+		&AuditLog{},
+		&UserSanction{},
+		&SensitiveWord{},
 	)
 	if err != nil {
 		return err
@@ -24,7 +38,14 @@ func AutoMigrate(db *gorm.DB) error {
 
 	// 初始化预置数据
 	initOccupations(db)
+	initLevels(db)
 	initAdminUser(db)
+	backfillCommentRoots(db)
+	backfillCompanyReviews(db)
+	backfillCompanyNormalizedNames(db)
+	backfillCompanySearchTags(db)
+	backfillBanSanctions(db)
+	ensureFullTextIndexes(db)
 
 	log.Println("Database migration completed")
 	return nil
@@ -42,17 +63,32 @@ func initOccupations(db *gorm.DB) {
 	log.Println("Default occupations initialized")
 }
 
-// initAdminUser 初始化管理员账号
+// initLevels 等级表为空时写入预置等级，之后由管理员维护
+func initLevels(db *gorm.DB) {
+	var count int64
+	db.Model(&Level{}).Count(&count)
+	if count == 0 {
+		levels := append([]Level(nil), DefaultLevels...)
+		db.Create(&levels)
+		log.Println("Default levels initialized")
+	}
+}
+
+// initAdminUser 初始化管理员账号，等级为当前配置的最高等级
 func initAdminUser(db *gorm.DB) {
 	var admin User
 	result := db.Where("username = ?", "admin").First(&admin)
 	if result.Error == gorm.ErrRecordNotFound {
+		var top Level
+		if err := db.Order("min_exp DESC").First(&top).Error; err != nil {
+			top = Level{Level: 1}
+		}
 		admin = User{
 			Username:     "admin",
 			Password:     "admin123", // 会在 BeforeCreate 中加密
 			OccupationID: 1,
-			Level:        5,
-			Exp:          99999,
+			Level:        top.Level,
+			Exp:          top.MinExp,
 			Role:         "super_admin",
 			Status:       1,
 		}
@@ -60,3 +96,100 @@ func initAdminUser(db *gorm.DB) {
 		log.Println("Admin user created: admin / admin123")
 	}
 }
+
+// backfillCommentRoots 为旧版回复补齐 root_id（旧数据只有一层回复）
+func backfillCommentRoots(db *gorm.DB) {
+	db.Model(&Comment{}).
+		Where("parent_id IS NOT NULL AND root_id IS NULL").
+		UpdateColumn("root_id", gorm.Expr("parent_id"))
+}
+
+// backfillCompanyReviews 旧数据的避雷信息由创建者填写，转为创建者的评价并计算聚合值
+func backfillCompanyReviews(db *gorm.DB) {
+	var companies []Company
+	db.Where("review_count = 0").Find(&companies)
+
+	for _, company := range companies {
+		var count int64
+		db.Model(&CompanyReview{}).Where("company_id = ?", company.ID).Count(&count)
+		if count == 0 {
+			db.Create(&CompanyReview{
+				CompanyID: company.ID,
+				UserID:    company.CreatorID,
+				RiskLevel: company.RiskLevel,
+				Tags:      company.Tags,
+				Content:   company.Content,
+				Evidence:  company.Evidence,
+				Status:    1,
+				CreatedAt: company.CreatedAt,
+			})
+		}
+
+		var reviews []CompanyReview
+		db.Where("company_id = ? AND status = 1", company.ID).Find(&reviews)
+		db.Model(&Company{}).Where("id = ?", company.ID).
+			UpdateColumns(AggregateReviews(reviews).Columns())
+	}
+}
+
+// backfillCompanyNormalizedNames 补齐旧数据的归一化名称
+func backfillCompanyNormalizedNames(db *gorm.DB) {
+	var companies []Company
+	db.Select("id", "name").Where("normalized_name IS NULL OR normalized_name = ''").Find(&companies)
+
+	for _, company := range companies {
+		db.Model(&Company{}).Where("id = ?", company.ID).
+			UpdateColumn("normalized_name", NormalizeCompanyName(company.Name))
+	}
+}
+
+// backfillCompanySearchTags 补齐旧数据的全文索引标签
+func backfillCompanySearchTags(db *gorm.DB) {
+	var companies []Company
+	db.Select("id", "tags").Where("search_tags IS NULL OR search_tags = ''").Find(&companies)
+
+	for _, company := range companies {
+		if len(company.Tags) == 0 {
+			continue
+		}
+		db.Model(&Company{}).Where("id = ?", company.ID).
+			UpdateColumn("search_tags", strings.Join(company.Tags, " "))
+	}
+}
+
+// backfillBanSanctions 旧版封禁只修改了账号状态，为其补一条永久封禁记录，便于统一解除
+func backfillBanSanctions(db *gorm.DB) {
+	var userIDs []uint
+	db.Model(&User{}).Where("status = 0").
+		Where("id NOT IN (?)", db.Model(&UserSanction{}).Select("user_id").
+			Where("type = ? AND lifted_at IS NULL", SanctionBan)).
+		Pluck("id", &userIDs)
+
+	for _, userID := range userIDs {
+		db.Create(&UserSanction{UserID: userID, Type: SanctionBan, Reason: "历史封禁"})
+	}
+}
+
+// fullTextIndexes 全文索引（ngram 分词，支持中文）
+var fullTextIndexes = []struct {
+	table   string
+	name    string
+	columns string
+}{
+	{table: "posts", name: "ft_posts_title_content", columns: "title, content"},
+	{table: "companies", name: "ft_companies_search", columns: "name, city, content, search_tags"},
+}
+
+// ensureFullTextIndexes 创建全文索引，已存在时跳过
+func ensureFullTextIndexes(db *gorm.DB) {
+	for _, idx := range fullTextIndexes {
+		if db.Migrator().HasIndex(idx.table, idx.name) {
+			continue
+		}
+		err := db.Exec("ALTER TABLE " + idx.table + " ADD FULLTEXT INDEX " + idx.name +
+			" (" + idx.columns + ") WITH PARSER ngram").Error
+		if err != nil {
+			log.Printf("Failed to create fulltext index %s: %v", idx.name, err)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+// 通知类型
+const (
+	NotificationReply   = "reply"    // 评论被回复
+	NotificationComment = "comment"  // 帖子被评论
+	NotificationLike    = "like"     // 帖子被点赞
+	NotificationLevelUp = "level_up" // 等级提升
+)
+
+// Notification 站内通知实体
+type Notification struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null;index" json:"user_id"` // 接收者
+	Type      string    `gorm:"size:30;not null" json:"type"`
+	ActorID   uint      `json:"actor_id"` // 触发者
+	Actor     *User     `gorm:"foreignKey:ActorID" json:"actor,omitempty"`
+	PostID    uint      `json:"post_id,omitempty"`
+	CommentID uint      `json:"comment_id,omitempty"`
+	Content   string    `gorm:"size:500" json:"content"`
+	IsRead    bool      `gorm:"default:false;index" json:"is_read"`
+	DedupeKey *string   `gorm:"size:150;uniqueIndex" json:"-"` // 幂等键，重复投递的通知不会重复写入和推送
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
+// NotificationDedupeKey 通知的幂等键，subject 为触发通知的对象（如评论、点赞、升级到的等级）
+func NotificationDedupeKey(userID uint, notificationType, subject string) string {
+	return fmt.Sprintf("%d:%s:%s", userID, notificationType, subject)
+}
+
+// TableName 表名
+func (Notification) TableName() string {
+	return "notifications"
+}
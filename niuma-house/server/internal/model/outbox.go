@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// Outbox 事件状态
+const (
+	OutboxPending = 0 // 待发送
+	OutboxSent    = 1 // 已发送
+	OutboxHeld    = 2 // 所属内容待审核，工单驳回（内容恢复）后转为待发送
+)
+
+// OutboxEvent 事务性发件箱，与业务数据在同一事务中写入，由中继任务发布到消息队列
+type OutboxEvent struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	RoutingKey    string     `gorm:"size:50;not null" json:"routing_key"`
+	Payload       string     `gorm:"type:text;not null" json:"payload"`
+	Status        int        `gorm:"default:0;index:idx_outbox_pending,priority:1" json:"status"`
+	Attempts      int        `gorm:"default:0" json:"attempts"`
+	LastError     string     `gorm:"size:500" json:"last_error,omitempty"`
+	NextAttemptAt time.Time  `gorm:"index:idx_outbox_pending,priority:2" json:"next_attempt_at"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+	CaseID        *uint      `gorm:"index" json:"case_id,omitempty"` // 暂存事件所属的送审工单
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// TableName 表名
+func (OutboxEvent) TableName() string {
+	return "outbox_events"
+}
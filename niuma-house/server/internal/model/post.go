@@ -9,7 +9,7 @@ import (
 // Post 博客帖子实体
 type Post struct {
 	ID           uint           `gorm:"primaryKey" json:"id"`
-	UserID       uint           `gorm:"not null;index" json:"user_id"`
+	UserID       uint           `gorm:"not null;index" json:"user_id,omitempty"` // 匿名帖子对外置零
 	User         *User          `gorm:"foreignKey:UserID" json:"user,omitempty"`
 	OccupationID uint           `gorm:"not null;index" json:"occupation_id"`
 	Occupation   *Occupation    `gorm:"foreignKey:OccupationID" json:"occupation,omitempty"`
@@ -17,10 +17,14 @@ type Post struct {
 	Content      string         `gorm:"type:text;not null" json:"content"`
 	LikesCount   int            `gorm:"default:0" json:"likes_count"`
 	ViewsCount   int            `gorm:"default:0" json:"views_count"`
-	Status       int            `gorm:"default:1;index" json:"status"` // 1: 正常, 0: 删除, 2: 置顶
+	Status       int            `gorm:"default:1;index" json:"status"` // 1: 正常, 0: 删除, 2: 置顶, -1: 举报隐藏
+	Anonymous    bool           `gorm:"not null;default:false" json:"anonymous"`
 	CreatedAt    time.Time      `gorm:"index" json:"created_at"`
 	UpdatedAt    time.Time      `json:"updated_at"`
 	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+
+	Author *AnonymousAuthor `gorm:"-" json:"author,omitempty"`  // 匿名作者信息，替代 User
+	IsMine bool             `gorm:"-" json:"is_mine,omitempty"` // 当前用户是否为作者
 }
 
 // TableName 表名
@@ -28,6 +32,9 @@ func (Post) TableName() string {
 	return "posts"
 }
 
+// PostStatusHidden 被举报达到阈值后自动隐藏，审核驳回后恢复原状态
+const PostStatusHidden = -1
+
 // PostLike 帖子点赞记录
 type PostLike struct {
 	ID        uint      `gorm:"primaryKey" json:"id"`
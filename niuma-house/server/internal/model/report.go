@@ -0,0 +1,108 @@
+package model
+
+import "time"
+
+// 可举报的内容类型
+const (
+	ReportTargetPost    = "post"
+	ReportTargetComment = "comment"
+	ReportTargetCompany = "company"
+	ReportTargetUser    = "user"
+	ReportTargetMessage = "message"
+)
+
+// ReportReasons 举报理由分类
+var ReportReasons = []ReportReason{
+	{Code: "spam", Name: "广告引流"},
+	{Code: "abuse", Name: "辱骂攻击"},
+	{Code: "porn", Name: "色情低俗"},
+	{Code: "illegal", Name: "违法违规"},
+	{Code: "privacy", Name: "泄露隐私"},
+	{Code: "fake", Name: "不实信息"},
+	{Code: "other", Name: "其他"},
+}
+
+// ReportReason 举报理由
+type ReportReason struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+// 审核状态
+const (
+	ModerationPending  = "pending"  // 待处理
+	ModerationApproved = "approved" // 举报属实，内容隐藏
+	ModerationRejected = "rejected" // 举报不成立，恢复被自动隐藏的内容
+	ModerationActioned = "actioned" // 举报属实并已处置（删除内容或封禁作者）
+)
+
+// ModerationReasonFilter 内容过滤要求人工审核时记入工单的理由，不在用户可选的举报理由中
+const ModerationReasonFilter = "filter"
+
+// 处置方式
+const (
+	ModerationActionDelete = "delete" // 删除内容
+	ModerationActionBan    = "ban"    // 封禁作者
+)
+
+// Report 用户举报记录，同一用户对同一内容只能举报一次
+type Report struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	CaseID     uint      `gorm:"not null;index" json:"case_id"`
+	ReporterID uint      `gorm:"not null;uniqueIndex:idx_report_reporter_target,priority:1" json:"reporter_id"`
+	Reporter   *User     `gorm:"foreignKey:ReporterID" json:"reporter,omitempty"`
+	TargetType string    `gorm:"size:20;not null;uniqueIndex:idx_report_reporter_target,priority:2" json:"target_type"`
+	TargetID   uint      `gorm:"not null;uniqueIndex:idx_report_reporter_target,priority:3" json:"target_id"`
+	Reason     string    `gorm:"size:20;not null" json:"reason"`
+	Detail     string    `gorm:"size:500" json:"detail"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TableName 表名
+func (Report) TableName() string {
+	return "reports"
+}
+
+// ModerationCase 审核工单，同一内容的举报汇总到一个工单
+type ModerationCase struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	TargetType    string     `gorm:"size:20;not null;uniqueIndex:idx_case_target" json:"target_type"`
+	TargetID      uint       `gorm:"not null;uniqueIndex:idx_case_target" json:"target_id"`
+	TargetUserID  uint       `gorm:"not null;index" json:"target_user_id,omitempty"` // 被举报内容的作者（举报用户时为其本人），匿名内容对外置零
+	TargetUser    *User      `gorm:"foreignKey:TargetUserID" json:"target_user,omitempty"`
+	Anonymous     bool       `gorm:"not null;default:false" json:"anonymous"` // 被举报内容是否匿名，真实作者需通过审计接口查看
+	Snapshot      string     `gorm:"type:text" json:"snapshot"`               // 首次被举报时的内容快照，内容过滤送审时更新为最新内容
+	Status        string     `gorm:"size:20;not null;index" json:"status"`
+	ReportCount   int        `gorm:"not null;default:0;index" json:"report_count"`
+	ReviewedCount int        `gorm:"not null;default:0" json:"-"`            // 上次审核时的举报数，之后的举报重新计入自动隐藏阈值
+	Reasons       TagCounts  `gorm:"type:json" json:"reasons"`               // 各举报理由的次数 {"spam": 3}
+	FilterHits    string     `gorm:"size:1000" json:"filter_hits,omitempty"` // 内容过滤最近一次命中的分类和词，送审时填写
+	Hidden        bool       `gorm:"not null;default:false" json:"hidden"`
+	HiddenStatus  int        `gorm:"not null;default:0" json:"-"` // 隐藏前的内容状态，恢复时使用
+	AssigneeID    *uint      `gorm:"index" json:"assignee_id,omitempty"`
+	Assignee      *User      `gorm:"foreignKey:AssigneeID" json:"assignee,omitempty"`
+	ResolverID    *uint      `json:"resolver_id,omitempty"`
+	Resolver      *User      `gorm:"foreignKey:ResolverID" json:"resolver,omitempty"`
+	Action        string     `gorm:"size:20" json:"action,omitempty"`
+	Note          string     `gorm:"size:1000" json:"note"` // 处理说明
+	ResolvedAt    *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt     time.Time  `gorm:"index" json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+
+	Reports []Report `gorm:"foreignKey:CaseID" json:"reports,omitempty"`
+}
+
+// TableName 表名
+func (ModerationCase) TableName() string {
+	return "moderation_cases"
+}
+
+// IsValidReportReason 是否为预置举报理由
+func IsValidReportReason(code string) bool {
+	for _, r := range ReportReasons {
+		if r.Code == code {
+			return true
+		}
+	}
+	return false
+}
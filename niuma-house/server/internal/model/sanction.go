@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// 处罚类型
+const (
+	SanctionMutePost = "mute_post" // 禁言：可以浏览，不能发帖、评论、创建公司、提交评价和上传
+	SanctionMuteDM   = "mute_dm"   // 禁私信：不能发送私信
+	SanctionBan      = "ban"       // 封禁：账号不可登录，同时包含以上限制
+)
+
+// UserSanction 用户处罚记录，同一用户可同时有多条生效的处罚
+type UserSanction struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	UserID    uint       `gorm:"not null;index:idx_sanction_user_lifted" json:"user_id"`
+	User      *User      `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Type      string     `gorm:"size:20;not null" json:"type"`
+	Reason    string     `gorm:"size:500;not null" json:"reason"`
+	ExpiresAt *time.Time `gorm:"index" json:"expires_at"` // 为空表示永久
+	IssuerID  uint       `gorm:"not null;index" json:"issuer_id"`
+	Issuer    *User      `gorm:"foreignKey:IssuerID" json:"issuer,omitempty"`
+	LiftedAt  *time.Time `gorm:"index:idx_sanction_user_lifted" json:"lifted_at,omitempty"` // 到期自动解除或被管理员提前解除的时间
+	LiftedBy  *uint      `json:"lifted_by,omitempty"`                                       // 提前解除的管理员，到期解除时为空
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TableName 表名
+func (UserSanction) TableName() string {
+	return "user_sanctions"
+}
+
+// Active 处罚在 now 时是否生效
+func (s *UserSanction) Active(now time.Time) bool {
+	return s.LiftedAt == nil && (s.ExpiresAt == nil || s.ExpiresAt.After(now))
+}
+
+// Restricts 处罚是否限制 sanctionType 对应的行为，封禁包含所有限制
+func (s *UserSanction) Restricts(sanctionType string) bool {
+	return s.Type == sanctionType || s.Type == SanctionBan
+}
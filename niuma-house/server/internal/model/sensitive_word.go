@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// SensitiveWord 敏感词词库，由管理员维护
+type SensitiveWord struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Word      string    `gorm:"size:100;not null;uniqueIndex" json:"word"`
+	Category  string    `gorm:"size:20;not null;default:'other';index" json:"category"` // 分类，如 abuse、porn、ad
+	Action    string    `gorm:"size:10;not null" json:"action"`                         // 命中后的处理：mask 打码，review 隐藏待审核，reject 拒绝发布
+	CreatorID uint      `gorm:"not null" json:"creator_id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 表名
+func (SensitiveWord) TableName() string {
+	return "sensitive_words"
+}
@@ -9,20 +9,24 @@ import (
 
 // User 用户实体
 type User struct {
-	ID           uint           `gorm:"primaryKey" json:"id"`
-	Username     string         `gorm:"uniqueIndex;size:50;not null" json:"username"`
-	Nickname     string         `gorm:"size:50" json:"nickname"`
-	Avatar       string         `gorm:"size:255" json:"avatar"`
-	Password     string         `gorm:"size:255;not null" json:"-"`
-	OccupationID uint           `gorm:"not null" json:"occupation_id"`
-	Occupation   *Occupation    `gorm:"foreignKey:OccupationID" json:"occupation,omitempty"`
-	Level        int            `gorm:"default:1" json:"level"`
-	Exp          int            `gorm:"default:0" json:"exp"`
-	Role         string         `gorm:"size:20;default:'user'" json:"role"` // user, admin, super_admin
-	Status       int            `gorm:"default:1" json:"status"`            // 1: 正常, 0: 封禁
-	CreatedAt    time.Time      `json:"created_at"`
-	UpdatedAt    time.Time      `json:"updated_at"`
-	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+	ID              uint           `gorm:"primaryKey" json:"id"`
+	Username        string         `gorm:"uniqueIndex;size:50;not null" json:"username"`
+	Nickname        string         `gorm:"size:50" json:"nickname"`
+	Avatar          string         `gorm:"size:255" json:"avatar"`
+	Password        string         `gorm:"size:255;not null" json:"-"`
+	OccupationID    uint           `gorm:"not null" json:"occupation_id"`
+	Occupation      *Occupation    `gorm:"foreignKey:OccupationID" json:"occupation,omitempty"`
+	Level           int            `gorm:"default:1" json:"level"`
+	Exp             int            `gorm:"default:0" json:"exp"`
+	Role            string         `gorm:"size:20;default:'user'" json:"role"`                // user, admin, super_admin
+	Status          int            `gorm:"default:1" json:"status"`                           // 1: 正常, 0: 封禁
+	MessagePrivacy  string         `gorm:"size:20;default:'everyone'" json:"message_privacy"` // everyone, level, nobody
+	MessageMinLevel int            `gorm:"default:2" json:"message_min_level"`                // message_privacy 为 level 时发送者的最低等级
+	LevelName       string         `gorm:"-" json:"level_name,omitempty"`                     // 等级称号，由服务层按等级配置填充
+	Sanctions       []UserSanction `gorm:"-" json:"sanctions,omitempty"`                      // 当前生效的处罚，仅在本人资料中填充
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 // TableName 表名
@@ -46,45 +50,35 @@ func (u *User) CheckPassword(password string) bool {
 	return err == nil
 }
 
-// LevelName 等级名称
-func (u *User) LevelName() string {
-	return GetLevelName(u.Level)
-}
-
-// 等级阈值配置
-var LevelThresholds = map[int]int{
-	1: 0,     // 普通牛马
-	2: 100,   // 内卷牛马
-	3: 500,   // 精英牛马
-	4: 2000,  // 天选牛马
-	5: 10000, // 核动力牛马
+// SetPassword 设置新密码（加密后写入 Password 字段）
+func (u *User) SetPassword(password string) error {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	u.Password = string(hashedPassword)
+	return nil
 }
 
-// LevelNames 等级名称
-var LevelNames = map[int]string{
-	1: "普通牛马",
-	2: "内卷牛马",
-	3: "精英牛马",
-	4: "天选牛马",
-	5: "核动力牛马",
-}
+// 私信隐私设置
+const (
+	MessagePrivacyEveryone = "everyone" // 所有人可发私信
+	MessagePrivacyLevel    = "level"    // 仅达到指定等级的用户
+	MessagePrivacyNobody   = "nobody"   // 不接收私信
+)
 
-// GetLevelName 获取等级名称
-func GetLevelName(level int) string {
-	if name, ok := LevelNames[level]; ok {
-		return name
+// AcceptsMessageFrom 按隐私设置判断是否接收 sender 的私信（不含黑名单判断）
+// 管理员不受隐私设置限制
+func (u *User) AcceptsMessageFrom(sender *User) bool {
+	if sender.Role != "user" {
+		return true
 	}
-	return "普通牛马"
-}
-
-// CalculateLevel 根据经验值计算等级
-func CalculateLevel(exp int) int {
-	level := 1
-	for l := 5; l >= 1; l-- {
-		if exp >= LevelThresholds[l] {
-			level = l
-			break
-		}
+	switch u.MessagePrivacy {
+	case MessagePrivacyNobody:
+		return false
+	case MessagePrivacyLevel:
+		return sender.Level >= u.MessageMinLevel
+	default:
+		return true
 	}
-	return level
 }
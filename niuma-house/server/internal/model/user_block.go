@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// UserBlock 用户黑名单，被拉黑的用户无法给 UserID 发私信
+type UserBlock struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_block_user_blocked" json:"user_id"`
+	BlockedID uint      `gorm:"not null;uniqueIndex:idx_block_user_blocked;index" json:"blocked_id"`
+	Blocked   *User     `gorm:"foreignKey:BlockedID" json:"blocked,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName 表名
+func (UserBlock) TableName() string {
+	return "user_blocks"
+}
@@ -1,82 +1,162 @@
 package mq
 
 import (
-	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
+	"strings"
+	"time"
 
 	"niuma-house/internal/model"
+	"niuma-house/internal/repository"
+	"niuma-house/internal/ws"
 	"niuma-house/pkg/database"
 	"niuma-house/pkg/queue"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// dailyExpCaps 各行为每日可获得的经验上限，超出部分只记流水不发放
+var dailyExpCaps = map[string]int{
+	ActionLogin:     15, // 连续签到最高奖励
+	ActionPost:      50,
+	ActionLiked:     50,
+	ActionCommented: 30,
+	ActionReplied:   30,
+}
+
+// classify 将无法通过重试解决的错误标记为永久性错误（记录已不存在），直接转入死信队列
+func classify(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return queue.Permanent(err)
+	}
+	return err
+}
+
 // StartExpConsumer 启动经验值消费者
 func StartExpConsumer() {
-	ch := queue.GetChannel()
-
-	msgs, err := ch.Consume(
-		"exp_queue", // queue
-		"",          // consumer
-		false,       // auto-ack
-		false,       // exclusive
-		false,       // no-local
-		false,       // no-wait
-		nil,         // args
-	)
-	if err != nil {
-		log.Fatalf("Failed to register consumer: %v", err)
-	}
+	Subscribe("exp_queue", processExpMessage)
+}
 
-	log.Println("Exp consumer started, waiting for messages...")
+// processExpMessage 处理经验值消息：按幂等键记流水、执行每日上限，升级时写入升级事件
+func processExpMessage(msg ExpMessage) error {
+	repo := repository.NewExpRepository()
 
-	for msg := range msgs {
-		var expMsg ExpMessage
-		if err := json.Unmarshal(msg.Body, &expMsg); err != nil {
-			log.Printf("Failed to unmarshal message: %v", err)
-			msg.Nack(false, false)
-			continue
-		}
+	if msg.Subject == "" {
+		// 兼容未携带来源对象的旧消息
+		msg.Subject = fmt.Sprintf("ts:%d", msg.Timestamp)
+	}
+	day := time.Unix(msg.Timestamp, 0).Format("2006-01-02")
 
-		if err := processExpMessage(expMsg); err != nil {
-			log.Printf("Failed to process exp message: %v", err)
-			msg.Nack(false, true) // requeue
-			continue
-		}
+	var result *repository.ExpResult
+	var err error
+	if msg.Revoke {
+		result, err = repo.Revoke(msg.UserID, msg.Action, msg.Subject, day)
+	} else {
+		result, err = repo.Grant(&model.ExpLedger{
+			UserID:    msg.UserID,
+			Action:    msg.Action,
+			Subject:   msg.Subject,
+			IdemKey:   model.ExpIdemKey(msg.UserID, msg.Action, msg.Subject),
+			Requested: msg.ExpAmount,
+			Day:       day,
+		}, dailyExpCaps[msg.Action], LevelUpEvents)
+	}
+	if err != nil {
+		return err
+	}
 
-		msg.Ack(false)
-		log.Printf("Processed exp message: userID=%d, action=%s, exp=+%d",
-			expMsg.UserID, expMsg.Action, expMsg.ExpAmount)
+	if !result.Applied {
+		log.Printf("Skipped duplicate exp message: userID=%d, action=%s, subject=%s", msg.UserID, msg.Action, msg.Subject)
+		return nil
+	}
+	log.Printf("Processed exp message: userID=%d, action=%s, subject=%s, exp=%+d",
+		msg.UserID, msg.Action, msg.Subject, result.Amount)
+
+	// 升级事件已随流水写入 outbox
+	if result.NewLevel != result.OldLevel {
+		log.Printf("User %d level changed: %d -> %d", msg.UserID, result.OldLevel, result.NewLevel)
 	}
+	return nil
 }
 
-// processExpMessage 处理经验值消息
-func processExpMessage(msg ExpMessage) error {
-	db := database.GetDB()
+// StartLevelConsumer 启动升级事件消费者：授予徽章并发送升级通知
+func StartLevelConsumer() {
+	Subscribe("level_queue", processLevelUp)
+}
 
-	return db.Transaction(func(tx *gorm.DB) error {
-		// 更新经验值
-		if err := tx.Model(&model.User{}).
-			Where("id = ?", msg.UserID).
-			UpdateColumn("exp", gorm.Expr("exp + ?", msg.ExpAmount)).Error; err != nil {
-			return err
-		}
+// processLevelUp 为跨越的每个等级授予徽章（重复投递时不会重复授予），并通知用户
+func processLevelUp(msg LevelUpMessage) error {
+	levels, err := repository.NewLevelRepository().List()
+	if err != nil {
+		return err
+	}
+	badgeRepo := repository.NewBadgeRepository()
 
-		// 获取更新后的用户
-		var user model.User
-		if err := tx.First(&user, msg.UserID).Error; err != nil {
+	var badges []string
+	for _, l := range levels {
+		if l.Level <= msg.OldLevel || l.Level > msg.NewLevel || l.BadgeName == "" {
+			continue
+		}
+		awarded, err := badgeRepo.Award(&model.UserBadge{
+			UserID: msg.UserID,
+			Level:  l.Level,
+			Name:   l.BadgeName,
+			Icon:   l.BadgeIcon,
+		})
+		if err != nil {
 			return err
 		}
-
-		// 计算新等级
-		newLevel := model.CalculateLevel(user.Exp)
-		if newLevel != user.Level {
-			if err := tx.Model(&user).Update("level", newLevel).Error; err != nil {
-				return err
-			}
-			log.Printf("User %d level up: %d -> %d", msg.UserID, user.Level, newLevel)
+		if awarded {
+			badges = append(badges, "「"+l.BadgeName+"」")
 		}
+	}
 
-		return nil
+	content := fmt.Sprintf("恭喜升级到 Lv.%d", msg.NewLevel)
+	if level := model.FindLevel(levels, msg.NewLevel); level != nil {
+		content += " " + level.Name
+	}
+	if len(badges) > 0 {
+		content += "，获得徽章" + strings.Join(badges, "")
+	}
+	return PublishNotification(NotificationMessage{
+		UserID:    msg.UserID,
+		Type:      model.NotificationLevelUp,
+		Content:   content,
+		DedupeKey: model.NotificationDedupeKey(msg.UserID, model.NotificationLevelUp, LevelSubject(msg.NewLevel)),
 	})
 }
+
+// StartNotificationConsumer 启动通知消费者：持久化通知并推送给在线用户
+func StartNotificationConsumer() {
+	Subscribe("notification_queue", processNotification)
+}
+
+// processNotification 处理通知消息，幂等键已存在（重复投递）时不再写入和推送
+func processNotification(msg NotificationMessage) error {
+	notification := &model.Notification{
+		UserID:    msg.UserID,
+		Type:      msg.Type,
+		ActorID:   msg.ActorID,
+		PostID:    msg.PostID,
+		CommentID: msg.CommentID,
+		Content:   msg.Content,
+	}
+	if msg.DedupeKey != "" {
+		notification.DedupeKey = &msg.DedupeKey
+	}
+
+	result := database.GetDB().Clauses(clause.OnConflict{DoNothing: true}).Create(notification)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		log.Printf("Skipped duplicate notification: userID=%d, key=%s", msg.UserID, msg.DedupeKey)
+		return nil
+	}
+
+	// 实时推送给在线设备，离线时通过通知列表获取
+	ws.GetHub().Notify(msg.UserID, notification)
+	return nil
+}
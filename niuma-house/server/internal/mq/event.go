@@ -0,0 +1,52 @@
+package mq
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"niuma-house/pkg/queue"
+)
+
+// 发布超时
+const publishTimeout = 5 * time.Second
+
+// Event 可通过消息队列投递的事件，按 RoutingKey 路由到对应队列
+// 新增事件类型时实现该接口，并在 queue.Bindings 中登记队列
+type Event interface {
+	RoutingKey() string
+}
+
+// RoutingKey 经验值事件路由
+func (ExpMessage) RoutingKey() string { return "exp" }
+
+// RoutingKey 通知事件路由
+func (NotificationMessage) RoutingKey() string { return "notification" }
+
+// RoutingKey 升级事件路由
+func (LevelUpMessage) RoutingKey() string { return "level_up" }
+
+// Publish 序列化并发布事件，返回 nil 表示队列后端已可靠接收
+func Publish(event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+
+	return queue.Get().Publish(ctx, event.RoutingKey(), body)
+}
+
+// Subscribe 持续消费队列中的 T 类型事件（阻塞调用）
+// 无法解析的消息直接转入死信，handle 返回错误时由队列后端重试
+func Subscribe[T Event](queueName string, handle func(T) error) {
+	queue.Get().Consume(queueName, func(body []byte) error {
+		var event T
+		if err := json.Unmarshal(body, &event); err != nil {
+			return queue.Permanent(err)
+		}
+		return classify(handle(event))
+	})
+}
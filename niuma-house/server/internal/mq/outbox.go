@@ -0,0 +1,48 @@
+package mq
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"niuma-house/internal/model"
+	"niuma-house/internal/repository"
+	"niuma-house/pkg/queue"
+)
+
+// outbox 中继参数
+const (
+	outboxPollInterval = time.Second
+	outboxBatchSize    = 100
+	// 领取租约，覆盖一批事件全部发布超时的最坏情况
+	outboxClaimLease = outboxBatchSize * publishTimeout
+)
+
+// StartOutboxRelay 启动 outbox 中继：轮询待发送事件，发布到 user_activity 交换机后标记为已发送
+// 发布成功但标记失败时事件会被再次发布，消费端按幂等键去重
+func StartOutboxRelay() {
+	repo := repository.NewOutboxRepository()
+	ticker := time.NewTicker(outboxPollInterval)
+	defer ticker.Stop()
+
+	log.Println("Outbox relay started")
+	for range ticker.C {
+		// 一批发满时立即继续，尽快清空积压
+		for {
+			sent, err := repo.Relay(outboxBatchSize, outboxClaimLease, publishOutboxEvent)
+			if err != nil {
+				log.Printf("Outbox relay failed: %v", err)
+			}
+			if err != nil || sent < outboxBatchSize {
+				break
+			}
+		}
+	}
+}
+
+// publishOutboxEvent 发布单条 outbox 事件并等待 broker 确认
+func publishOutboxEvent(event *model.OutboxEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+	return queue.Get().Publish(ctx, event.RoutingKey, []byte(event.Payload))
+}
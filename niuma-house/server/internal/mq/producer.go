@@ -1,14 +1,12 @@
 package mq
 
 import (
-	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"time"
 
-	"niuma-house/pkg/queue"
-
-	amqp "github.com/rabbitmq/amqp091-go"
+	"niuma-house/internal/model"
 )
 
 // 活动类型
@@ -17,51 +15,135 @@ const (
 	ActionPost      = "post"
 	ActionLiked     = "liked"
 	ActionCommented = "commented"
+	ActionReplied   = "replied"
 )
 
 // ExpMessage 经验值消息
+// (UserID, Action, Subject) 构成幂等键，Revoke 为 true 时撤销该来源发放的经验
 type ExpMessage struct {
 	UserID    uint   `json:"user_id"`
 	Action    string `json:"action"`
+	Subject   string `json:"subject"`
 	ExpAmount int    `json:"exp_amount"`
+	Revoke    bool   `json:"revoke,omitempty"`
 	Timestamp int64  `json:"timestamp"`
 }
 
-// PublishExpMessage 发布经验值消息
-func PublishExpMessage(userID uint, action string, expAmount int) error {
-	ch := queue.GetChannel()
+// PostSubject 发帖经验的来源对象
+func PostSubject(postID uint) string {
+	return fmt.Sprintf("post:%d", postID)
+}
+
+// LikeSubject 被点赞经验的来源对象，同一用户对同一帖子只计一次
+func LikeSubject(postID, likerID uint) string {
+	return fmt.Sprintf("post:%d:like:%d", postID, likerID)
+}
+
+// CommentSubject 被评论/被回复经验的来源对象
+func CommentSubject(commentID uint) string {
+	return fmt.Sprintf("comment:%d", commentID)
+}
+
+// CheckinSubject 签到经验的来源对象，每天一次
+func CheckinSubject(day time.Time) string {
+	return "checkin:" + day.Format("2006-01-02")
+}
 
-	msg := ExpMessage{
+// NotificationMessage 通知消息
+type NotificationMessage struct {
+	UserID    uint   `json:"user_id"` // 接收者
+	Type      string `json:"type"`
+	ActorID   uint   `json:"actor_id"`
+	PostID    uint   `json:"post_id"`
+	CommentID uint   `json:"comment_id"`
+	Content   string `json:"content"`
+	DedupeKey string `json:"dedupe_key,omitempty"` // 幂等键，见 model.NotificationDedupeKey
+	Timestamp int64  `json:"timestamp"`
+}
+
+// LevelSubject 升级通知的来源对象，同一用户升到同一等级只通知一次
+func LevelSubject(level int) string {
+	return fmt.Sprintf("level:%d", level)
+}
+
+// LevelUpMessage 升级事件，经验发放或等级校准导致等级提升时随等级变更写入 outbox
+// 消费者为 (OldLevel, NewLevel] 区间内的每个等级授予徽章并通知用户
+type LevelUpMessage struct {
+	UserID    uint  `json:"user_id"`
+	OldLevel  int   `json:"old_level"`
+	NewLevel  int   `json:"new_level"`
+	Timestamp int64 `json:"timestamp"`
+}
+
+// LevelUpEvents 升级事件，随等级变更写入 outbox，签名与 repository.LevelUpEvents 一致
+func LevelUpEvents(userID uint, oldLevel, newLevel int) []*model.OutboxEvent {
+	return []*model.OutboxEvent{newOutboxEvent(LevelUpMessage{
+		UserID:    userID,
+		OldLevel:  oldLevel,
+		NewLevel:  newLevel,
+		Timestamp: time.Now().Unix(),
+	})}
+}
+
+// newOutboxEvent 将事件序列化为 outbox 记录
+func newOutboxEvent(event Event) *model.OutboxEvent {
+	body, _ := json.Marshal(event)
+	return &model.OutboxEvent{
+		RoutingKey:    event.RoutingKey(),
+		Payload:       string(body),
+		Status:        model.OutboxPending,
+		NextAttemptAt: time.Now(),
+	}
+}
+
+// ExpEvent 经验值发放事件，随业务数据写入 outbox
+func ExpEvent(userID uint, action, subject string, expAmount int) *model.OutboxEvent {
+	return newOutboxEvent(ExpMessage{
 		UserID:    userID,
 		Action:    action,
+		Subject:   subject,
 		ExpAmount: expAmount,
 		Timestamp: time.Now().Unix(),
-	}
+	})
+}
 
-	body, err := json.Marshal(msg)
-	if err != nil {
-		log.Printf("Failed to marshal exp message: %v", err)
-		return err
-	}
+// CheckinEvent 签到经验事件，流水计入签到当天（补发时也不占用当天的每日上限）
+func CheckinEvent(userID uint, day time.Time, expAmount int) *model.OutboxEvent {
+	return newOutboxEvent(ExpMessage{
+		UserID:    userID,
+		Action:    ActionLogin,
+		Subject:   CheckinSubject(day),
+		ExpAmount: expAmount,
+		Timestamp: day.Unix(),
+	})
+}
+
+// ExpRevokeEvent 经验撤销事件（取消点赞、删除帖子或评论时）
+func ExpRevokeEvent(userID uint, action, subject string) *model.OutboxEvent {
+	return newOutboxEvent(ExpMessage{
+		UserID:    userID,
+		Action:    action,
+		Subject:   subject,
+		Revoke:    true,
+		Timestamp: time.Now().Unix(),
+	})
+}
+
+// NotificationEvent 通知事件
+func NotificationEvent(msg NotificationMessage) *model.OutboxEvent {
+	msg.Timestamp = time.Now().Unix()
+	return newOutboxEvent(msg)
+}
+
+// PublishNotification 发布通知消息
+func PublishNotification(msg NotificationMessage) error {
+	msg.Timestamp = time.Now().Unix()
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	err = ch.PublishWithContext(ctx,
-		"user_activity", // exchange
-		"exp",           // routing key
-		false,           // mandatory
-		false,           // immediate
-		amqp.Publishing{
-			ContentType: "application/json",
-			Body:        body,
-		})
-
-	if err != nil {
-		log.Printf("Failed to publish exp message: %v", err)
+	if err := Publish(msg); err != nil {
+		log.Printf("Failed to publish notification: %v", err)
 		return err
 	}
 
-	log.Printf("Published exp message: userID=%d, action=%s, exp=%d", userID, action, expAmount)
+	log.Printf("Published notification: userID=%d, type=%s", msg.UserID, msg.Type)
 	return nil
 }
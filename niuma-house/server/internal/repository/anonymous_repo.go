@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"errors"
+
+	"niuma-house/internal/model"
+	"niuma-house/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+// anonymousAuthorColumns 各类匿名内容的表和作者字段
+var anonymousAuthorColumns = map[string]struct{ table, column string }{
+	model.AnonymousTargetPost:    {"posts", "user_id"},
+	model.AnonymousTargetComment: {"comments", "user_id"},
+	model.AnonymousTargetCompany: {"companies", "creator_id"},
+	model.AnonymousTargetReview:  {"company_reviews", "user_id"},
+}
+
+// ErrUnknownAnonymousTarget 不支持的内容类型
+var ErrUnknownAnonymousTarget = errors.New("unknown anonymous target type")
+
+// AnonymousRepository 匿名内容真实作者查询及审计记录仓储
+type AnonymousRepository struct {
+	db *gorm.DB
+}
+
+// NewAnonymousRepository 创建匿名内容仓储
+func NewAnonymousRepository() *AnonymousRepository {
+	return &AnonymousRepository{db: database.GetDB()}
+}
+
+// AuthorOf 查询内容的真实作者 ID（含已删除的内容）
+func (r *AnonymousRepository) AuthorOf(targetType string, targetID uint) (uint, error) {
+	col, ok := anonymousAuthorColumns[targetType]
+	if !ok {
+		return 0, ErrUnknownAnonymousTarget
+	}
+
+	var authorIDs []uint
+	err := r.db.Table(col.table).Where("id = ?", targetID).Limit(1).Pluck(col.column, &authorIDs).Error
+	if err != nil {
+		return 0, err
+	}
+	if len(authorIDs) == 0 {
+		return 0, gorm.ErrRecordNotFound
+	}
+	return authorIDs[0], nil
+}
+
+// CreateReveal 写入查看记录
+func (r *AnonymousRepository) CreateReveal(reveal *model.AnonymousReveal) error {
+	return r.db.Create(reveal).Error
+}
+
+// ListReveals 查看记录列表，targetType 为空时不过滤
+func (r *AnonymousRepository) ListReveals(targetType string, page, size int) ([]model.AnonymousReveal, int64, error) {
+	var reveals []model.AnonymousReveal
+	var total int64
+
+	query := r.db.Model(&model.AnonymousReveal{})
+	if targetType != "" {
+		query = query.Where("target_type = ?", targetType)
+	}
+	query.Count(&total)
+
+	offset := (page - 1) * size
+	err := query.Preload("Admin").
+		Order("id DESC").
+		Offset(offset).Limit(size).
+		Find(&reveals).Error
+	return reveals, total, err
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"encoding/json"
+	"time"
+
+	"niuma-house/internal/model"
+	"niuma-house/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+// auditRedactedColumns 快照中不记录的敏感字段
+var auditRedactedColumns = []string{"password"}
+
+// AuditFilter 审计日志筛选条件，零值表示不过滤
+type AuditFilter struct {
+	ActorID    uint
+	Action     string
+	TargetType string
+	TargetID   string
+	Since      time.Time
+	Until      time.Time
+}
+
+// AuditRepository 管理端审计日志仓储
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditRepository 创建审计日志仓储
+func NewAuditRepository() *AuditRepository {
+	return &AuditRepository{db: database.GetDB()}
+}
+
+// Create 写入审计日志
+func (r *AuditRepository) Create(log *model.AuditLog) error {
+	return r.db.Create(log).Error
+}
+
+// Snapshot 读取 table 中 column = key 的整行作为快照，记录不存在时返回 nil
+func (r *AuditRepository) Snapshot(table, column, key string) (json.RawMessage, error) {
+	var rows []map[string]any
+	err := r.db.Table(table).Where(column+" = ?", key).Limit(1).Find(&rows).Error
+	if err != nil || len(rows) == 0 {
+		return nil, err
+	}
+	for _, col := range auditRedactedColumns {
+		delete(rows[0], col)
+	}
+	return json.Marshal(rows[0])
+}
+
+// List 审计日志列表，按时间倒序
+func (r *AuditRepository) List(filter *AuditFilter, page, size int) ([]model.AuditLog, int64, error) {
+	var logs []model.AuditLog
+	var total int64
+
+	query := r.filter(filter)
+	query.Count(&total)
+
+	offset := (page - 1) * size
+	err := query.Preload("Actor").
+		Order("id DESC").
+		Offset(offset).Limit(size).
+		Find(&logs).Error
+	return logs, total, err
+}
+
+// Export 导出符合条件的审计日志，最多 limit 条，按时间倒序
+func (r *AuditRepository) Export(filter *AuditFilter, limit int) ([]model.AuditLog, error) {
+	var logs []model.AuditLog
+	err := r.filter(filter).Preload("Actor").
+		Order("id DESC").
+		Limit(limit).
+		Find(&logs).Error
+	return logs, err
+}
+
+// filter 构造筛选条件
+func (r *AuditRepository) filter(filter *AuditFilter) *gorm.DB {
+	query := r.db.Model(&model.AuditLog{})
+	if filter.ActorID > 0 {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != "" {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	if !filter.Since.IsZero() {
+		query = query.Where("created_at >= ?", filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		query = query.Where("created_at < ?", filter.Until)
+	}
+	return query
+}
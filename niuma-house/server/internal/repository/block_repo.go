@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"niuma-house/internal/model"
+	"niuma-house/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+// BlockRepository 黑名单仓储
+type BlockRepository struct {
+	db *gorm.DB
+}
+
+// NewBlockRepository 创建黑名单仓储
+func NewBlockRepository() *BlockRepository {
+	return &BlockRepository{db: database.GetDB()}
+}
+
+// Block 拉黑
+func (r *BlockRepository) Block(userID, blockedID uint) error {
+	block := model.UserBlock{UserID: userID, BlockedID: blockedID}
+	return r.db.Create(&block).Error
+}
+
+// Unblock 取消拉黑
+func (r *BlockRepository) Unblock(userID, blockedID uint) error {
+	return r.db.Where("user_id = ? AND blocked_id = ?", userID, blockedID).
+		Delete(&model.UserBlock{}).Error
+}
+
+// IsBlocked userID 是否拉黑了 blockedID
+func (r *BlockRepository) IsBlocked(userID, blockedID uint) bool {
+	var count int64
+	r.db.Model(&model.UserBlock{}).
+		Where("user_id = ? AND blocked_id = ?", userID, blockedID).
+		Count(&count)
+	return count > 0
+}
+
+// BlockersOf userIDs 中拉黑了 blockedID 的用户
+func (r *BlockRepository) BlockersOf(userIDs []uint, blockedID uint) map[uint]bool {
+	result := make(map[uint]bool)
+	if len(userIDs) == 0 {
+		return result
+	}
+
+	var blockers []uint
+	r.db.Model(&model.UserBlock{}).
+		Where("user_id IN ? AND blocked_id = ?", userIDs, blockedID).
+		Pluck("user_id", &blockers)
+	for _, id := range blockers {
+		result[id] = true
+	}
+	return result
+}
+
+// ListByUserID 获取用户的黑名单
+func (r *BlockRepository) ListByUserID(userID uint, page, size int) ([]model.UserBlock, int64, error) {
+	var blocks []model.UserBlock
+	var total int64
+
+	query := r.db.Model(&model.UserBlock{}).Where("user_id = ?", userID)
+	query.Count(&total)
+
+	offset := (page - 1) * size
+	err := query.Preload("Blocked").
+		Order("created_at DESC").
+		Offset(offset).Limit(size).
+		Find(&blocks).Error
+
+	return blocks, total, err
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"niuma-house/pkg/cache"
+)
+
+// 缓存有效期
+const (
+	postCacheTTL       = 10 * time.Minute
+	postFeedCacheTTL   = time.Minute
+	companyCacheTTL    = 10 * time.Minute
+	occupationCacheTTL = time.Hour
+	userLevelCacheTTL  = 5 * time.Minute
+	levelCacheTTL      = 10 * time.Minute
+
+	// 帖子列表只缓存前几页
+	postFeedCachePages = 3
+)
+
+// postFeedVersionKey 帖子列表缓存版本号，任何帖子变更都会递增以整体失效
+const postFeedVersionKey = "cache:post:feed:ver"
+
+const occupationListKey = "cache:occupation:list"
+
+const levelListKey = "cache:level:list"
+
+func postCacheKey(id uint) string {
+	return fmt.Sprintf("cache:post:%d", id)
+}
+
+func postFeedCacheKey(version int64, occupationID uint, page, size int) string {
+	return fmt.Sprintf("cache:post:feed:v%d:%d:%d:%d", version, occupationID, page, size)
+}
+
+func userLevelCacheKey(id uint) string {
+	return fmt.Sprintf("cache:user:level:%d", id)
+}
+
+func companyCacheKey(id uint) string {
+	return fmt.Sprintf("cache:company:%d", id)
+}
+
+// pageResult 分页查询缓存结构
+type pageResult[T any] struct {
+	List  []T   `json:"list"`
+	Total int64 `json:"total"`
+}
+
+// postFeedVersion 获取当前帖子列表缓存版本号
+func postFeedVersion(ctx context.Context) int64 {
+	version, _ := cache.GetRedis().Get(ctx, postFeedVersionKey).Int64()
+	return version
+}
+
+// invalidatePost 失效帖子详情和帖子列表缓存
+func invalidatePost(postID uint) {
+	ctx := context.Background()
+	if postID > 0 {
+		cache.Invalidate(ctx, postCacheKey(postID))
+	}
+	cache.GetRedis().Incr(ctx, postFeedVersionKey)
+}
+
+// invalidateCompany 失效公司详情缓存
+func invalidateCompany(companyID uint) {
+	cache.Invalidate(context.Background(), companyCacheKey(companyID))
+}
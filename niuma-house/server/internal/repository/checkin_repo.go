@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"niuma-house/pkg/cache"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 签到日历保留时间，足够查看最近一年
+const checkinCalendarTTL = 400 * 24 * time.Hour
+
+// 连续签到记录的保留时间，超过一天未签到后自然过期
+const checkinStreakTTL = 49 * time.Hour
+
+// 每次 SCAN 返回的 key 数
+const checkinScanCount = 500
+
+// checkinCalendarKey 用户某月的签到位图，第 N 位表示 N+1 号已签到
+func checkinCalendarKey(userID uint, month time.Time) string {
+	return fmt.Sprintf("checkin:calendar:%d:%s", userID, month.Format("200601"))
+}
+
+// checkinStreakKey 用户连续签到记录，Hash 字段 day 为最近签到日期，streak 为截至该日的连续天数
+func checkinStreakKey(userID uint) string {
+	return fmt.Sprintf("checkin:streak:%d", userID)
+}
+
+// checkinScript 原子地完成签到：置位当天日历并更新连续天数，返回 {是否首次签到, 连续天数}
+// ARGV: 日期在当月的偏移, 今天, 昨天, 日历 TTL(ms), 连续记录 TTL(ms)
+var checkinScript = redis.NewScript(`
+if redis.call('SETBIT', KEYS[1], ARGV[1], 1) == 1 then
+	return {0, tonumber(redis.call('HGET', KEYS[2], 'streak') or '1')}
+end
+redis.call('PEXPIRE', KEYS[1], ARGV[4])
+local last = redis.call('HGET', KEYS[2], 'day')
+local streak = 1
+if last == ARGV[3] then
+	streak = tonumber(redis.call('HGET', KEYS[2], 'streak') or '0') + 1
+elseif last == ARGV[2] then
+	streak = tonumber(redis.call('HGET', KEYS[2], 'streak') or '1')
+end
+redis.call('HSET', KEYS[2], 'day', ARGV[2], 'streak', streak)
+redis.call('PEXPIRE', KEYS[2], ARGV[5])
+return {1, streak}
+`)
+
+// CheckinRepository 每日签到仓储，签到日历和连续天数保存在 Redis 中
+type CheckinRepository struct {
+	rdb *redis.Client
+}
+
+// NewCheckinRepository 创建签到仓储
+func NewCheckinRepository() *CheckinRepository {
+	return &CheckinRepository{rdb: cache.GetRedis()}
+}
+
+// Checkin 记录 day 当天的签到，返回是否为当天首次签到及截至当天的连续天数
+func (r *CheckinRepository) Checkin(userID uint, day time.Time) (bool, int, error) {
+	res, err := checkinScript.Run(context.Background(), r.rdb,
+		[]string{checkinCalendarKey(userID, day), checkinStreakKey(userID)},
+		day.Day()-1,
+		day.Format("2006-01-02"),
+		day.AddDate(0, 0, -1).Format("2006-01-02"),
+		checkinCalendarTTL.Milliseconds(),
+		checkinStreakTTL.Milliseconds(),
+	).Int64Slice()
+	if err != nil {
+		return false, 0, err
+	}
+	return res[0] == 1, int(res[1]), nil
+}
+
+// Streak 截至 day 的连续签到天数，day 当天尚未签到时按截至前一天计算
+func (r *CheckinRepository) Streak(userID uint, day time.Time) (int, error) {
+	values, err := r.rdb.HMGet(context.Background(), checkinStreakKey(userID), "day", "streak").Result()
+	if err != nil {
+		return 0, err
+	}
+	last, _ := values[0].(string)
+	if last != day.Format("2006-01-02") && last != day.AddDate(0, 0, -1).Format("2006-01-02") {
+		return 0, nil
+	}
+	streak, _ := values[1].(string)
+	n, _ := strconv.Atoi(streak)
+	return n, nil
+}
+
+// Calendar 用户某月已签到的日期（几号），升序
+func (r *CheckinRepository) Calendar(userID uint, month time.Time) ([]int, error) {
+	bitmap, err := r.rdb.Get(context.Background(), checkinCalendarKey(userID, month)).Bytes()
+	if err == redis.Nil {
+		return []int{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	days := make([]int, 0)
+	for i, b := range bitmap {
+		for bit := 0; bit < 8; bit++ {
+			if b&(0x80>>bit) != 0 {
+				days = append(days, i*8+bit+1)
+			}
+		}
+	}
+	return days, nil
+}
+
+// StreakAt 根据签到日历计算截至 day 的连续天数，最多回溯 limit 天
+func (r *CheckinRepository) StreakAt(userID uint, day time.Time, limit int) (int, error) {
+	ctx := context.Background()
+	streak := 0
+	for streak < limit {
+		d := day.AddDate(0, 0, -streak)
+		bit, err := r.rdb.GetBit(ctx, checkinCalendarKey(userID, d), int64(d.Day()-1)).Result()
+		if err != nil {
+			return 0, err
+		}
+		if bit == 0 {
+			break
+		}
+		streak++
+	}
+	return streak, nil
+}
+
+// CheckedInUsers 遍历签到日历，返回 day 当天已签到的用户
+func (r *CheckinRepository) CheckedInUsers(day time.Time) ([]uint, error) {
+	ctx := context.Background()
+	suffix := ":" + day.Format("200601")
+	offset := int64(day.Day() - 1)
+
+	var userIDs []uint
+	iter := r.rdb.Scan(ctx, 0, "checkin:calendar:*"+suffix, checkinScanCount).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		bit, err := r.rdb.GetBit(ctx, key, offset).Result()
+		if err != nil {
+			return nil, err
+		}
+		if bit == 0 {
+			continue
+		}
+		id, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(key, "checkin:calendar:"), suffix), 10, 64)
+		if err == nil {
+			userIDs = append(userIDs, uint(id))
+		}
+	}
+	return userIDs, iter.Err()
+}
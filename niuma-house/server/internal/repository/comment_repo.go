@@ -17,9 +17,15 @@ func NewCommentRepository() *CommentRepository {
 	return &CommentRepository{db: database.GetDB()}
 }
 
-// Create 创建评论
-func (r *CommentRepository) Create(comment *model.Comment) error {
-	return r.db.Create(comment).Error
+// Create 创建评论，events 在同一事务中写入 outbox
+// hold 不为空时评论需以隐藏状态传入，同一事务内创建送审工单，events 暂存到工单驳回后发布
+func (r *CommentRepository) Create(comment *model.Comment, events OutboxEvents, hold *ContentHold) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(comment).Error; err != nil {
+			return err
+		}
+		return writeContent(tx, model.ReportTargetComment, comment.ID, hold, true, events)
+	})
 }
 
 // FindByID 根据 ID 查找评论
@@ -32,24 +38,29 @@ func (r *CommentRepository) FindByID(id uint) (*model.Comment, error) {
 	return &comment, nil
 }
 
-// Delete 删除评论
-func (r *CommentRepository) Delete(id uint) error {
-	return r.db.Model(&model.Comment{}).Where("id = ?", id).
-		Update("status", 0).Error
+// Delete 删除评论，events 在同一事务中写入 outbox
+func (r *CommentRepository) Delete(id uint, events OutboxEvents) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.Comment{}).Where("id = ?", id).
+			Update("status", 0).Error; err != nil {
+			return err
+		}
+		return writeOutbox(tx, events)
+	})
 }
 
-// ListByPostID 根据帖子 ID 获取评论列表
-func (r *CommentRepository) ListByPostID(postID uint, page, size int) ([]model.Comment, int64, error) {
+// ListRootsByPostID 根据帖子 ID 获取一级评论列表
+func (r *CommentRepository) ListRootsByPostID(postID uint, page, size int) ([]model.Comment, int64, error) {
 	var comments []model.Comment
 	var total int64
 
-	r.db.Model(&model.Comment{}).
-		Where("post_id = ? AND status = 1", postID).
-		Count(&total)
+	query := r.db.Model(&model.Comment{}).
+		Where("post_id = ? AND status = 1 AND root_id IS NULL", postID)
+
+	query.Count(&total)
 
 	offset := (page - 1) * size
-	err := r.db.Preload("User").
-		Where("post_id = ? AND status = 1", postID).
+	err := query.Preload("User").
 		Order("created_at ASC").
 		Offset(offset).Limit(size).
 		Find(&comments).Error
@@ -57,6 +68,75 @@ func (r *CommentRepository) ListByPostID(postID uint, page, size int) ([]model.C
 	return comments, total, err
 }
 
+// ListReplies 获取一级评论下的回复（分页）
+func (r *CommentRepository) ListReplies(rootID uint, page, size int) ([]model.Comment, int64, error) {
+	var replies []model.Comment
+	var total int64
+
+	query := r.db.Model(&model.Comment{}).
+		Where("root_id = ? AND status = 1", rootID)
+
+	query.Count(&total)
+
+	offset := (page - 1) * size
+	err := query.Preload("User").Preload("ReplyToUser").
+		Order("created_at ASC").
+		Offset(offset).Limit(size).
+		Find(&replies).Error
+
+	return replies, total, err
+}
+
+// ListReplyPreviews 批量获取每个一级评论最早的 limit 条回复
+func (r *CommentRepository) ListReplyPreviews(rootIDs []uint, limit int) (map[uint][]model.Comment, error) {
+	result := make(map[uint][]model.Comment)
+	if len(rootIDs) == 0 {
+		return result, nil
+	}
+
+	ranked := r.db.Model(&model.Comment{}).
+		Select("*, ROW_NUMBER() OVER (PARTITION BY root_id ORDER BY created_at ASC, id ASC) AS rn").
+		Where("root_id IN ? AND status = 1", rootIDs)
+
+	var replies []model.Comment
+	err := r.db.Table("(?) AS comments", ranked).
+		Preload("User").Preload("ReplyToUser").
+		Where("rn <= ?", limit).
+		Order("created_at ASC, id ASC").
+		Find(&replies).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, reply := range replies {
+		result[*reply.RootID] = append(result[*reply.RootID], reply)
+	}
+	return result, nil
+}
+
+// CountRepliesByRootIDs 批量统计一级评论的回复数
+func (r *CommentRepository) CountRepliesByRootIDs(rootIDs []uint) map[uint]int64 {
+	result := make(map[uint]int64)
+	if len(rootIDs) == 0 {
+		return result
+	}
+
+	var rows []struct {
+		RootID uint
+		Count  int64
+	}
+	r.db.Model(&model.Comment{}).
+		Select("root_id, COUNT(*) AS count").
+		Where("root_id IN ? AND status = 1", rootIDs).
+		Group("root_id").
+		Scan(&rows)
+
+	for _, row := range rows {
+		result[row.RootID] = row.Count
+	}
+	return result
+}
+
 // CountByPostID 统计帖子评论数
 func (r *CommentRepository) CountByPostID(postID uint) int64 {
 	var count int64
@@ -4,16 +4,13 @@ import (
 	"context"
 
 	"niuma-house/internal/model"
+	"niuma-house/pkg/cache"
 	"niuma-house/pkg/database"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-// Searcher 搜索接口
-type Searcher interface {
-	SearchCompanies(ctx context.Context, keyword string, page, size int) ([]model.Company, int64, error)
-}
-
 // CompanyRepository 公司仓储
 type CompanyRepository struct {
 	db *gorm.DB
@@ -29,26 +26,72 @@ func (r *CompanyRepository) Create(company *model.Company) error {
 	return r.db.Create(company).Error
 }
 
-// FindByID 根据 ID 查找公司
-func (r *CompanyRepository) FindByID(id uint) (*model.Company, error) {
-	var company model.Company
-	err := r.db.Preload("Creator").
-		Where("status > 0").First(&company, id).Error
+// CreateWithReview 创建公司及创建者的首条评价，hold 不为空时公司需以隐藏状态传入，同一事务内创建送审工单
+func (r *CompanyRepository) CreateWithReview(company *model.Company, review *model.CompanyReview, hold *ContentHold) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(company).Error; err != nil {
+			return err
+		}
+		review.CompanyID = company.ID
+		if err := tx.Create(review).Error; err != nil {
+			return err
+		}
+		return writeContent(tx, model.ReportTargetCompany, company.ID, hold, true, nil)
+	})
 	if err != nil {
-		return nil, err
+		return err
+	}
+	return r.RefreshAggregate(company.ID)
+}
+
+// RefreshAggregate 根据有效评价重新计算公司的避雷等级和标签统计
+func (r *CompanyRepository) RefreshAggregate(companyID uint) error {
+	var reviews []model.CompanyReview
+	if err := r.db.Where("company_id = ? AND status = 1", companyID).
+		Find(&reviews).Error; err != nil {
+		return err
+	}
+
+	err := r.db.Model(&model.Company{}).Where("id = ?", companyID).
+		UpdateColumns(model.AggregateReviews(reviews).Columns()).Error
+	if err != nil {
+		return err
 	}
-	return &company, nil
+	invalidateCompany(companyID)
+	return nil
+}
+
+// FindByID 根据 ID 查找公司（读穿缓存）
+func (r *CompanyRepository) FindByID(id uint) (*model.Company, error) {
+	return cache.Remember(context.Background(), "company", companyCacheKey(id), companyCacheTTL, func() (*model.Company, error) {
+		var company model.Company
+		err := r.db.Preload("Creator").
+			Where("status > 0").First(&company, id).Error
+		if err != nil {
+			return nil, err
+		}
+		return &company, nil
+	})
 }
 
 // Update 更新公司
 func (r *CompanyRepository) Update(company *model.Company) error {
-	return r.db.Save(company).Error
+	if err := r.db.Save(company).Error; err != nil {
+		return err
+	}
+	invalidateCompany(company.ID)
+	return nil
 }
 
 // Delete 删除公司（软删除）
 func (r *CompanyRepository) Delete(id uint) error {
-	return r.db.Model(&model.Company{}).Where("id = ?", id).
+	err := r.db.Model(&model.Company{}).Where("id = ?", id).
 		Update("status", 0).Error
+	if err != nil {
+		return err
+	}
+	invalidateCompany(id)
+	return nil
 }
 
 // List 公司列表
@@ -56,81 +99,138 @@ func (r *CompanyRepository) List(page, size int) ([]model.Company, int64, error)
 	var companies []model.Company
 	var total int64
 
-	r.db.Model(&model.Company{}).Where("status > 0").Count(&total)
+	r.db.Model(&model.Company{}).Where("status = ?", model.CompanyStatusNormal).Count(&total)
 
 	offset := (page - 1) * size
 	err := r.db.Preload("Creator").
-		Where("status > 0").
-		Order("risk_level DESC, created_at DESC").
+		Where("status = ?", model.CompanyStatusNormal).
+		Order("avg_risk DESC, review_count DESC, created_at DESC").
 		Offset(offset).Limit(size).
 		Find(&companies).Error
 
 	return companies, total, err
 }
 
-// IncrementViews 增加浏览数
-func (r *CompanyRepository) IncrementViews(companyID uint) error {
-	return r.db.Model(&model.Company{}).Where("id = ?", companyID).
-		UpdateColumn("view_count", gorm.Expr("view_count + 1")).Error
-}
-
-// AdminList 管理端列表
-func (r *CompanyRepository) AdminList(page, size int) ([]model.Company, int64, error) {
+// FindCandidates 按归一化名称查找可能重复的公司（相同、互为前缀或首字相同）
+// 名称完全相同的公司总是排在最前；其余按名称长度与目标的差距、评价数排序后取前 limit 个，
+// 避免常见首字（如"北京"）的公司过多时漏掉真正相近的公司
+func (r *CompanyRepository) FindCandidates(normalizedName string, limit int) ([]model.Company, error) {
 	var companies []model.Company
-	var total int64
+	if normalizedName == "" {
+		return companies, nil
+	}
 
-	r.db.Model(&model.Company{}).Count(&total)
+	if err := r.db.Where("status = ? AND normalized_name = ?", model.CompanyStatusNormal, normalizedName).
+		Limit(limit).Find(&companies).Error; err != nil {
+		return nil, err
+	}
+	if len(companies) >= limit {
+		return companies, nil
+	}
 
-	offset := (page - 1) * size
-	err := r.db.Preload("Creator").
-		Order("created_at DESC").
-		Offset(offset).Limit(size).
-		Find(&companies).Error
+	var similar []model.Company
+	prefix := string([]rune(normalizedName)[:1])
+	err := r.db.Where("status = ? AND normalized_name <> ?", model.CompanyStatusNormal, normalizedName).
+		Where("normalized_name LIKE ? OR ? LIKE CONCAT(normalized_name, '%')",
+			prefix+"%", normalizedName).
+		Order(clause.Expr{SQL: "ABS(CHAR_LENGTH(normalized_name) - CHAR_LENGTH(?)), review_count DESC", Vars: []interface{}{normalizedName}}).
+		Limit(limit - len(companies)).
+		Find(&similar).Error
 
-	return companies, total, err
+	return append(companies, similar...), err
 }
 
-// MySQLSearcher MySQL 搜索实现
-type MySQLSearcher struct {
-	db *gorm.DB
-}
+// Merge 将 source 公司合并到 target：迁移评价、证据和浏览量，source 标记为已合并并指向 target
+func (r *CompanyRepository) Merge(sourceID, targetID uint) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var source, target model.Company
+		if err := tx.Where("status = ?", model.CompanyStatusNormal).First(&source, sourceID).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("status = ?", model.CompanyStatusNormal).First(&target, targetID).Error; err != nil {
+			return err
+		}
+
+		// 同一用户在两家公司都有评价时，target 的评价有效则保留 target 的，
+		// 否则（已删除）由 source 的评价替换
+		var targetUserIDs []uint
+		if err := tx.Model(&model.CompanyReview{}).Where("company_id = ? AND status = 1", targetID).
+			Pluck("user_id", &targetUserIDs).Error; err != nil {
+			return err
+		}
+		if len(targetUserIDs) > 0 {
+			if err := tx.Where("company_id = ? AND user_id IN ?", sourceID, targetUserIDs).
+				Delete(&model.CompanyReview{}).Error; err != nil {
+				return err
+			}
+		}
+		var sourceUserIDs []uint
+		if err := tx.Model(&model.CompanyReview{}).Where("company_id = ?", sourceID).
+			Pluck("user_id", &sourceUserIDs).Error; err != nil {
+			return err
+		}
+		if len(sourceUserIDs) > 0 {
+			if err := tx.Where("company_id = ? AND user_id IN ?", targetID, sourceUserIDs).
+				Delete(&model.CompanyReview{}).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&model.CompanyReview{}).Where("company_id = ?", sourceID).
+			Update("company_id", targetID).Error; err != nil {
+			return err
+		}
+
+		// 合并证据（去重）
+		evidence := target.Evidence
+		seen := make(map[string]bool, len(evidence))
+		for _, key := range evidence {
+			seen[key] = true
+		}
+		for _, key := range source.Evidence {
+			if !seen[key] {
+				evidence = append(evidence, key)
+				seen[key] = true
+			}
+		}
+
+		if err := tx.Model(&model.Company{}).Where("id = ?", targetID).
+			UpdateColumns(map[string]interface{}{
+				"evidence":   evidence,
+				"view_count": gorm.Expr("view_count + ?", source.ViewCount),
+			}).Error; err != nil {
+			return err
+		}
+
+		// source 及之前合并到 source 的公司都重定向到 target
+		if err := tx.Model(&model.Company{}).Where("id = ? OR merged_into_id = ?", sourceID, sourceID).
+			UpdateColumns(map[string]interface{}{
+				"status":         model.CompanyStatusMerged,
+				"merged_into_id": targetID,
+			}).Error; err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
 
-// NewMySQLSearcher 创建 MySQL 搜索器
-func NewMySQLSearcher() *MySQLSearcher {
-	return &MySQLSearcher{db: database.GetDB()}
+	invalidateCompany(sourceID)
+	return r.RefreshAggregate(targetID)
 }
 
-// SearchCompanies 搜索公司
-func (s *MySQLSearcher) SearchCompanies(ctx context.Context, keyword string, page, size int) ([]model.Company, int64, error) {
+// AdminList 管理端列表
+func (r *CompanyRepository) AdminList(page, size int) ([]model.Company, int64, error) {
 	var companies []model.Company
 	var total int64
 
-	query := s.db.Model(&model.Company{}).Where("status > 0")
-	if keyword != "" {
-		query = query.Where("name LIKE ? OR city LIKE ?", "%"+keyword+"%", "%"+keyword+"%")
-	}
-
-	query.Count(&total)
+	r.db.Model(&model.Company{}).Count(&total)
 
 	offset := (page - 1) * size
-	err := query.Preload("Creator").
-		Order("risk_level DESC, view_count DESC").
+	err := r.db.Preload("Creator").
+		Order("created_at DESC").
 		Offset(offset).Limit(size).
 		Find(&companies).Error
 
 	return companies, total, err
 }
-
-// ElasticSearcher Elasticsearch 搜索实现（预留）
-// type ElasticSearcher struct {
-//     client *elastic.Client
-// }
-//
-// func NewElasticSearcher(client *elastic.Client) *ElasticSearcher {
-//     return &ElasticSearcher{client: client}
-// }
-//
-// func (s *ElasticSearcher) SearchCompanies(ctx context.Context, keyword string, page, size int) ([]model.Company, int64, error) {
-//     // TODO: 实现 Elasticsearch 搜索
-//     return nil, 0, nil
-// }
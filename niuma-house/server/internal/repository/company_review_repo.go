@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"niuma-house/internal/model"
+	"niuma-house/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+// CompanyReviewRepository 公司评价仓储
+type CompanyReviewRepository struct {
+	db *gorm.DB
+}
+
+// NewCompanyReviewRepository 创建公司评价仓储
+func NewCompanyReviewRepository() *CompanyReviewRepository {
+	return &CompanyReviewRepository{db: database.GetDB()}
+}
+
+// Create 创建评价
+func (r *CompanyReviewRepository) Create(review *model.CompanyReview) error {
+	return r.db.Create(review).Error
+}
+
+// Update 更新评价
+func (r *CompanyReviewRepository) Update(review *model.CompanyReview) error {
+	return r.db.Save(review).Error
+}
+
+// FindByID 根据 ID 查找评价
+func (r *CompanyReviewRepository) FindByID(id uint) (*model.CompanyReview, error) {
+	var review model.CompanyReview
+	err := r.db.First(&review, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// FindByCompanyAndUser 查找用户对公司的评价（含已删除）
+func (r *CompanyReviewRepository) FindByCompanyAndUser(companyID, userID uint) (*model.CompanyReview, error) {
+	var review model.CompanyReview
+	err := r.db.Where("company_id = ? AND user_id = ?", companyID, userID).
+		First(&review).Error
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// Delete 删除评价
+func (r *CompanyReviewRepository) Delete(id uint) error {
+	return r.db.Model(&model.CompanyReview{}).Where("id = ?", id).
+		Update("status", 0).Error
+}
+
+// ListByCompanyID 公司评价列表
+func (r *CompanyReviewRepository) ListByCompanyID(companyID uint, page, size int) ([]model.CompanyReview, int64, error) {
+	var reviews []model.CompanyReview
+	var total int64
+
+	query := r.db.Model(&model.CompanyReview{}).
+		Where("company_id = ? AND status = 1", companyID)
+
+	query.Count(&total)
+
+	offset := (page - 1) * size
+	err := query.Preload("User").
+		Order("created_at DESC").
+		Offset(offset).Limit(size).
+		Find(&reviews).Error
+
+	return reviews, total, err
+}
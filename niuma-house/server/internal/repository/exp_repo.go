@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"niuma-house/internal/model"
+	"niuma-house/pkg/cache"
+	"niuma-house/pkg/database"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ExpResult 经验变更结果
+type ExpResult struct {
+	Applied  bool // false 表示重复消息，未做任何变更
+	Amount   int  // 经验变化量，撤销时为负数
+	OldLevel int
+	NewLevel int
+}
+
+// LevelUpEvents 用户升级时需要发布的事件，与等级变更在同一事务中写入 outbox
+type LevelUpEvents func(userID uint, oldLevel, newLevel int) []*model.OutboxEvent
+
+// ExpRepository 经验值流水仓储
+type ExpRepository struct {
+	db        *gorm.DB
+	levelRepo *LevelRepository
+}
+
+// NewExpRepository 创建经验值流水仓储
+func NewExpRepository() *ExpRepository {
+	return &ExpRepository{db: database.GetDB(), levelRepo: NewLevelRepository()}
+}
+
+// Grant 按流水发放经验，dailyCap > 0 时同一行为每日发放总量不超过该值
+// 幂等键已存在（重复投递或已撤销）时不做变更；升级时 levelUp 产生的事件随流水一起提交
+func (r *ExpRepository) Grant(entry *model.ExpLedger, dailyCap int, levelUp LevelUpEvents) (*ExpResult, error) {
+	levels, err := r.levelRepo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ExpResult{}
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		// 锁定用户行，串行化同一用户的经验变更，保证每日上限准确
+		user, err := lockUser(tx, entry.UserID)
+		if err != nil {
+			return err
+		}
+		result.OldLevel, result.NewLevel = user.Level, user.Level
+
+		var count int64
+		if err := tx.Model(&model.ExpLedger{}).Where("idem_key = ?", entry.IdemKey).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return nil
+		}
+
+		entry.Amount = entry.Requested
+		if dailyCap > 0 {
+			var used int
+			if err := tx.Model(&model.ExpLedger{}).
+				Where("user_id = ? AND day = ? AND action = ? AND revoked_at IS NULL", entry.UserID, entry.Day, entry.Action).
+				Select("COALESCE(SUM(amount), 0)").Scan(&used).Error; err != nil {
+				return err
+			}
+			entry.Amount = min(entry.Requested, max(dailyCap-used, 0))
+		}
+
+		if err := tx.Create(entry).Error; err != nil {
+			return err
+		}
+		result.Applied = true
+		result.Amount = entry.Amount
+		if err := applyExp(tx, user, entry.Amount, levels, result); err != nil {
+			return err
+		}
+		if result.NewLevel > result.OldLevel && levelUp != nil {
+			return writeOutbox(tx, func() []*model.OutboxEvent {
+				return levelUp(entry.UserID, result.OldLevel, result.NewLevel)
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	invalidateUserLevel(entry.UserID, result)
+	return result, nil
+}
+
+// Revoke 撤销一条经验流水并扣回已发放的经验
+// 流水尚不存在时（撤销消息先于发放到达）写入撤销占位，之后到达的发放消息会被忽略
+func (r *ExpRepository) Revoke(userID uint, action, subject, day string) (*ExpResult, error) {
+	levels, err := r.levelRepo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ExpResult{}
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		user, err := lockUser(tx, userID)
+		if err != nil {
+			return err
+		}
+		result.OldLevel, result.NewLevel = user.Level, user.Level
+
+		now := time.Now()
+		var entry model.ExpLedger
+		err = tx.Where("idem_key = ?", model.ExpIdemKey(userID, action, subject)).First(&entry).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			result.Applied = true
+			return tx.Create(&model.ExpLedger{
+				UserID:    userID,
+				Action:    action,
+				Subject:   subject,
+				IdemKey:   model.ExpIdemKey(userID, action, subject),
+				Day:       day,
+				RevokedAt: &now,
+			}).Error
+		}
+		if err != nil || entry.RevokedAt != nil {
+			return err
+		}
+
+		if err := tx.Model(&entry).Update("revoked_at", now).Error; err != nil {
+			return err
+		}
+		result.Applied = true
+		result.Amount = -entry.Amount
+		return applyExp(tx, user, -entry.Amount, levels, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	invalidateUserLevel(userID, result)
+	return result, nil
+}
+
+// ListByUserID 用户的经验流水（不含撤销占位）
+func (r *ExpRepository) ListByUserID(userID uint, page, size int) ([]model.ExpLedger, int64, error) {
+	var entries []model.ExpLedger
+	var total int64
+
+	query := r.db.Model(&model.ExpLedger{}).Where("user_id = ? AND requested > 0", userID)
+	query.Count(&total)
+
+	offset := (page - 1) * size
+	err := query.Order("id DESC").Offset(offset).Limit(size).Find(&entries).Error
+	return entries, total, err
+}
+
+// UsersWithSubject 返回 userIDs 中已有该来源流水（含已撤销和撤销占位）的用户
+func (r *ExpRepository) UsersWithSubject(action, subject string, userIDs []uint) (map[uint]bool, error) {
+	found := make(map[uint]bool)
+	if len(userIDs) == 0 {
+		return found, nil
+	}
+
+	var ids []uint
+	err := r.db.Model(&model.ExpLedger{}).
+		Where("action = ? AND subject = ? AND user_id IN ?", action, subject, userIDs).
+		Pluck("user_id", &ids).Error
+	for _, id := range ids {
+		found[id] = true
+	}
+	return found, err
+}
+
+// lockUser 加行锁读取用户经验和等级
+func lockUser(tx *gorm.DB, userID uint) (*model.User, error) {
+	var user model.User
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "exp", "level").First(&user, userID).Error
+	return &user, err
+}
+
+// applyExp 变更经验值（不低于 0）并重新计算等级
+func applyExp(tx *gorm.DB, user *model.User, delta int, levels []model.Level, result *ExpResult) error {
+	if delta == 0 {
+		return nil
+	}
+	exp := max(user.Exp+delta, 0)
+	result.NewLevel = model.LevelForExp(levels, exp)
+	return tx.Model(user).UpdateColumns(map[string]interface{}{
+		"exp":   exp,
+		"level": result.NewLevel,
+	}).Error
+}
+
+// invalidateUserLevel 等级变化时失效等级缓存
+func invalidateUserLevel(userID uint, result *ExpResult) {
+	if result.NewLevel != result.OldLevel {
+		cache.Invalidate(context.Background(), userLevelCacheKey(userID))
+	}
+}
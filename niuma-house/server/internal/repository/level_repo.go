@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+
+	"niuma-house/internal/model"
+	"niuma-house/pkg/cache"
+	"niuma-house/pkg/database"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LevelRepository 等级配置仓储
+type LevelRepository struct {
+	db *gorm.DB
+}
+
+// NewLevelRepository 创建等级配置仓储
+func NewLevelRepository() *LevelRepository {
+	return &LevelRepository{db: database.GetDB()}
+}
+
+// List 获取全部等级，按经验阈值升序（读穿缓存）
+func (r *LevelRepository) List() ([]model.Level, error) {
+	return cache.Remember(context.Background(), "level", levelListKey, levelCacheTTL, func() ([]model.Level, error) {
+		var levels []model.Level
+		err := r.db.Order("min_exp ASC").Find(&levels).Error
+		return levels, err
+	})
+}
+
+// Find 获取指定等级的配置（等级被删除时取不高于它的最近一级）
+func (r *LevelRepository) Find(level int) (*model.Level, error) {
+	levels, err := r.List()
+	if err != nil {
+		return nil, err
+	}
+	if found := model.FindLevel(levels, level); found != nil {
+		return found, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// ForExp 根据经验值计算等级
+func (r *LevelRepository) ForExp(exp int) (int, error) {
+	levels, err := r.List()
+	if err != nil {
+		return 0, err
+	}
+	return model.LevelForExp(levels, exp), nil
+}
+
+// Save 新增或更新等级
+func (r *LevelRepository) Save(level *model.Level) error {
+	if err := r.db.Save(level).Error; err != nil {
+		return err
+	}
+	cache.Invalidate(context.Background(), levelListKey)
+	return nil
+}
+
+// Delete 删除等级
+func (r *LevelRepository) Delete(level int) error {
+	if err := r.db.Delete(&model.Level{}, level).Error; err != nil {
+		return err
+	}
+	cache.Invalidate(context.Background(), levelListKey)
+	return nil
+}
+
+// BadgeRepository 用户徽章仓储
+type BadgeRepository struct {
+	db *gorm.DB
+}
+
+// NewBadgeRepository 创建用户徽章仓储
+func NewBadgeRepository() *BadgeRepository {
+	return &BadgeRepository{db: database.GetDB()}
+}
+
+// Award 授予徽章，已获得过该等级徽章时返回 false
+func (r *BadgeRepository) Award(badge *model.UserBadge) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(badge)
+	return result.RowsAffected > 0, result.Error
+}
+
+// ListByUserID 用户的全部徽章
+func (r *BadgeRepository) ListByUserID(userID uint) ([]model.UserBadge, error) {
+	var badges []model.UserBadge
+	err := r.db.Where("user_id = ?", userID).Order("level ASC").Find(&badges).Error
+	return badges, err
+}
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"niuma-house/internal/model"
 	"niuma-house/pkg/database"
 
@@ -22,6 +24,72 @@ func (r *MessageRepository) Create(message *model.Message) error {
 	return r.db.Create(message).Error
 }
 
+// CreateDedup 按客户端去重键创建私信，重复提交时返回已存在的私信，created 为 false
+func (r *MessageRepository) CreateDedup(message *model.Message) (bool, error) {
+	if message.ClientMsgID == nil || *message.ClientMsgID == "" {
+		message.ClientMsgID = nil
+		return true, r.Create(message)
+	}
+
+	if existing, err := r.FindByClientMsgID(message.SenderID, *message.ClientMsgID); err == nil {
+		*message = *existing
+		return false, nil
+	}
+
+	if err := r.Create(message); err != nil {
+		// 并发重复提交被唯一索引拦截
+		existing, findErr := r.FindByClientMsgID(message.SenderID, *message.ClientMsgID)
+		if findErr != nil {
+			return false, err
+		}
+		*message = *existing
+		return false, nil
+	}
+	return true, nil
+}
+
+// FindByClientMsgID 根据发送者和客户端去重键查找私信
+func (r *MessageRepository) FindByClientMsgID(senderID uint, clientMsgID string) (*model.Message, error) {
+	var message model.Message
+	err := r.db.Where("sender_id = ? AND client_msg_id = ?", senderID, clientMsgID).
+		First(&message).Error
+	if err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// ListSince 获取用户收发的 ID 大于 lastID 的私信（断线重连同步），按 ID 升序
+func (r *MessageRepository) ListSince(userID, lastID uint, limit int) ([]model.Message, error) {
+	var messages []model.Message
+	err := r.db.Where("(sender_id = ? OR receiver_id = ?) AND id > ?", userID, userID, lastID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&messages).Error
+	return messages, err
+}
+
+// MarkDelivered 标记私信已送达，返回是否有更新
+func (r *MessageRepository) MarkDelivered(receiverID, messageID uint) (bool, error) {
+	result := r.db.Model(&model.Message{}).
+		Where("id = ? AND receiver_id = ? AND delivered_at IS NULL", messageID, receiverID).
+		Update("delivered_at", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
+
+// MarkReadUpTo 标记来自 senderID 且 ID 不大于 maxID 的私信为已读
+func (r *MessageRepository) MarkReadUpTo(receiverID, senderID, maxID uint) (int64, error) {
+	now := time.Now()
+	result := r.db.Model(&model.Message{}).
+		Where("receiver_id = ? AND sender_id = ? AND id <= ? AND is_read = false", receiverID, senderID, maxID).
+		Updates(map[string]interface{}{
+			"is_read":      true,
+			"read_at":      now,
+			"delivered_at": gorm.Expr("COALESCE(delivered_at, ?)", now),
+		})
+	return result.RowsAffected, result.Error
+}
+
 // FindByID 根据 ID 查找私信
 func (r *MessageRepository) FindByID(id uint) (*model.Message, error) {
 	var message model.Message
@@ -65,11 +133,16 @@ func (r *MessageRepository) ListByConversation(userID, otherUserID uint, page, s
 	return messages, total, err
 }
 
-// MarkAsRead 标记消息为已读
+// MarkAsRead 标记来自 senderID 的全部消息为已读
 func (r *MessageRepository) MarkAsRead(receiverID, senderID uint) error {
+	now := time.Now()
 	return r.db.Model(&model.Message{}).
 		Where("receiver_id = ? AND sender_id = ? AND is_read = false", receiverID, senderID).
-		Update("is_read", true).Error
+		Updates(map[string]interface{}{
+			"is_read":      true,
+			"read_at":      now,
+			"delivered_at": gorm.Expr("COALESCE(delivered_at, ?)", now),
+		}).Error
 }
 
 // CountUnread 统计未读消息数
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"niuma-house/internal/model"
+	"niuma-house/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+// NotificationRepository 通知仓储
+type NotificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository 创建通知仓储
+func NewNotificationRepository() *NotificationRepository {
+	return &NotificationRepository{db: database.GetDB()}
+}
+
+// Create 创建通知
+func (r *NotificationRepository) Create(notification *model.Notification) error {
+	return r.db.Create(notification).Error
+}
+
+// ListByUserID 获取用户的通知列表
+func (r *NotificationRepository) ListByUserID(userID uint, page, size int) ([]model.Notification, int64, error) {
+	var notifications []model.Notification
+	var total int64
+
+	query := r.db.Model(&model.Notification{}).Where("user_id = ?", userID)
+
+	query.Count(&total)
+
+	offset := (page - 1) * size
+	err := query.Preload("Actor").
+		Order("created_at DESC").
+		Offset(offset).Limit(size).
+		Find(&notifications).Error
+
+	return notifications, total, err
+}
+
+// CountUnread 统计未读通知数
+func (r *NotificationRepository) CountUnread(userID uint) int64 {
+	var count int64
+	r.db.Model(&model.Notification{}).
+		Where("user_id = ? AND is_read = false", userID).
+		Count(&count)
+	return count
+}
+
+// MarkAllAsRead 标记全部通知为已读
+func (r *NotificationRepository) MarkAllAsRead(userID uint) error {
+	return r.db.Model(&model.Notification{}).
+		Where("user_id = ? AND is_read = false", userID).
+		Update("is_read", true).Error
+}
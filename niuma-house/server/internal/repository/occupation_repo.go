@@ -1,7 +1,10 @@
 package repository
 
 import (
+	"context"
+
 	"niuma-house/internal/model"
+	"niuma-house/pkg/cache"
 	"niuma-house/pkg/database"
 )
 
@@ -13,11 +16,13 @@ func NewOccupationRepository() *OccupationRepository {
 	return &OccupationRepository{}
 }
 
-// List 获取所有职业
+// List 获取所有职业（读穿缓存）
 func (r *OccupationRepository) List() ([]model.Occupation, error) {
-	var occupations []model.Occupation
-	err := database.GetDB().Order("id ASC").Find(&occupations).Error
-	return occupations, err
+	return cache.Remember(context.Background(), "occupation", occupationListKey, occupationCacheTTL, func() ([]model.Occupation, error) {
+		var occupations []model.Occupation
+		err := database.GetDB().Order("id ASC").Find(&occupations).Error
+		return occupations, err
+	})
 }
 
 // FindByID 根据 ID 查找职业
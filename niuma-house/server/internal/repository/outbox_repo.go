@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"time"
+
+	"niuma-house/internal/model"
+	"niuma-house/pkg/database"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 发布失败后的重试退避上限
+const outboxMaxBackoff = 5 * time.Minute
+
+// OutboxEvents 业务写入成功后需要发布的事件，在同一事务内写入 outbox
+// 以函数形式传入，便于使用写入后才确定的 ID
+type OutboxEvents func() []*model.OutboxEvent
+
+// writeOutbox 在事务内写入事件
+func writeOutbox(tx *gorm.DB, events OutboxEvents) error {
+	if events == nil {
+		return nil
+	}
+	list := make([]*model.OutboxEvent, 0)
+	for _, event := range events() {
+		if event != nil {
+			list = append(list, event)
+		}
+	}
+	if len(list) == 0 {
+		return nil
+	}
+	return tx.Create(&list).Error
+}
+
+// writeHeldOutbox 在事务内写入暂存事件，关联送审工单，工单驳回后才进入待发送状态
+func writeHeldOutbox(tx *gorm.DB, caseID uint, events OutboxEvents) error {
+	if events == nil {
+		return nil
+	}
+	return writeOutbox(tx, func() []*model.OutboxEvent {
+		list := events()
+		for _, event := range list {
+			if event != nil {
+				event.Status = model.OutboxHeld
+				event.CaseID = &caseID
+			}
+		}
+		return list
+	})
+}
+
+// releaseHeldOutbox 工单驳回（内容恢复）后发布该工单暂存的事件
+func releaseHeldOutbox(tx *gorm.DB, caseID uint) error {
+	return tx.Model(&model.OutboxEvent{}).
+		Where("case_id = ? AND status = ?", caseID, model.OutboxHeld).
+		UpdateColumns(map[string]interface{}{
+			"status":          model.OutboxPending,
+			"next_attempt_at": time.Now(),
+		}).Error
+}
+
+// OutboxRepository 发件箱仓储
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+// NewOutboxRepository 创建发件箱仓储
+func NewOutboxRepository() *OutboxRepository {
+	return &OutboxRepository{db: database.GetDB()}
+}
+
+// Write 单独写入事件，用于没有数据库写操作的业务（如 Redis 中的签到）
+func (r *OutboxRepository) Write(events OutboxEvents) error {
+	return writeOutbox(r.db, events)
+}
+
+// Relay 领取一批到期的待发送事件并逐条发布，返回发布成功的条数
+// 领取时在短事务内用 SKIP LOCKED 锁定并把 next_attempt_at 推迟 lease 作为租约，多实例不会重复领取；
+// 发布在事务外进行，每条事件发布后单独标记，发布失败时按指数退避推迟并停止本批次，
+// 本批次剩余事件在租约到期后重新领取
+func (r *OutboxRepository) Relay(limit int, lease time.Duration, publish func(event *model.OutboxEvent) error) (int, error) {
+	events, err := r.claim(limit, lease)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for i := range events {
+		event := &events[i]
+		if err := publish(event); err != nil {
+			backoff := min(time.Duration(1<<min(event.Attempts, 16))*time.Second, outboxMaxBackoff)
+			return sent, r.db.Model(event).UpdateColumns(map[string]interface{}{
+				"attempts":        event.Attempts + 1,
+				"last_error":      truncate(err.Error(), 500),
+				"next_attempt_at": time.Now().Add(backoff),
+			}).Error
+		}
+
+		if err := r.db.Model(event).UpdateColumns(map[string]interface{}{
+			"status":   model.OutboxSent,
+			"attempts": event.Attempts + 1,
+			"sent_at":  time.Now(),
+		}).Error; err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// claim 锁定一批到期的待发送事件并推迟其 next_attempt_at，租约期内其他实例不会再领取
+func (r *OutboxRepository) claim(limit int, lease time.Duration) ([]model.OutboxEvent, error) {
+	var events []model.OutboxEvent
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", model.OutboxPending, now).
+			Order("id").Limit(limit).Find(&events).Error
+		if err != nil || len(events) == 0 {
+			return err
+		}
+
+		ids := make([]uint, len(events))
+		for i := range events {
+			ids[i] = events[i].ID
+		}
+		return tx.Model(&model.OutboxEvent{}).Where("id IN ?", ids).
+			UpdateColumn("next_attempt_at", now.Add(lease)).Error
+	})
+	return events, err
+}
+
+// PurgeSent 删除指定时间之前已发送的事件
+func (r *OutboxRepository) PurgeSent(before time.Time) (int64, error) {
+	result := r.db.Where("status = ? AND sent_at < ?", model.OutboxSent, before).
+		Delete(&model.OutboxEvent{})
+	return result.RowsAffected, result.Error
+}
+
+// truncate 按字符数截断字符串
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
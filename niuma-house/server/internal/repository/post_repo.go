@@ -1,7 +1,11 @@
 package repository
 
 import (
+	"context"
+	"time"
+
 	"niuma-house/internal/model"
+	"niuma-house/pkg/cache"
 	"niuma-house/pkg/database"
 
 	"gorm.io/gorm"
@@ -17,35 +21,91 @@ func NewPostRepository() *PostRepository {
 	return &PostRepository{db: database.GetDB()}
 }
 
-// Create 创建帖子
-func (r *PostRepository) Create(post *model.Post) error {
-	return r.db.Create(post).Error
+// Create 创建帖子，events 与帖子在同一事务中写入 outbox
+// hold 不为空时帖子需以隐藏状态传入，同一事务内创建送审工单，events 暂存到工单驳回后发布
+func (r *PostRepository) Create(post *model.Post, events OutboxEvents, hold *ContentHold) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(post).Error; err != nil {
+			return err
+		}
+		return writeContent(tx, model.ReportTargetPost, post.ID, hold, true, events)
+	})
+	if err != nil {
+		return err
+	}
+	invalidatePost(0)
+	return nil
 }
 
-// FindByID 根据 ID 查找帖子
+// FindByID 根据 ID 查找帖子（读穿缓存）
 func (r *PostRepository) FindByID(id uint) (*model.Post, error) {
-	var post model.Post
-	err := r.db.Preload("User").Preload("Occupation").
-		Where("status > 0").First(&post, id).Error
+	return cache.Remember(context.Background(), "post", postCacheKey(id), postCacheTTL, func() (*model.Post, error) {
+		var post model.Post
+		err := r.db.Preload("User").Preload("Occupation").
+			Where("status > 0").First(&post, id).Error
+		if err != nil {
+			return nil, err
+		}
+		return &post, nil
+	})
+}
+
+// UpdateContent 只更新标题和正文，不回写计数、置顶和状态等可能已被并发修改的字段
+// hold 不为空时在同一事务内隐藏帖子并送审
+func (r *PostRepository) UpdateContent(id uint, title, content string, hold *ContentHold) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.Post{}).Where("id = ?", id).
+			Updates(map[string]interface{}{"title": title, "content": content}).Error; err != nil {
+			return err
+		}
+		return writeContent(tx, model.ReportTargetPost, id, hold, false, nil)
+	})
 	if err != nil {
-		return nil, err
+		return err
 	}
-	return &post, nil
+	invalidatePost(id)
+	return nil
 }
 
-// Update 更新帖子
-func (r *PostRepository) Update(post *model.Post) error {
-	return r.db.Save(post).Error
+// Delete 删除帖子（软删除），events 在同一事务中写入 outbox
+func (r *PostRepository) Delete(id uint, events OutboxEvents) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.Post{}).Where("id = ?", id).
+			Update("status", 0).Error; err != nil {
+			return err
+		}
+		return writeOutbox(tx, events)
+	})
+	if err != nil {
+		return err
+	}
+	invalidatePost(id)
+	return nil
 }
 
-// Delete 删除帖子（软删除）
-func (r *PostRepository) Delete(id uint) error {
-	return r.db.Model(&model.Post{}).Where("id = ?", id).
-		Update("status", 0).Error
+// List 帖子列表，前几页走缓存
+func (r *PostRepository) List(occupationID uint, page, size int) ([]model.Post, int64, error) {
+	if page > postFeedCachePages {
+		return r.list(occupationID, page, size)
+	}
+
+	ctx := context.Background()
+	key := postFeedCacheKey(postFeedVersion(ctx), occupationID, page, size)
+	result, err := cache.Remember(ctx, "post_feed", key, postFeedCacheTTL, func() (*pageResult[model.Post], error) {
+		posts, total, err := r.list(occupationID, page, size)
+		if err != nil {
+			return nil, err
+		}
+		return &pageResult[model.Post]{List: posts, Total: total}, nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return result.List, result.Total, nil
 }
 
-// List 帖子列表
-func (r *PostRepository) List(occupationID uint, page, size int) ([]model.Post, int64, error) {
+// list 从数据库查询帖子列表
+func (r *PostRepository) list(occupationID uint, page, size int) ([]model.Post, int64, error) {
 	var posts []model.Post
 	var total int64
 
@@ -67,26 +127,44 @@ func (r *PostRepository) List(occupationID uint, page, size int) ([]model.Post,
 
 // IncrementLikes 增加点赞数
 func (r *PostRepository) IncrementLikes(postID uint) error {
-	return r.db.Model(&model.Post{}).Where("id = ?", postID).
+	err := r.db.Model(&model.Post{}).Where("id = ?", postID).
 		UpdateColumn("likes_count", gorm.Expr("likes_count + 1")).Error
+	if err != nil {
+		return err
+	}
+	invalidatePost(postID)
+	return nil
 }
 
 // DecrementLikes 减少点赞数
 func (r *PostRepository) DecrementLikes(postID uint) error {
-	return r.db.Model(&model.Post{}).Where("id = ?", postID).
+	err := r.db.Model(&model.Post{}).Where("id = ?", postID).
 		UpdateColumn("likes_count", gorm.Expr("likes_count - 1")).Error
+	if err != nil {
+		return err
+	}
+	invalidatePost(postID)
+	return nil
 }
 
-// IncrementViews 增加浏览数
-func (r *PostRepository) IncrementViews(postID uint) error {
-	return r.db.Model(&model.Post{}).Where("id = ?", postID).
-		UpdateColumn("views_count", gorm.Expr("views_count + 1")).Error
+// CountByUserSince 统计用户自 since 起发布的帖子数（含已删除，删帖不返还发帖额度）
+func (r *PostRepository) CountByUserSince(userID uint, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.Post{}).
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Count(&count).Error
+	return count, err
 }
 
 // SetTop 置顶帖子
 func (r *PostRepository) SetTop(postID uint) error {
-	return r.db.Model(&model.Post{}).Where("id = ?", postID).
+	err := r.db.Model(&model.Post{}).Where("id = ?", postID).
 		Update("status", 2).Error
+	if err != nil {
+		return err
+	}
+	invalidatePost(postID)
+	return nil
 }
 
 // AdminList 管理端列表（含已删除）
@@ -115,16 +193,26 @@ func NewLikeRepository() *LikeRepository {
 	return &LikeRepository{db: database.GetDB()}
 }
 
-// Like 点赞
-func (r *LikeRepository) Like(postID, userID uint) error {
-	like := model.PostLike{PostID: postID, UserID: userID}
-	return r.db.Create(&like).Error
+// Like 点赞，events 在同一事务中写入 outbox
+func (r *LikeRepository) Like(postID, userID uint, events OutboxEvents) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		like := model.PostLike{PostID: postID, UserID: userID}
+		if err := tx.Create(&like).Error; err != nil {
+			return err
+		}
+		return writeOutbox(tx, events)
+	})
 }
 
-// Unlike 取消点赞
-func (r *LikeRepository) Unlike(postID, userID uint) error {
-	return r.db.Where("post_id = ? AND user_id = ?", postID, userID).
-		Delete(&model.PostLike{}).Error
+// Unlike 取消点赞，events 在同一事务中写入 outbox
+func (r *LikeRepository) Unlike(postID, userID uint, events OutboxEvents) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("post_id = ? AND user_id = ?", postID, userID).
+			Delete(&model.PostLike{}).Error; err != nil {
+			return err
+		}
+		return writeOutbox(tx, events)
+	})
 }
 
 // IsLiked 是否已点赞
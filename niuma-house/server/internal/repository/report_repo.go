@@ -0,0 +1,383 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"niuma-house/internal/model"
+	"niuma-house/pkg/database"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// reportTargetColumns 各类可举报内容的表、作者字段、快照表达式及是否支持匿名
+var reportTargetColumns = map[string]struct {
+	table, owner, snapshot string
+	anonymous              bool
+}{
+	model.ReportTargetPost:    {"posts", "user_id", "CONCAT(title, '\\n', content)", true},
+	model.ReportTargetComment: {"comments", "user_id", "content", true},
+	model.ReportTargetCompany: {"companies", "creator_id", "CONCAT(name, '\\n', IFNULL(content, ''))", true},
+	model.ReportTargetUser:    {"users", "id", "CONCAT(username, ' ', IFNULL(nickname, ''))", false},
+	model.ReportTargetMessage: {"messages", "sender_id", "content", false},
+}
+
+// hideableTargets 可自动隐藏的内容：表、可见状态条件及隐藏状态
+var hideableTargets = map[string]struct {
+	table, visible string
+	hidden         int
+}{
+	model.ReportTargetPost:    {"posts", "status > 0", model.PostStatusHidden},
+	model.ReportTargetComment: {"comments", "status = 1", model.CommentStatusHidden},
+	model.ReportTargetCompany: {"companies", "status = 1", model.CompanyStatusHidden},
+}
+
+var (
+	// ErrUnknownReportTarget 不支持的举报类型
+	ErrUnknownReportTarget = errors.New("unknown report target type")
+	// ErrAlreadyReported 已举报过该内容
+	ErrAlreadyReported = errors.New("已举报过该内容")
+	// ErrCaseClosed 工单已处置，不能再修改
+	ErrCaseClosed = errors.New("工单已处置")
+)
+
+// ReportTarget 被举报内容的基本信息
+type ReportTarget struct {
+	OwnerID   uint
+	Snapshot  string
+	Anonymous bool
+}
+
+// CaseFilter 审核队列筛选条件，零值表示不过滤
+type CaseFilter struct {
+	Status     string
+	TargetType string
+	AssigneeID uint
+}
+
+// ReportRepository 举报及审核工单仓储
+type ReportRepository struct {
+	db *gorm.DB
+}
+
+// NewReportRepository 创建举报仓储
+func NewReportRepository() *ReportRepository {
+	return &ReportRepository{db: database.GetDB()}
+}
+
+// Target 查询被举报内容（已删除的内容视为不存在）
+func (r *ReportRepository) Target(targetType string, targetID uint) (*ReportTarget, error) {
+	return findReportTarget(r.db, targetType, targetID)
+}
+
+// findReportTarget 查询被举报内容，可在事务内调用
+func findReportTarget(db *gorm.DB, targetType string, targetID uint) (*ReportTarget, error) {
+	col, ok := reportTargetColumns[targetType]
+	if !ok {
+		return nil, ErrUnknownReportTarget
+	}
+
+	anonymous := "FALSE"
+	if col.anonymous {
+		anonymous = "anonymous"
+	}
+	query := db.Table(col.table).
+		Select(col.owner+" AS owner_id, "+col.snapshot+" AS snapshot, "+anonymous+" AS anonymous").
+		Where("id = ?", targetID)
+	if targetType != model.ReportTargetMessage {
+		query = query.Where("deleted_at IS NULL")
+	}
+	if _, ok := hideableTargets[targetType]; ok {
+		query = query.Where("status <> 0")
+	}
+
+	var targets []ReportTarget
+	if err := query.Limit(1).Scan(&targets).Error; err != nil {
+		return nil, err
+	}
+	if len(targets) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &targets[0], nil
+}
+
+// Submit 写入举报并汇总到工单：同一内容的首个举报创建工单，被驳回的工单收到新举报时重新待处理；
+// 待处理工单自上次审核后的举报数达到 hideThreshold 时自动隐藏内容（hideThreshold 为 0 时不隐藏）
+func (r *ReportRepository) Submit(report *model.Report, target *ReportTarget, hideThreshold int) (*model.ModerationCase, error) {
+	var mc model.ModerationCase
+	hidden := false
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("target_type = ? AND target_id = ?", report.TargetType, report.TargetID).
+			First(&mc).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			mc = model.ModerationCase{
+				TargetType:   report.TargetType,
+				TargetID:     report.TargetID,
+				TargetUserID: target.OwnerID,
+				Anonymous:    target.Anonymous,
+				Snapshot:     target.Snapshot,
+				Status:       model.ModerationPending,
+				Reasons:      model.TagCounts{},
+			}
+			err = tx.Create(&mc).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		// 工单行已加锁，同一内容的举报串行处理
+		var count int64
+		tx.Model(&model.Report{}).
+			Where("reporter_id = ? AND target_type = ? AND target_id = ?", report.ReporterID, report.TargetType, report.TargetID).
+			Count(&count)
+		if count > 0 {
+			return ErrAlreadyReported
+		}
+
+		report.CaseID = mc.ID
+		if err := tx.Create(report).Error; err != nil {
+			return err
+		}
+
+		mc.ReportCount++
+		if mc.Reasons == nil {
+			mc.Reasons = model.TagCounts{}
+		}
+		mc.Reasons[report.Reason]++
+		if mc.Status == model.ModerationRejected {
+			mc.Status = model.ModerationPending
+		}
+
+		if mc.Status == model.ModerationPending && !mc.Hidden && hideThreshold > 0 &&
+			mc.ReportCount-mc.ReviewedCount >= hideThreshold {
+			if hidden, err = hideTarget(tx, &mc); err != nil {
+				return err
+			}
+		}
+		return tx.Save(&mc).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	if hidden {
+		invalidateReportTarget(mc.TargetType, mc.TargetID)
+	}
+	return &mc, nil
+}
+
+// ContentHold 内容过滤要求人工审核时，随内容写入一起提交的送审信息
+type ContentHold struct {
+	Hits string // 命中的分类和词
+}
+
+// holdTarget 在写入内容的同一事务内将内容送审：创建或重新打开该内容的工单并隐藏内容，
+// created 表示内容是新写入的且已以隐藏状态插入，恢复时改为正常状态；
+// events 以暂存状态写入 outbox 并关联工单，工单驳回（内容恢复）后才发布
+// 审核员通过时内容保持隐藏，驳回时恢复，与举报工单的处理方式一致
+func holdTarget(tx *gorm.DB, targetType string, targetID uint, hold *ContentHold, created bool, events OutboxEvents) error {
+	target, err := findReportTarget(tx, targetType, targetID)
+	if err != nil {
+		return err
+	}
+
+	var mc model.ModerationCase
+	err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("target_type = ? AND target_id = ?", targetType, targetID).
+		First(&mc).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		mc = model.ModerationCase{
+			TargetType:   targetType,
+			TargetID:     targetID,
+			TargetUserID: target.OwnerID,
+			Anonymous:    target.Anonymous,
+			Reasons:      model.TagCounts{},
+		}
+		err = tx.Create(&mc).Error
+	}
+	if err != nil {
+		return err
+	}
+
+	if mc.Reasons == nil {
+		mc.Reasons = model.TagCounts{}
+	}
+	mc.Reasons[model.ModerationReasonFilter]++
+	mc.FilterHits = hold.Hits
+	mc.Snapshot = target.Snapshot
+	mc.Status = model.ModerationPending
+
+	switch {
+	case created:
+		mc.Hidden = true
+		mc.HiddenStatus = 1
+	case !mc.Hidden:
+		if _, err := hideTarget(tx, &mc); err != nil {
+			return err
+		}
+	}
+	if err := tx.Save(&mc).Error; err != nil {
+		return err
+	}
+	return writeHeldOutbox(tx, mc.ID, events)
+}
+
+// writeContent 在事务内提交内容写入附带的事件：需要送审时随工单暂存，否则直接写入 outbox
+func writeContent(tx *gorm.DB, targetType string, targetID uint, hold *ContentHold, created bool, events OutboxEvents) error {
+	if hold != nil {
+		return holdTarget(tx, targetType, targetID, hold, created, events)
+	}
+	return writeOutbox(tx, events)
+}
+
+// FindCase 工单详情，含全部举报记录
+func (r *ReportRepository) FindCase(id uint) (*model.ModerationCase, error) {
+	var mc model.ModerationCase
+	err := r.db.Preload("TargetUser").Preload("Assignee").Preload("Resolver").
+		Preload("Reports", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Preload("Reports.Reporter").
+		First(&mc, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &mc, nil
+}
+
+// ListCases 审核队列，按举报数从多到少、先到先处理排序
+func (r *ReportRepository) ListCases(filter *CaseFilter, page, size int) ([]model.ModerationCase, int64, error) {
+	var cases []model.ModerationCase
+	var total int64
+
+	query := r.db.Model(&model.ModerationCase{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.AssigneeID > 0 {
+		query = query.Where("assignee_id = ?", filter.AssigneeID)
+	}
+	query.Count(&total)
+
+	offset := (page - 1) * size
+	err := query.Preload("TargetUser").Preload("Assignee").
+		Order("report_count DESC, id ASC").
+		Offset(offset).Limit(size).
+		Find(&cases).Error
+	return cases, total, err
+}
+
+// Assign 分配工单给审核员，assigneeID 为 nil 时取消分配
+func (r *ReportRepository) Assign(caseID uint, assigneeID *uint) error {
+	result := r.db.Model(&model.ModerationCase{}).
+		Where("id = ? AND status <> ?", caseID, model.ModerationActioned).
+		Update("assignee_id", assigneeID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrCaseClosed
+	}
+	return nil
+}
+
+// Resolve 结案：approved 时隐藏内容，rejected 时恢复被隐藏的内容并发布送审期间暂存的事件，
+// actioned 的处置由调用方先行完成
+func (r *ReportRepository) Resolve(caseID, resolverID uint, status, action, note string) (*model.ModerationCase, error) {
+	var mc model.ModerationCase
+	changed := false
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&mc, caseID).Error; err != nil {
+			return err
+		}
+		if mc.Status == model.ModerationActioned {
+			return ErrCaseClosed
+		}
+
+		var err error
+		switch status {
+		case model.ModerationApproved:
+			if !mc.Hidden {
+				changed, err = hideTarget(tx, &mc)
+			}
+		case model.ModerationRejected:
+			if mc.Hidden {
+				changed, err = restoreTarget(tx, &mc)
+			}
+			if err == nil {
+				err = releaseHeldOutbox(tx, mc.ID)
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		mc.Status = status
+		mc.Action = action
+		mc.Note = note
+		mc.ResolverID = &resolverID
+		mc.ResolvedAt = &now
+		mc.ReviewedCount = mc.ReportCount
+		return tx.Save(&mc).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		invalidateReportTarget(mc.TargetType, mc.TargetID)
+	}
+	return &mc, nil
+}
+
+// hideTarget 隐藏可见的内容并记录原状态，内容不可隐藏或已不可见时返回 false
+func hideTarget(tx *gorm.DB, mc *model.ModerationCase) (bool, error) {
+	t, ok := hideableTargets[mc.TargetType]
+	if !ok {
+		return false, nil
+	}
+
+	var statuses []int
+	err := tx.Table(t.table).Where("id = ?", mc.TargetID).Where(t.visible).
+		Limit(1).Pluck("status", &statuses).Error
+	if err != nil || len(statuses) == 0 {
+		return false, err
+	}
+
+	if err := tx.Table(t.table).Where("id = ?", mc.TargetID).
+		Update("status", t.hidden).Error; err != nil {
+		return false, err
+	}
+	mc.Hidden = true
+	mc.HiddenStatus = statuses[0]
+	return true, nil
+}
+
+// restoreTarget 恢复被隐藏的内容，期间已被删除的内容保持删除
+func restoreTarget(tx *gorm.DB, mc *model.ModerationCase) (bool, error) {
+	t, ok := hideableTargets[mc.TargetType]
+	if !ok {
+		return false, nil
+	}
+
+	if err := tx.Table(t.table).Where("id = ? AND status = ?", mc.TargetID, t.hidden).
+		Update("status", mc.HiddenStatus).Error; err != nil {
+		return false, err
+	}
+	mc.Hidden = false
+	return true, nil
+}
+
+// invalidateReportTarget 内容隐藏或恢复后失效相关缓存
+func invalidateReportTarget(targetType string, targetID uint) {
+	switch targetType {
+	case model.ReportTargetPost:
+		invalidatePost(targetID)
+	case model.ReportTargetCompany:
+		invalidateCompany(targetID)
+	}
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"niuma-house/internal/model"
+	"niuma-house/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+// ErrSanctionLifted 处罚已解除
+var ErrSanctionLifted = errors.New("处罚已解除")
+
+// SanctionFilter 处罚记录筛选条件，零值表示不过滤
+type SanctionFilter struct {
+	UserID     uint
+	Type       string
+	ActiveOnly bool
+}
+
+// SanctionRepository 用户处罚仓储
+type SanctionRepository struct {
+	db *gorm.DB
+}
+
+// NewSanctionRepository 创建用户处罚仓储
+func NewSanctionRepository() *SanctionRepository {
+	return &SanctionRepository{db: database.GetDB()}
+}
+
+// Create 记录处罚
+func (r *SanctionRepository) Create(sanction *model.UserSanction) error {
+	return r.db.Create(sanction).Error
+}
+
+// FindByID 根据 ID 查找处罚
+func (r *SanctionRepository) FindByID(id uint) (*model.UserSanction, error) {
+	var sanction model.UserSanction
+	err := r.db.First(&sanction, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &sanction, nil
+}
+
+// Active 用户当前生效的处罚，永久处罚在前，其余按到期时间倒序
+func (r *SanctionRepository) Active(userID uint) ([]model.UserSanction, error) {
+	var sanctions []model.UserSanction
+	err := r.active(r.db, userID).
+		Order("expires_at IS NULL DESC, expires_at DESC").
+		Find(&sanctions).Error
+	return sanctions, err
+}
+
+// FindActive 用户当前生效的、限制 sanctionType 的处罚中最晚到期的一条，没有时返回 gorm.ErrRecordNotFound
+func (r *SanctionRepository) FindActive(userID uint, sanctionType string) (*model.UserSanction, error) {
+	var sanction model.UserSanction
+	err := r.active(r.db, userID).
+		Where("type IN ?", []string{sanctionType, model.SanctionBan}).
+		Order("expires_at IS NULL DESC, expires_at DESC").
+		First(&sanction).Error
+	if err != nil {
+		return nil, err
+	}
+	return &sanction, nil
+}
+
+// Lift 解除处罚，liftedBy 为空表示到期自动解除
+func (r *SanctionRepository) Lift(id uint, liftedBy *uint) error {
+	result := r.db.Model(&model.UserSanction{}).
+		Where("id = ? AND lifted_at IS NULL", id).
+		Updates(map[string]any{"lifted_at": time.Now(), "lifted_by": liftedBy})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrSanctionLifted
+	}
+	return nil
+}
+
+// LiftByType 解除用户所有生效中的 sanctionType 处罚，返回解除的条数
+func (r *SanctionRepository) LiftByType(userID uint, sanctionType string, liftedBy *uint) (int64, error) {
+	result := r.active(r.db.Model(&model.UserSanction{}), userID).
+		Where("type = ?", sanctionType).
+		Updates(map[string]any{"lifted_at": time.Now(), "lifted_by": liftedBy})
+	return result.RowsAffected, result.Error
+}
+
+// Expired 已到期但尚未解除的处罚，最多 limit 条
+func (r *SanctionRepository) Expired(now time.Time, limit int) ([]model.UserSanction, error) {
+	var sanctions []model.UserSanction
+	err := r.db.Where("lifted_at IS NULL AND expires_at <= ?", now).
+		Order("expires_at").
+		Limit(limit).
+		Find(&sanctions).Error
+	return sanctions, err
+}
+
+// List 处罚记录列表，按时间倒序
+func (r *SanctionRepository) List(filter *SanctionFilter, page, size int) ([]model.UserSanction, int64, error) {
+	var sanctions []model.UserSanction
+	var total int64
+
+	query := r.db.Model(&model.UserSanction{})
+	if filter.UserID > 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.ActiveOnly {
+		query = query.Where("lifted_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", time.Now())
+	}
+	query.Count(&total)
+
+	offset := (page - 1) * size
+	err := query.Preload("User").Preload("Issuer").
+		Order("id DESC").
+		Offset(offset).Limit(size).
+		Find(&sanctions).Error
+	return sanctions, total, err
+}
+
+// active 生效中的处罚：未解除且未到期
+func (r *SanctionRepository) active(query *gorm.DB, userID uint) *gorm.DB {
+	return query.Where("user_id = ? AND lifted_at IS NULL AND (expires_at IS NULL OR expires_at > ?)",
+		userID, time.Now())
+}
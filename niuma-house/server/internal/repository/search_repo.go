@@ -0,0 +1,377 @@
+package repository
+
+import (
+	"context"
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"niuma-house/internal/model"
+	"niuma-house/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+// ngram 分词长度（MySQL ngram_token_size 默认值），更短的关键词走 LIKE 匹配
+const ngramTokenSize = 2
+
+// 高亮片段长度（字符数）
+const highlightSnippetSize = 120
+
+// SearchQuery 搜索条件
+type SearchQuery struct {
+	Keyword      string
+	City         string // 公司：城市
+	Tag          string // 公司：避雷标签
+	RiskLevel    int    // 公司：最低避雷等级
+	OccupationID uint   // 帖子：职业分类
+	Page         int
+	Size         int
+}
+
+// PostHit 帖子搜索结果
+type PostHit struct {
+	model.Post
+	Score     float64           `json:"score"`
+	Highlight map[string]string `json:"highlight,omitempty"` // 字段 -> 高亮后的 HTML 片段
+}
+
+// CompanyHit 公司搜索结果
+type CompanyHit struct {
+	model.Company
+	Score     float64           `json:"score"`
+	Highlight map[string]string `json:"highlight,omitempty"`
+}
+
+// Searcher 搜索接口
+type Searcher interface {
+	SearchPosts(ctx context.Context, q *SearchQuery) ([]PostHit, int64, error)
+	SearchCompanies(ctx context.Context, q *SearchQuery) ([]CompanyHit, int64, error)
+}
+
+// SearchIndexer 搜索索引同步钩子，在内容创建、更新、删除后调用
+type SearchIndexer interface {
+	IndexPost(ctx context.Context, post *model.Post) error
+	RemovePost(ctx context.Context, postID uint) error
+	IndexCompany(ctx context.Context, company *model.Company) error
+	RemoveCompany(ctx context.Context, companyID uint) error
+}
+
+// searchScore 相关度查询结果
+type searchScore struct {
+	ID    uint
+	Score float64
+}
+
+// MySQLSearcher MySQL 全文搜索实现（FULLTEXT + ngram 分词）
+type MySQLSearcher struct {
+	db *gorm.DB
+}
+
+// NewMySQLSearcher 创建 MySQL 搜索器
+func NewMySQLSearcher() *MySQLSearcher {
+	return &MySQLSearcher{db: database.GetDB()}
+}
+
+// SearchPosts 搜索帖子标题和内容
+func (s *MySQLSearcher) SearchPosts(ctx context.Context, q *SearchQuery) ([]PostHit, int64, error) {
+	query := s.db.WithContext(ctx).Model(&model.Post{}).Where("status > 0")
+	if q.OccupationID > 0 {
+		query = query.Where("occupation_id = ?", q.OccupationID)
+	}
+
+	keyword := strings.TrimSpace(q.Keyword)
+	query, scoreExpr, scoreArgs := matchKeyword(query, keyword, "title, content", []string{"title", "content"})
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var scores []searchScore
+	err := query.Select("id, "+scoreExpr+" AS score", scoreArgs...).
+		Order("score DESC, status DESC, created_at DESC").
+		Offset((q.Page - 1) * q.Size).Limit(q.Size).
+		Scan(&scores).Error
+	if err != nil || len(scores) == 0 {
+		return []PostHit{}, total, err
+	}
+
+	var posts []model.Post
+	if err := s.db.WithContext(ctx).Preload("User").Preload("Occupation").
+		Where("id IN ?", scoreIDs(scores)).Find(&posts).Error; err != nil {
+		return nil, 0, err
+	}
+	byID := make(map[uint]model.Post, len(posts))
+	for _, post := range posts {
+		byID[post.ID] = post
+	}
+
+	terms := strings.Fields(keyword)
+	hits := make([]PostHit, 0, len(scores))
+	for _, sc := range scores {
+		post, ok := byID[sc.ID]
+		if !ok {
+			continue
+		}
+		hits = append(hits, PostHit{
+			Post:  post,
+			Score: sc.Score,
+			Highlight: highlightFields(terms, map[string]highlightField{
+				"title":   {text: post.Title},
+				"content": {text: post.Content, snippet: true},
+			}),
+		})
+	}
+	return hits, total, nil
+}
+
+// SearchCompanies 搜索公司名称、城市、描述和标签
+func (s *MySQLSearcher) SearchCompanies(ctx context.Context, q *SearchQuery) ([]CompanyHit, int64, error) {
+	query := s.db.WithContext(ctx).Model(&model.Company{}).Where("status = ?", model.CompanyStatusNormal)
+	if q.City != "" {
+		query = query.Where("city = ?", q.City)
+	}
+	if q.Tag != "" {
+		query = query.Where("JSON_CONTAINS(tags, JSON_QUOTE(?))", q.Tag)
+	}
+	if q.RiskLevel > 0 {
+		query = query.Where("risk_level >= ?", q.RiskLevel)
+	}
+
+	keyword := strings.TrimSpace(q.Keyword)
+	query, scoreExpr, scoreArgs := matchKeyword(query, keyword,
+		"name, city, content, search_tags", []string{"name", "city", "content", "search_tags"})
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var scores []searchScore
+	err := query.Select("id, "+scoreExpr+" AS score", scoreArgs...).
+		Order("score DESC, avg_risk DESC, view_count DESC").
+		Offset((q.Page - 1) * q.Size).Limit(q.Size).
+		Scan(&scores).Error
+	if err != nil || len(scores) == 0 {
+		return []CompanyHit{}, total, err
+	}
+
+	var companies []model.Company
+	if err := s.db.WithContext(ctx).Preload("Creator").
+		Where("id IN ?", scoreIDs(scores)).Find(&companies).Error; err != nil {
+		return nil, 0, err
+	}
+	byID := make(map[uint]model.Company, len(companies))
+	for _, company := range companies {
+		byID[company.ID] = company
+	}
+
+	terms := strings.Fields(keyword)
+	hits := make([]CompanyHit, 0, len(scores))
+	for _, sc := range scores {
+		company, ok := byID[sc.ID]
+		if !ok {
+			continue
+		}
+		hits = append(hits, CompanyHit{
+			Company: company,
+			Score:   sc.Score,
+			Highlight: highlightFields(terms, map[string]highlightField{
+				"name":    {text: company.Name},
+				"city":    {text: company.City},
+				"content": {text: company.Content, snippet: true},
+				"tags":    {text: company.SearchTags},
+			}),
+		})
+	}
+	return hits, total, nil
+}
+
+// IndexPost MySQL 全文索引随写入自动更新，无需额外处理
+func (s *MySQLSearcher) IndexPost(ctx context.Context, post *model.Post) error {
+	return nil
+}
+
+// RemovePost 删除的帖子通过 status 过滤，无需额外处理
+func (s *MySQLSearcher) RemovePost(ctx context.Context, postID uint) error {
+	return nil
+}
+
+// IndexCompany MySQL 全文索引随写入自动更新（标签由 search_tags 字段冗余），无需额外处理
+func (s *MySQLSearcher) IndexCompany(ctx context.Context, company *model.Company) error {
+	return nil
+}
+
+// RemoveCompany 删除或合并的公司通过 status 过滤，无需额外处理
+func (s *MySQLSearcher) RemoveCompany(ctx context.Context, companyID uint) error {
+	return nil
+}
+
+// matchKeyword 追加关键词条件，返回相关度表达式
+// 关键词长度不足 ngram 分词长度时无法命中全文索引，退化为 LIKE 匹配
+func matchKeyword(query *gorm.DB, keyword, fullTextColumns string, likeColumns []string) (*gorm.DB, string, []interface{}) {
+	if keyword == "" {
+		return query, "0", nil
+	}
+
+	if utf8.RuneCountInString(keyword) < ngramTokenSize {
+		like := "%" + keyword + "%"
+		conds := make([]string, len(likeColumns))
+		args := make([]interface{}, len(likeColumns))
+		for i, col := range likeColumns {
+			conds[i] = col + " LIKE ?"
+			args[i] = like
+		}
+		return query.Where(strings.Join(conds, " OR "), args...), "0", nil
+	}
+
+	match := "MATCH(" + fullTextColumns + ") AGAINST (? IN NATURAL LANGUAGE MODE)"
+	return query.Where(match, keyword), match, []interface{}{keyword}
+}
+
+func scoreIDs(scores []searchScore) []uint {
+	ids := make([]uint, len(scores))
+	for i, sc := range scores {
+		ids[i] = sc.ID
+	}
+	return ids
+}
+
+// highlightField 待高亮的字段
+type highlightField struct {
+	text    string
+	snippet bool // 长文本只截取命中附近的片段
+}
+
+// highlightFields 对命中关键词的字段生成高亮 HTML，未命中的字段不返回
+func highlightFields(terms []string, fields map[string]highlightField) map[string]string {
+	if len(terms) == 0 {
+		return nil
+	}
+
+	result := make(map[string]string)
+	for name, field := range fields {
+		maxRunes := 0
+		if field.snippet {
+			maxRunes = highlightSnippetSize
+		}
+		if text, ok := highlight(field.text, terms, maxRunes); ok {
+			result[name] = text
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// highlight 用 <em> 标记关键词（忽略大小写），其余文本做 HTML 转义
+// maxRunes > 0 时只保留首个命中附近的片段
+func highlight(text string, terms []string, maxRunes int) (string, bool) {
+	runes := []rune(text)
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	// 找出所有命中区间 [start, end)
+	var spans [][2]int
+	for _, term := range terms {
+		t := []rune(strings.ToLower(term))
+		if len(t) == 0 {
+			continue
+		}
+		for i := 0; i+len(t) <= len(lower); i++ {
+			if string(lower[i:i+len(t)]) == string(t) {
+				spans = append(spans, [2]int{i, i + len(t)})
+			}
+		}
+	}
+	if len(spans) == 0 {
+		return "", false
+	}
+
+	// 合并重叠区间
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+	merged := spans[:1]
+	for _, sp := range spans[1:] {
+		last := &merged[len(merged)-1]
+		if sp[0] <= last[1] {
+			if sp[1] > last[1] {
+				last[1] = sp[1]
+			}
+			continue
+		}
+		merged = append(merged, sp)
+	}
+
+	from, to := 0, len(runes)
+	if maxRunes > 0 && len(runes) > maxRunes {
+		from = merged[0][0] - maxRunes/4
+		if from < 0 {
+			from = 0
+		}
+		to = from + maxRunes
+		if to > len(runes) {
+			to = len(runes)
+			from = to - maxRunes
+		}
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("...")
+	}
+	pos := from
+	for _, sp := range merged {
+		start, end := sp[0], sp[1]
+		if end <= from || start >= to {
+			continue
+		}
+		if start < from {
+			start = from
+		}
+		if end > to {
+			end = to
+		}
+		b.WriteString(html.EscapeString(string(runes[pos:start])))
+		b.WriteString("<em>")
+		b.WriteString(html.EscapeString(string(runes[start:end])))
+		b.WriteString("</em>")
+		pos = end
+	}
+	b.WriteString(html.EscapeString(string(runes[pos:to])))
+	if to < len(runes) {
+		b.WriteString("...")
+	}
+	return b.String(), true
+}
+
+// ElasticSearcher Elasticsearch 搜索实现（预留）
+// type ElasticSearcher struct {
+//     client *elastic.Client
+// }
+//
+// func NewElasticSearcher(client *elastic.Client) *ElasticSearcher {
+//     return &ElasticSearcher{client: client}
+// }
+//
+// func (s *ElasticSearcher) SearchPosts(ctx context.Context, q *SearchQuery) ([]PostHit, int64, error) {
+//     // TODO: 实现 Elasticsearch 搜索
+//     return nil, 0, nil
+// }
+//
+// func (s *ElasticSearcher) SearchCompanies(ctx context.Context, q *SearchQuery) ([]CompanyHit, int64, error) {
+//     // TODO: 实现 Elasticsearch 搜索
+//     return nil, 0, nil
+// }
+//
+// func (s *ElasticSearcher) IndexPost(ctx context.Context, post *model.Post) error {
+//     // TODO: 写入 Elasticsearch 索引
+//     return nil
+// }
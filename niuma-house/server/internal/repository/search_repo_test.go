@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+func TestHighlight(t *testing.T) {
+	long := strings.Repeat("一", 100) + "加班" + strings.Repeat("二", 100)
+
+	tests := []struct {
+		name     string
+		text     string
+		terms    []string
+		maxRunes int
+		want     string
+		wantOK   bool
+	}{
+		{
+			name:   "未命中",
+			text:   "准时下班",
+			terms:  []string{"加班"},
+			wantOK: false,
+		},
+		{
+			name:   "忽略大小写，保留原文大小写",
+			text:   "Golang 后端",
+			terms:  []string{"GO"},
+			want:   "<em>Go</em>lang 后端",
+			wantOK: true,
+		},
+		{
+			name:   "同一个词多次命中",
+			text:   "加班，又加班",
+			terms:  []string{"加班"},
+			want:   "<em>加班</em>，又<em>加班</em>",
+			wantOK: true,
+		},
+		{
+			name:   "多个词重叠时合并为一个区间",
+			text:   "周末加班费",
+			terms:  []string{"加班费", "周末加班"},
+			want:   "<em>周末加班费</em>",
+			wantOK: true,
+		},
+		{
+			name:   "多个词相邻时合并",
+			text:   "大小周",
+			terms:  []string{"大小", "周"},
+			want:   "<em>大小周</em>",
+			wantOK: true,
+		},
+		{
+			name:   "一个词包含另一个词",
+			text:   "无偿加班",
+			terms:  []string{"加", "无偿加班"},
+			want:   "<em>无偿加班</em>",
+			wantOK: true,
+		},
+		{
+			name:   "转义 HTML",
+			text:   "<script>加班</script> & \"更多\"",
+			terms:  []string{"加班"},
+			want:   "&lt;script&gt;<em>加班</em>&lt;/script&gt; &amp; &#34;更多&#34;",
+			wantOK: true,
+		},
+		{
+			name:   "关键词本身含 HTML 特殊字符",
+			text:   "a<b",
+			terms:  []string{"<"},
+			want:   "a<em>&lt;</em>b",
+			wantOK: true,
+		},
+		{
+			name:   "忽略空关键词",
+			text:   "加班",
+			terms:  []string{"", "加班"},
+			want:   "<em>加班</em>",
+			wantOK: true,
+		},
+		{
+			name:     "文本不超过片段长度时不截取",
+			text:     "加班严重",
+			terms:    []string{"加班"},
+			maxRunes: 10,
+			want:     "<em>加班</em>严重",
+			wantOK:   true,
+		},
+		{
+			name:     "命中在开头的片段",
+			text:     "加班" + strings.Repeat("一", 20),
+			terms:    []string{"加班"},
+			maxRunes: 10,
+			want:     "<em>加班</em>" + strings.Repeat("一", 8) + "...",
+			wantOK:   true,
+		},
+		{
+			name:     "命中在结尾的片段",
+			text:     strings.Repeat("一", 20) + "加班",
+			terms:    []string{"加班"},
+			maxRunes: 10,
+			want:     "..." + strings.Repeat("一", 8) + "<em>加班</em>",
+			wantOK:   true,
+		},
+		{
+			name:     "命中在中间的片段，命中前保留四分之一",
+			text:     long,
+			terms:    []string{"加班"},
+			maxRunes: 20,
+			want:     "..." + strings.Repeat("一", 5) + "<em>加班</em>" + strings.Repeat("二", 13) + "...",
+			wantOK:   true,
+		},
+		{
+			name:     "跨越片段边界的命中被截断",
+			text:     "加班" + strings.Repeat("一", 8) + "大小周" + strings.Repeat("二", 10),
+			terms:    []string{"加班", "大小周"},
+			maxRunes: 12,
+			want:     "<em>加班</em>" + strings.Repeat("一", 8) + "<em>大小</em>...",
+			wantOK:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := highlight(tt.text, tt.terms, tt.maxRunes)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if got != tt.want {
+				t.Errorf("highlight() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHighlightFields(t *testing.T) {
+	fields := map[string]highlightField{
+		"title":   {text: "996 公司"},
+		"content": {text: "准时下班"},
+	}
+
+	if got := highlightFields(nil, fields); got != nil {
+		t.Errorf("highlightFields(nil) = %v, want nil", got)
+	}
+	if got := highlightFields([]string{"加班"}, fields); got != nil {
+		t.Errorf("highlightFields(未命中) = %v, want nil", got)
+	}
+
+	got := highlightFields([]string{"996"}, fields)
+	if len(got) != 1 || got["title"] != "<em>996</em> 公司" {
+		t.Errorf("highlightFields(996) = %v", got)
+	}
+}
+
+func TestMatchKeyword(t *testing.T) {
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		DSN:                       "user:pass@tcp(127.0.0.1:3306)/test",
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatalf("open dry-run db: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		keyword   string
+		wantWhere string
+		wantScore string
+		wantArgs  int
+	}{
+		{
+			name:      "空关键词不追加条件",
+			keyword:   "",
+			wantWhere: "WHERE status = 0 ORDER",
+			wantScore: "0",
+		},
+		{
+			name:      "单字关键词退化为 LIKE，OR 条件不影响其他条件",
+			keyword:   "坑",
+			wantWhere: "WHERE status = 0 AND (title LIKE '%坑%' OR content LIKE '%坑%')",
+			wantScore: "0",
+		},
+		{
+			name:      "单个英文字母同样退化为 LIKE",
+			keyword:   "a",
+			wantWhere: "WHERE status = 0 AND (title LIKE '%a%' OR content LIKE '%a%')",
+			wantScore: "0",
+		},
+		{
+			name:      "两个字及以上走全文索引",
+			keyword:   "加班",
+			wantWhere: "WHERE status = 0 AND MATCH(title, content) AGAINST ('加班' IN NATURAL LANGUAGE MODE)",
+			wantScore: "MATCH(title, content) AGAINST (? IN NATURAL LANGUAGE MODE)",
+			wantArgs:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var score string
+			var args []interface{}
+			sql := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+				var query *gorm.DB
+				query, score, args = matchKeyword(tx.Table("posts").Where("status = ?", 0), tt.keyword, "title, content", []string{"title", "content"})
+				return query.Order("id").Find(&[]map[string]interface{}{})
+			})
+
+			if !strings.Contains(sql, tt.wantWhere) {
+				t.Errorf("sql = %q, want contains %q", sql, tt.wantWhere)
+			}
+			if score != tt.wantScore {
+				t.Errorf("score = %q, want %q", score, tt.wantScore)
+			}
+			if len(args) != tt.wantArgs {
+				t.Errorf("len(args) = %d, want %d", len(args), tt.wantArgs)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"niuma-house/internal/model"
+	"niuma-house/pkg/cache"
+	"niuma-house/pkg/database"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// sensitiveWordVersionKey 词库版本号，任何变更都会递增，各实例据此重新加载词库
+const sensitiveWordVersionKey = "filter:words:ver"
+
+// SensitiveWordRepository 敏感词词库仓储
+type SensitiveWordRepository struct {
+	db *gorm.DB
+}
+
+// NewSensitiveWordRepository 创建敏感词词库仓储
+func NewSensitiveWordRepository() *SensitiveWordRepository {
+	return &SensitiveWordRepository{db: database.GetDB()}
+}
+
+// All 全部敏感词，用于构建匹配器
+func (r *SensitiveWordRepository) All() ([]model.SensitiveWord, error) {
+	var words []model.SensitiveWord
+	err := r.db.Order("id").Find(&words).Error
+	return words, err
+}
+
+// List 敏感词列表，keyword 按词模糊匹配，category 为空时不过滤
+func (r *SensitiveWordRepository) List(keyword, category string, page, size int) ([]model.SensitiveWord, int64, error) {
+	var words []model.SensitiveWord
+	var total int64
+
+	query := r.db.Model(&model.SensitiveWord{})
+	if keyword != "" {
+		query = query.Where("word LIKE ?", "%"+keyword+"%")
+	}
+	if category != "" {
+		query = query.Where("category = ?", category)
+	}
+	query.Count(&total)
+
+	offset := (page - 1) * size
+	err := query.Order("id DESC").
+		Offset(offset).Limit(size).
+		Find(&words).Error
+	return words, total, err
+}
+
+// FindByID 根据 ID 查找敏感词
+func (r *SensitiveWordRepository) FindByID(id uint) (*model.SensitiveWord, error) {
+	var word model.SensitiveWord
+	if err := r.db.First(&word, id).Error; err != nil {
+		return nil, err
+	}
+	return &word, nil
+}
+
+// CreateBatch 批量添加，已存在的词跳过，返回新增条数
+func (r *SensitiveWordRepository) CreateBatch(words []model.SensitiveWord) (int64, error) {
+	if len(words) == 0 {
+		return 0, nil
+	}
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&words)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	r.bumpVersion()
+	return result.RowsAffected, nil
+}
+
+// Update 修改分类和处理方式
+func (r *SensitiveWordRepository) Update(word *model.SensitiveWord) error {
+	err := r.db.Model(word).Select("category", "action").Updates(word).Error
+	if err == nil {
+		r.bumpVersion()
+	}
+	return err
+}
+
+// Delete 删除敏感词
+func (r *SensitiveWordRepository) Delete(id uint) error {
+	err := r.db.Delete(&model.SensitiveWord{}, id).Error
+	if err == nil {
+		r.bumpVersion()
+	}
+	return err
+}
+
+// Version 当前词库版本号，Redis 异常时返回 -1
+func (r *SensitiveWordRepository) Version() int64 {
+	version, err := cache.GetRedis().Get(context.Background(), sensitiveWordVersionKey).Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return -1
+	}
+	return version
+}
+
+// bumpVersion 递增词库版本号
+func (r *SensitiveWordRepository) bumpVersion() {
+	cache.GetRedis().Incr(context.Background(), sensitiveWordVersionKey)
+}
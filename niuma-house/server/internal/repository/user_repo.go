@@ -1,10 +1,14 @@
 package repository
 
 import (
+	"context"
+
 	"niuma-house/internal/model"
+	"niuma-house/pkg/cache"
 	"niuma-house/pkg/database"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // UserRepository 用户仓储
@@ -32,6 +36,25 @@ func (r *UserRepository) FindByID(id uint) (*model.User, error) {
 	return &user, nil
 }
 
+// FindByIDs 批量查找用户
+func (r *UserRepository) FindByIDs(ids []uint) ([]model.User, error) {
+	var users []model.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+	err := r.db.Where("id IN ?", ids).Find(&users).Error
+	return users, err
+}
+
+// GetLevel 获取用户等级（读穿缓存，等级变化最多延迟一个缓存周期生效）
+func (r *UserRepository) GetLevel(id uint) (int, error) {
+	return cache.Remember(context.Background(), "user_level", userLevelCacheKey(id), userLevelCacheTTL, func() (int, error) {
+		var user model.User
+		err := r.db.Select("id", "level").First(&user, id).Error
+		return user.Level, err
+	})
+}
+
 // FindByUsername 根据用户名查找用户
 func (r *UserRepository) FindByUsername(username string) (*model.User, error) {
 	var user model.User
@@ -47,6 +70,12 @@ func (r *UserRepository) Update(user *model.User) error {
 	return r.db.Save(user).Error
 }
 
+// UpdatePassword 更新密码（传入已加密的密码）
+func (r *UserRepository) UpdatePassword(userID uint, hashedPassword string) error {
+	return r.db.Model(&model.User{}).Where("id = ?", userID).
+		Update("password", hashedPassword).Error
+}
+
 // UpdateExp 更新经验值
 func (r *UserRepository) UpdateExp(userID uint, expDelta int) error {
 	return r.db.Model(&model.User{}).Where("id = ?", userID).
@@ -55,8 +84,75 @@ func (r *UserRepository) UpdateExp(userID uint, expDelta int) error {
 
 // UpdateLevel 更新等级
 func (r *UserRepository) UpdateLevel(userID uint, level int) error {
-	return r.db.Model(&model.User{}).Where("id = ?", userID).
+	err := r.db.Model(&model.User{}).Where("id = ?", userID).
 		Update("level", level).Error
+	if err != nil {
+		return err
+	}
+	cache.Invalidate(context.Background(), userLevelCacheKey(userID))
+	return nil
+}
+
+// 等级校准每个事务处理的用户数
+const recalculateBatchSize = 500
+
+// RecalculateLevels 按等级配置批量校准所有用户的等级，返回变更的用户数
+// levels 需按 MinExp 升序排列；升级的用户由 levelUp 产生事件与等级变更在同一事务中写入 outbox，
+// 变更的用户等级缓存随即失效
+func (r *UserRepository) RecalculateLevels(levels []model.Level, levelUp LevelUpEvents) (int64, error) {
+	if len(levels) == 0 {
+		return 0, nil
+	}
+
+	expr := "CASE"
+	args := make([]interface{}, 0, len(levels)*2)
+	for i := len(levels) - 1; i >= 0; i-- {
+		expr += " WHEN exp >= ? THEN ?"
+		args = append(args, levels[i].MinExp, levels[i].Level)
+	}
+	expr += " ELSE 1 END"
+
+	var total int64
+	for {
+		var users []model.User
+		err := r.db.Transaction(func(tx *gorm.DB) error {
+			// 锁定待校准的用户，与经验发放串行
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Select("id", "exp", "level").
+				Where("level <> ("+expr+")", args...).
+				Order("id").Limit(recalculateBatchSize).
+				Find(&users).Error
+			if err != nil || len(users) == 0 {
+				return err
+			}
+
+			var events []*model.OutboxEvent
+			for i := range users {
+				user := &users[i]
+				level := model.LevelForExp(levels, user.Exp)
+				if err := tx.Model(user).UpdateColumn("level", level).Error; err != nil {
+					return err
+				}
+				if level > user.Level && levelUp != nil {
+					events = append(events, levelUp(user.ID, user.Level, level)...)
+				}
+			}
+			return writeOutbox(tx, func() []*model.OutboxEvent { return events })
+		})
+		if err != nil {
+			return total, err
+		}
+		if len(users) == 0 {
+			return total, nil
+		}
+
+		keys := make([]string, len(users))
+		for i := range users {
+			keys[i] = userLevelCacheKey(users[i].ID)
+		}
+		cache.Invalidate(context.Background(), keys...)
+		total += int64(len(users))
+	}
 }
 
 // Ban 封禁用户
@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"niuma-house/pkg/cache"
+	"niuma-house/pkg/database"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// 浏览量计数目标
+const (
+	ViewTargetPost    = "post"
+	ViewTargetCompany = "company"
+)
+
+// 同一访客在该窗口内重复浏览只计一次
+const viewDedupWindow = 30 * time.Minute
+
+// 每个事务落库的记录数
+const viewFlushBatchSize = 200
+
+// 落库锁的过期时间，持锁实例异常退出后锁自动释放
+const viewFlushLockTTL = 2 * time.Minute
+
+// viewColumn 浏览量目标对应的数据表字段
+type viewColumn struct {
+	table      string
+	column     string
+	cacheKey   func(id uint) string
+	versionKey string // 列表缓存版本号，落库后递增；为空表示列表不缓存
+}
+
+var viewColumns = map[string]viewColumn{
+	ViewTargetPost:    {table: "posts", column: "views_count", cacheKey: postCacheKey, versionKey: postFeedVersionKey},
+	ViewTargetCompany: {table: "companies", column: "view_count", cacheKey: companyCacheKey},
+}
+
+// ackViewScript 扣减已落库的增量，归零后删除字段
+var ackViewScript = redis.NewScript(`
+local v = redis.call('HINCRBY', KEYS[1], ARGV[1], -tonumber(ARGV[2]))
+if v <= 0 then
+	redis.call('HDEL', KEYS[1], ARGV[1])
+end
+return v
+`)
+
+// moveViewScript 将一个字段的增量转移到另一个字段
+var moveViewScript = redis.NewScript(`
+local v = redis.call('HGET', KEYS[1], ARGV[1])
+if v then
+	redis.call('HINCRBY', KEYS[1], ARGV[2], tonumber(v))
+	redis.call('HDEL', KEYS[1], ARGV[1])
+	return 1
+end
+return 0
+`)
+
+// unlockViewScript 仅当锁仍由自己持有时释放
+var unlockViewScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// ViewRepository 浏览量缓冲仓储
+// 浏览增量先累加在 Redis Hash 中，由定时任务批量写回 MySQL
+type ViewRepository struct {
+	db  *gorm.DB
+	rdb *redis.Client
+}
+
+// NewViewRepository 创建浏览量缓冲仓储
+func NewViewRepository() *ViewRepository {
+	return &ViewRepository{db: database.GetDB(), rdb: cache.GetRedis()}
+}
+
+func viewPendingKey(target string) string {
+	return "view:pending:" + target
+}
+
+func viewFlushLockKey(target string) string {
+	return "view:flush:lock:" + target
+}
+
+func viewDedupKey(target string, id uint, viewer string) string {
+	return fmt.Sprintf("view:dedup:%s:%d:%s", target, id, viewer)
+}
+
+// Record 记录一次浏览，去重窗口内同一访客只计一次
+func (r *ViewRepository) Record(target string, id uint, viewer string) error {
+	ctx := context.Background()
+
+	first, err := r.rdb.SetNX(ctx, viewDedupKey(target, id, viewer), 1, viewDedupWindow).Result()
+	if err != nil || !first {
+		return err
+	}
+
+	return r.rdb.HIncrBy(ctx, viewPendingKey(target), strconv.FormatUint(uint64(id), 10), 1).Err()
+}
+
+// Pending 获取尚未落库的浏览增量
+func (r *ViewRepository) Pending(target string, id uint) int {
+	delta, _ := r.rdb.HGet(context.Background(), viewPendingKey(target), strconv.FormatUint(uint64(id), 10)).Int()
+	return delta
+}
+
+// PendingMulti 批量获取尚未落库的浏览增量
+func (r *ViewRepository) PendingMulti(target string, ids []uint) map[uint]int {
+	result := make(map[uint]int, len(ids))
+	if len(ids) == 0 {
+		return result
+	}
+
+	fields := make([]string, len(ids))
+	for i, id := range ids {
+		fields[i] = strconv.FormatUint(uint64(id), 10)
+	}
+
+	values, err := r.rdb.HMGet(context.Background(), viewPendingKey(target), fields...).Result()
+	if err != nil {
+		return result
+	}
+	for i, v := range values {
+		if s, ok := v.(string); ok {
+			delta, _ := strconv.Atoi(s)
+			result[ids[i]] = delta
+		}
+	}
+	return result
+}
+
+// Move 将未落库的浏览增量转移到另一条记录（公司合并时使用）
+func (r *ViewRepository) Move(target string, fromID, toID uint) error {
+	return moveViewScript.Run(context.Background(), r.rdb, []string{viewPendingKey(target)},
+		strconv.FormatUint(uint64(fromID), 10), strconv.FormatUint(uint64(toID), 10)).Err()
+}
+
+// Flush 将缓冲的浏览增量分批写入 MySQL，返回落库的记录数
+// 多实例同时执行时只有持有落库锁的实例会落库，避免同一增量被重复累加
+func (r *ViewRepository) Flush(target string) (int, error) {
+	col, ok := viewColumns[target]
+	if !ok {
+		return 0, fmt.Errorf("unknown view target: %s", target)
+	}
+
+	ctx := context.Background()
+	lockKey := viewFlushLockKey(target)
+	token := uuid.NewString()
+	locked, err := r.rdb.SetNX(ctx, lockKey, token, viewFlushLockTTL).Result()
+	if err != nil || !locked {
+		return 0, err
+	}
+	defer unlockViewScript.Run(ctx, r.rdb, []string{lockKey}, token)
+
+	pending, err := r.rdb.HGetAll(ctx, viewPendingKey(target)).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	type item struct {
+		field string
+		id    uint
+		delta int64
+	}
+	items := make([]item, 0, len(pending))
+	for field, value := range pending {
+		id, err1 := strconv.ParseUint(field, 10, 64)
+		delta, err2 := strconv.ParseInt(value, 10, 64)
+		if err1 != nil || err2 != nil || delta <= 0 {
+			continue
+		}
+		items = append(items, item{field: field, id: uint(id), delta: delta})
+	}
+
+	// 有记录落库时递增一次列表缓存版本号，部分批次失败时同样需要失效
+	flushed := 0
+	defer func() {
+		if flushed > 0 && col.versionKey != "" {
+			r.rdb.Incr(ctx, col.versionKey)
+		}
+	}()
+
+	for start := 0; start < len(items); start += viewFlushBatchSize {
+		end := start + viewFlushBatchSize
+		if end > len(items) {
+			end = len(items)
+		}
+		batch := items[start:end]
+
+		err := r.db.Transaction(func(tx *gorm.DB) error {
+			for _, it := range batch {
+				if err := tx.Table(col.table).Where("id = ?", it.id).
+					UpdateColumn(col.column, gorm.Expr(col.column+" + ?", it.delta)).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return flushed, err
+		}
+
+		// 落库成功后扣减缓冲值，并失效详情缓存
+		keys := make([]string, 0, len(batch))
+		for _, it := range batch {
+			ackViewScript.Run(ctx, r.rdb, []string{viewPendingKey(target)}, it.field, it.delta)
+			keys = append(keys, col.cacheKey(it.id))
+		}
+		cache.Invalidate(ctx, keys...)
+
+		flushed += len(batch)
+	}
+
+	return flushed, nil
+}
@@ -3,6 +3,7 @@ package router
 import (
 	"niuma-house/internal/handler"
 	"niuma-house/internal/middleware"
+	"niuma-house/internal/model"
 	"niuma-house/internal/ws"
 	"niuma-house/pkg/config"
 
@@ -31,51 +32,85 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 		// 认证
 		auth := api.Group("/auth")
 		{
-			auth.POST("/register", handler.Register)
-			auth.POST("/login", handler.Login)
+			auth.POST("/register", middleware.RateLimit(middleware.RateRegisterRule), handler.Register)
+			auth.POST("/login", middleware.RateLimit(middleware.RateLoginRule), handler.Login)
+			auth.POST("/refresh", handler.RefreshToken)
+			auth.POST("/logout", middleware.JWTAuth(), handler.Logout)
 		}
 
 		// 职业分类
 		api.GET("/occupations", handler.GetOccupations)
+		api.GET("/levels", handler.GetLevels)
 
 		// 需要认证的 API
 		protected := api.Group("")
 		protected.Use(middleware.JWTAuth())
 		{
+			// 被禁言的用户可以浏览，不能发布内容
+			mutePost := middleware.NotSanctioned(model.SanctionMutePost)
+
 			// 用户
 			protected.GET("/user/profile", handler.GetProfile)
 			protected.PUT("/user/profile", handler.UpdateProfile)
+			protected.PUT("/user/password", handler.ChangePassword)
+			protected.PUT("/user/privacy", handler.UpdatePrivacy)
+			protected.GET("/user/exp/history", handler.GetExpHistory)
+			protected.GET("/user/badges", handler.GetMyBadges)
+			protected.POST("/user/checkin", handler.Checkin)
+			protected.GET("/user/checkin/calendar", handler.GetCheckinCalendar)
+			protected.GET("/user/blocks", handler.GetBlocks)
+			protected.POST("/user/blocks", handler.BlockUser)
+			protected.DELETE("/user/blocks/:user_id", handler.UnblockUser)
 
 			// 帖子
 			protected.GET("/posts", handler.GetPosts)
+			protected.GET("/posts/search", handler.SearchPosts)
 			protected.GET("/posts/:id", handler.GetPost)
-			protected.POST("/posts", handler.CreatePost)
-			protected.PUT("/posts/:id", handler.UpdatePost)
+			protected.POST("/posts", mutePost, middleware.RateLimit(middleware.RatePostRule), handler.CreatePost)
+			protected.PUT("/posts/:id", mutePost, middleware.RateLimit(middleware.RatePostRule), handler.UpdatePost)
 			protected.DELETE("/posts/:id", handler.DeletePost)
-			protected.POST("/posts/:id/like", handler.LikePost)
-			protected.DELETE("/posts/:id/like", handler.UnlikePost)
-			protected.POST("/posts/:id/favorite", handler.FavoritePost)
-			protected.DELETE("/posts/:id/favorite", handler.UnfavoritePost)
+			protected.POST("/posts/:id/like", middleware.RateLimit(middleware.RateLikeRule), handler.LikePost)
+			protected.DELETE("/posts/:id/like", middleware.RateLimit(middleware.RateLikeRule), handler.UnlikePost)
+			protected.POST("/posts/:id/favorite", middleware.RateLimit(middleware.RateLikeRule), handler.FavoritePost)
+			protected.DELETE("/posts/:id/favorite", middleware.RateLimit(middleware.RateLikeRule), handler.UnfavoritePost)
+			protected.POST("/posts/:id/report", middleware.RateLimit(middleware.RateReportRule), handler.ReportPost)
 
 			// 评论
 			protected.GET("/posts/:id/comments", handler.GetComments)
-			protected.POST("/posts/:id/comments", handler.CreateComment)
+			protected.POST("/posts/:id/comments", mutePost, middleware.RateLimit(middleware.RateCommentRule), handler.CreateComment)
+			protected.GET("/comments/:id/replies", handler.GetCommentReplies)
 			protected.DELETE("/comments/:id", handler.DeleteComment)
+			protected.POST("/comments/:id/report", middleware.RateLimit(middleware.RateReportRule), handler.ReportComment)
 
 			// 公司
 			protected.GET("/companies", handler.GetCompanies)
 			protected.GET("/companies/search", handler.SearchCompanies)
+			protected.GET("/companies/duplicates", handler.CheckCompanyDuplicates)
 			protected.GET("/companies/:id", handler.GetCompany)
-			protected.POST("/companies", handler.CreateCompany)
+			protected.POST("/companies", mutePost, middleware.RateLimit(middleware.RateCompanyRule), handler.CreateCompany)
+			protected.GET("/companies/:id/reviews", handler.GetCompanyReviews)
+			protected.POST("/companies/:id/reviews", mutePost, middleware.RateLimit(middleware.RateReviewRule), handler.SubmitCompanyReview)
+			protected.DELETE("/companies/:id/reviews", handler.DeleteCompanyReview)
+			protected.POST("/companies/:id/report", middleware.RateLimit(middleware.RateReportRule), handler.ReportCompany)
 
 			// 上传
-			protected.POST("/upload/presign", handler.GetPresignedURL)
-			protected.POST("/user/avatar", handler.UploadAvatar)
+			protected.POST("/upload/presign", mutePost, middleware.RateLimit(middleware.RateUploadRule), handler.GetPresignedURL)
+			protected.POST("/user/avatar", mutePost, middleware.RateLimit(middleware.RateUploadRule), handler.UploadAvatar)
 
 			// 私信
 			protected.GET("/messages", handler.GetMessages)
 			protected.GET("/messages/unread", handler.GetUnreadCount)
 			protected.POST("/messages/read", handler.MarkAsRead)
+			protected.POST("/messages/:id/report", middleware.RateLimit(middleware.RateReportRule), handler.ReportMessage)
+
+			// 举报
+			protected.GET("/reports/reasons", handler.GetReportReasons)
+			protected.POST("/users/:id/report", middleware.RateLimit(middleware.RateReportRule), handler.ReportUser)
+
+			// 通知
+			protected.GET("/notifications", handler.GetNotifications)
+			protected.GET("/notifications/unread", handler.GetUnreadNotificationCount)
+			protected.POST("/notifications/read", handler.MarkNotificationsAsRead)
 		}
 
 		// WebSocket
@@ -84,15 +119,44 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 
 	// 管理后台 API
 	admin := r.Group("/api/admin")
-	admin.Use(middleware.JWTAuth(), middleware.AdminAuth())
+	admin.Use(middleware.JWTAuth(), middleware.CasbinAuth(), middleware.AuditLog())
 	{
 		// 数据统计
 		admin.GET("/dashboard/stats", handler.GetDashboardStats)
+		admin.GET("/cache/stats", handler.GetCacheStats)
 
 		// 用户管理
 		admin.GET("/users", handler.AdminGetUsers)
 		admin.POST("/users/:id/ban", handler.BanUser)
 		admin.POST("/users/:id/unban", handler.UnbanUser)
+		admin.POST("/users/:id/kick", handler.KickUser)
+		admin.POST("/users/:id/sanctions", handler.AdminIssueSanction)
+
+		// 处罚记录
+		admin.GET("/sanctions", handler.AdminGetSanctions)
+		admin.POST("/sanctions/:id/lift", handler.AdminLiftSanction)
+
+		// 匿名内容：查看真实作者（记录审计日志）
+		admin.POST("/anonymous/reveal", handler.AdminRevealAuthor)
+		admin.GET("/anonymous/reveals", handler.AdminGetReveals)
+
+		// 举报审核
+		admin.GET("/moderation/cases", handler.AdminGetModerationCases)
+		admin.GET("/moderation/cases/:id", handler.AdminGetModerationCase)
+		admin.POST("/moderation/cases/:id/assign", handler.AdminAssignModerationCase)
+		admin.POST("/moderation/cases/:id/resolve", handler.AdminResolveModerationCase)
+
+		// 内容过滤
+		admin.GET("/filter/words", handler.AdminGetSensitiveWords)
+		admin.POST("/filter/words", handler.AdminAddSensitiveWords)
+		admin.PUT("/filter/words/:id", handler.AdminUpdateSensitiveWord)
+		admin.DELETE("/filter/words/:id", handler.AdminDeleteSensitiveWord)
+		admin.POST("/filter/test", handler.AdminTestFilter)
+
+		// 等级管理
+		admin.GET("/levels", handler.GetLevels)
+		admin.PUT("/levels/:level", handler.AdminSaveLevel)
+		admin.DELETE("/levels/:level", handler.AdminDeleteLevel)
 
 		// 帖子管理
 		admin.GET("/posts", handler.AdminGetPosts)
@@ -102,6 +166,24 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 		// 公司管理
 		admin.GET("/companies", handler.AdminGetCompanies)
 		admin.DELETE("/companies/:id", handler.AdminDeleteCompany)
+		admin.POST("/companies/:id/merge", handler.AdminMergeCompany)
+		admin.DELETE("/companies/:id/reviews/:review_id", handler.AdminDeleteCompanyReview)
+
+		// 消息队列
+		admin.GET("/mq/dead-letters", handler.AdminGetDeadLetters)
+		admin.POST("/mq/dead-letters/replay", handler.AdminReplayDeadLetters)
+
+		// 审计日志
+		admin.GET("/audit-logs", handler.AdminGetAuditLogs)
+		admin.GET("/audit-logs/export", handler.AdminExportAuditLogs)
+
+		// 权限策略
+		admin.GET("/policies", handler.AdminGetPolicies)
+		admin.POST("/policies", handler.AdminAddPolicy)
+		admin.DELETE("/policies", handler.AdminRemovePolicy)
+		admin.POST("/policies/roles", handler.AdminAddRole)
+		admin.DELETE("/policies/roles", handler.AdminRemoveRole)
+		admin.POST("/policies/reload", handler.AdminReloadPolicies)
 	}
 
 	return r
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"sync"
+
+	"niuma-house/internal/model"
+	"niuma-house/internal/repository"
+	"niuma-house/pkg/config"
+)
+
+// 化名由形容词 + 动物 + 四位编号组成，如「摸鱼的水豚#0427」
+var (
+	pseudonymAdjectives = []string{"摸鱼的", "加班的", "躺平的", "内卷的", "早退的", "带薪的", "打卡的", "开会的", "画饼的", "跳槽的", "裸辞的", "通勤的"}
+	pseudonymAnimals    = []string{"水豚", "牛马", "考拉", "仓鼠", "柴犬", "企鹅", "树懒", "熊猫", "海豹", "羊驼", "狐狸", "刺猬"}
+)
+
+// 等级区间宽度，匿名作者只展示所在区间
+const levelBucketSize = 2
+
+var (
+	pseudonymKey     []byte
+	pseudonymKeyOnce sync.Once
+)
+
+// pseudonymSecret 化名密钥，未配置时使用 JWT 密钥
+func pseudonymSecret() []byte {
+	pseudonymKeyOnce.Do(func() {
+		cfg := config.GetConfig()
+		secret := cfg.Anonymous.Secret
+		if secret == "" {
+			secret = cfg.JWT.Secret
+		}
+		pseudonymKey = []byte(secret)
+	})
+	return pseudonymKey
+}
+
+// pseudonym 同一主题内同一用户的化名稳定，不同主题之间无法关联
+func pseudonym(thread string, userID uint) string {
+	mac := hmac.New(sha256.New, pseudonymSecret())
+	fmt.Fprintf(mac, "%s:%d", thread, userID)
+	sum := mac.Sum(nil)
+
+	adjective := pseudonymAdjectives[int(sum[0])%len(pseudonymAdjectives)]
+	animal := pseudonymAnimals[int(sum[1])%len(pseudonymAnimals)]
+	return fmt.Sprintf("%s%s#%04d", adjective, animal, binary.BigEndian.Uint16(sum[2:4])%10000)
+}
+
+// levelBucket 等级区间，如 Lv.1-2
+func levelBucket(level int) string {
+	if level < 1 {
+		level = 1
+	}
+	low := (level-1)/levelBucketSize*levelBucketSize + 1
+	return fmt.Sprintf("Lv.%d-%d", low, low+levelBucketSize-1)
+}
+
+// 匿名主题：帖子与其评论共用一个主题，公司与其评价共用一个主题
+func postThread(postID uint) string       { return fmt.Sprintf("post:%d", postID) }
+func companyThread(companyID uint) string { return fmt.Sprintf("company:%d", companyID) }
+
+// anonymizer 对外返回前隐藏匿名内容的真实作者，所有返回帖子、评论、公司、评价的服务方法都需经过它
+// 非匿名内容只标记 IsMine
+type anonymizer struct {
+	userRepo       *repository.UserRepository
+	occupationRepo *repository.OccupationRepository
+}
+
+func newAnonymizer() *anonymizer {
+	return &anonymizer{
+		userRepo:       repository.NewUserRepository(),
+		occupationRepo: repository.NewOccupationRepository(),
+	}
+}
+
+// author 构造匿名作者信息，user 未预加载时按 userID 查询
+func (a *anonymizer) author(thread string, userID uint, user *model.User) *model.AnonymousAuthor {
+	author := &model.AnonymousAuthor{Pseudonym: pseudonym(thread, userID)}
+	if user == nil {
+		var err error
+		if user, err = a.userRepo.FindByID(userID); err != nil {
+			return author
+		}
+	}
+	author.LevelBucket = levelBucket(user.Level)
+	if occupations, err := a.occupationRepo.List(); err == nil {
+		for _, o := range occupations {
+			if o.ID == user.OccupationID {
+				author.Occupation = o.Name
+				break
+			}
+		}
+	}
+	return author
+}
+
+// Post 隐藏匿名帖子的作者
+func (a *anonymizer) Post(post *model.Post, viewerID uint) {
+	post.IsMine = viewerID != 0 && post.UserID == viewerID
+	if !post.Anonymous {
+		return
+	}
+	post.Author = a.author(postThread(post.ID), post.UserID, post.User)
+	post.UserID = 0
+	post.User = nil
+}
+
+// Posts 批量处理帖子
+func (a *anonymizer) Posts(posts []model.Post, viewerID uint) {
+	for i := range posts {
+		a.Post(&posts[i], viewerID)
+	}
+}
+
+// Comment 隐藏匿名评论的作者，以及被回复的匿名评论作者；同时处理回复预览
+func (a *anonymizer) Comment(comment *model.Comment, viewerID uint) {
+	thread := postThread(comment.PostID)
+	comment.IsMine = viewerID != 0 && comment.UserID == viewerID
+	if comment.Anonymous {
+		comment.Author = a.author(thread, comment.UserID, comment.User)
+		comment.UserID = 0
+		comment.User = nil
+	}
+	if comment.ReplyToAnon && comment.ReplyToUserID != nil {
+		comment.ReplyToAuthor = a.author(thread, *comment.ReplyToUserID, comment.ReplyToUser)
+		comment.ReplyToUserID = nil
+		comment.ReplyToUser = nil
+	}
+	if comment.Post != nil {
+		a.Post(comment.Post, viewerID)
+	}
+	a.Comments(comment.Replies, viewerID)
+}
+
+// Comments 批量处理评论
+func (a *anonymizer) Comments(comments []model.Comment, viewerID uint) {
+	for i := range comments {
+		a.Comment(&comments[i], viewerID)
+	}
+}
+
+// Company 隐藏匿名曝光的创建者
+func (a *anonymizer) Company(company *model.Company, viewerID uint) {
+	company.IsMine = viewerID != 0 && company.CreatorID == viewerID
+	if !company.Anonymous {
+		return
+	}
+	company.Author = a.author(companyThread(company.ID), company.CreatorID, company.Creator)
+	company.CreatorID = 0
+	company.Creator = nil
+}
+
+// Companies 批量处理公司
+func (a *anonymizer) Companies(companies []model.Company, viewerID uint) {
+	for i := range companies {
+		a.Company(&companies[i], viewerID)
+	}
+}
+
+// Review 隐藏匿名评价的作者
+func (a *anonymizer) Review(review *model.CompanyReview, viewerID uint) {
+	review.IsMine = viewerID != 0 && review.UserID == viewerID
+	if !review.Anonymous {
+		return
+	}
+	review.Author = a.author(companyThread(review.CompanyID), review.UserID, review.User)
+	review.UserID = 0
+	review.User = nil
+}
+
+// Reviews 批量处理评价
+func (a *anonymizer) Reviews(reviews []model.CompanyReview, viewerID uint) {
+	for i := range reviews {
+		a.Review(&reviews[i], viewerID)
+	}
+}
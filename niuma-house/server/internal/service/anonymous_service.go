@@ -0,0 +1,58 @@
+package service
+
+import (
+	"niuma-house/internal/model"
+	"niuma-house/internal/repository"
+)
+
+// AnonymousService 匿名内容管理服务
+type AnonymousService struct {
+	anonRepo *repository.AnonymousRepository
+	userRepo *repository.UserRepository
+}
+
+// NewAnonymousService 创建匿名内容管理服务
+func NewAnonymousService() *AnonymousService {
+	return &AnonymousService{
+		anonRepo: repository.NewAnonymousRepository(),
+		userRepo: repository.NewUserRepository(),
+	}
+}
+
+// RevealRequest 查看真实作者请求
+type RevealRequest struct {
+	TargetType string `json:"target_type" binding:"required,oneof=post comment company review"`
+	TargetID   uint   `json:"target_id" binding:"required"`
+	Reason     string `json:"reason" binding:"required,min=5,max=500"` // 查看理由，写入审计记录
+}
+
+// Reveal 查看匿名内容的真实作者，先写审计记录再返回结果
+func (s *AnonymousService) Reveal(adminID uint, ip string, req *RevealRequest) (*model.User, *model.AnonymousReveal, error) {
+	authorID, err := s.anonRepo.AuthorOf(req.TargetType, req.TargetID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reveal := &model.AnonymousReveal{
+		AdminID:    adminID,
+		TargetType: req.TargetType,
+		TargetID:   req.TargetID,
+		AuthorID:   authorID,
+		Reason:     req.Reason,
+		IP:         ip,
+	}
+	if err := s.anonRepo.CreateReveal(reveal); err != nil {
+		return nil, nil, err
+	}
+
+	author, err := s.userRepo.FindByID(authorID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return author, reveal, nil
+}
+
+// ListReveals 查看记录
+func (s *AnonymousService) ListReveals(targetType string, page, size int) ([]model.AnonymousReveal, int64, error) {
+	return s.anonRepo.ListReveals(targetType, page, size)
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"niuma-house/internal/model"
+	"niuma-house/internal/repository"
+)
+
+// 单次导出的最大条数
+const auditExportLimit = 10000
+
+// AuditService 管理端审计日志服务
+type AuditService struct {
+	auditRepo *repository.AuditRepository
+}
+
+// NewAuditService 创建审计日志服务
+func NewAuditService() *AuditService {
+	return &AuditService{
+		auditRepo: repository.NewAuditRepository(),
+	}
+}
+
+// List 审计日志列表
+func (s *AuditService) List(filter *repository.AuditFilter, page, size int) ([]model.AuditLog, int64, error) {
+	return s.auditRepo.List(filter, page, size)
+}
+
+// Export 导出审计日志，最多 auditExportLimit 条
+func (s *AuditService) Export(filter *repository.AuditFilter) ([]model.AuditLog, error) {
+	return s.auditRepo.Export(filter, auditExportLimit)
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"errors"
+
+	"niuma-house/internal/model"
+	"niuma-house/internal/repository"
+	"niuma-house/internal/ws"
+)
+
+// BlockService 黑名单服务
+type BlockService struct {
+	blockRepo *repository.BlockRepository
+	userRepo  *repository.UserRepository
+}
+
+// NewBlockService 创建黑名单服务
+func NewBlockService() *BlockService {
+	return &BlockService{
+		blockRepo: repository.NewBlockRepository(),
+		userRepo:  repository.NewUserRepository(),
+	}
+}
+
+// Block 拉黑用户
+func (s *BlockService) Block(userID, blockedID uint) error {
+	if userID == blockedID {
+		return errors.New("不能拉黑自己")
+	}
+	if _, err := s.userRepo.FindByID(blockedID); err != nil {
+		return errors.New("用户不存在")
+	}
+	if s.blockRepo.IsBlocked(userID, blockedID) {
+		return errors.New("已拉黑该用户")
+	}
+	if err := s.blockRepo.Block(userID, blockedID); err != nil {
+		return err
+	}
+	// 被拉黑的用户不能再看到拉黑者的在线状态
+	ws.GetHub().Unwatch(blockedID, userID)
+	return nil
+}
+
+// Unblock 取消拉黑
+func (s *BlockService) Unblock(userID, blockedID uint) error {
+	if !s.blockRepo.IsBlocked(userID, blockedID) {
+		return errors.New("未拉黑该用户")
+	}
+	return s.blockRepo.Unblock(userID, blockedID)
+}
+
+// List 黑名单列表
+func (s *BlockService) List(userID uint, page, size int) ([]model.UserBlock, int64, error) {
+	return s.blockRepo.ListByUserID(userID, page, size)
+}
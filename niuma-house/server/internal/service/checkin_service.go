@@ -0,0 +1,152 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"niuma-house/internal/model"
+	"niuma-house/internal/mq"
+	"niuma-house/internal/repository"
+)
+
+// ErrAlreadyCheckedIn 今日已签到
+var ErrAlreadyCheckedIn = errors.New("今日已签到")
+
+// checkinRewards 连续签到第 N 天的经验奖励，超过 7 天按第 7 天计算
+// 最大值需与经验消费者中登录行为的每日上限一致
+var checkinRewards = []int{5, 6, 7, 8, 10, 12, 15}
+
+// checkinReward 连续签到 streak 天时的奖励
+func checkinReward(streak int) int {
+	if streak < 1 {
+		streak = 1
+	}
+	return checkinRewards[min(streak, len(checkinRewards))-1]
+}
+
+// CheckinService 每日签到服务
+type CheckinService struct {
+	checkinRepo *repository.CheckinRepository
+	outboxRepo  *repository.OutboxRepository
+	expRepo     *repository.ExpRepository
+}
+
+// NewCheckinService 创建签到服务
+func NewCheckinService() *CheckinService {
+	return &CheckinService{
+		checkinRepo: repository.NewCheckinRepository(),
+		outboxRepo:  repository.NewOutboxRepository(),
+		expRepo:     repository.NewExpRepository(),
+	}
+}
+
+// CheckinResult 签到结果
+type CheckinResult struct {
+	Day    string `json:"day"`
+	Streak int    `json:"streak"` // 连续签到天数
+	Reward int    `json:"reward"` // 本次获得的经验
+}
+
+// CheckinCalendar 签到月历
+type CheckinCalendar struct {
+	Month        string `json:"month"` // 2006-01
+	Days         []int  `json:"days"`  // 已签到的日期（几号）
+	Streak       int    `json:"streak"`
+	CheckedToday bool   `json:"checked_today"`
+	NextReward   int    `json:"next_reward"` // 今日（或明日）签到可获得的经验
+	Rewards      []int  `json:"rewards"`     // 连续签到第 1..N 天的奖励
+}
+
+// Checkin 每日签到，今日已签到时返回 ErrAlreadyCheckedIn
+func (s *CheckinService) Checkin(userID uint) (*CheckinResult, error) {
+	return checkin(s.checkinRepo, s.outboxRepo, userID)
+}
+
+// Calendar 某月的签到记录及连续签到信息
+func (s *CheckinService) Calendar(userID uint, month time.Time) (*CheckinCalendar, error) {
+	days, err := s.checkinRepo.Calendar(userID, month)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	streak, err := s.checkinRepo.Streak(userID, now)
+	if err != nil {
+		return nil, err
+	}
+	checked, err := s.checkinRepo.StreakAt(userID, now, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CheckinCalendar{
+		Month:        month.Format("2006-01"),
+		Days:         days,
+		Streak:       streak,
+		CheckedToday: checked > 0,
+		NextReward:   checkinReward(streak + 1),
+		Rewards:      checkinRewards,
+	}, nil
+}
+
+// Reconcile 校准 day 当天的签到奖励：签到已记录但经验事件未写入（如写 outbox 失败）时补发
+// 流水按日期幂等，重复补发不会重复加经验
+func (s *CheckinService) Reconcile(day time.Time) (int, error) {
+	userIDs, err := s.checkinRepo.CheckedInUsers(day)
+	if err != nil {
+		return 0, err
+	}
+
+	granted, err := s.expRepo.UsersWithSubject(mq.ActionLogin, mq.CheckinSubject(day), userIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	var events []*model.OutboxEvent
+	for _, userID := range userIDs {
+		if granted[userID] {
+			continue
+		}
+		streak, err := s.checkinRepo.StreakAt(userID, day, len(checkinRewards))
+		if err != nil {
+			return 0, err
+		}
+		events = append(events, mq.CheckinEvent(userID, day, checkinReward(streak)))
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	err = s.outboxRepo.Write(func() []*model.OutboxEvent { return events })
+	if err != nil {
+		return 0, err
+	}
+	return len(events), nil
+}
+
+// checkin 记录今日签到并写入经验事件，登录时也会自动签到
+func checkin(checkinRepo *repository.CheckinRepository, outboxRepo *repository.OutboxRepository, userID uint) (*CheckinResult, error) {
+	now := time.Now()
+	first, streak, err := checkinRepo.Checkin(userID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !first {
+		return nil, ErrAlreadyCheckedIn
+	}
+
+	result := &CheckinResult{
+		Day:    now.Format("2006-01-02"),
+		Streak: streak,
+		Reward: checkinReward(streak),
+	}
+	// 签到已记录，事件写入失败时由每日任务补发
+	err = outboxRepo.Write(func() []*model.OutboxEvent {
+		return []*model.OutboxEvent{mq.CheckinEvent(userID, now, result.Reward)}
+	})
+	if err != nil {
+		log.Printf("Failed to write checkin exp event: userID=%d, err=%v", userID, err)
+	}
+	return result, nil
+}
@@ -2,16 +2,21 @@ package service
 
 import (
 	"errors"
+	"fmt"
 
 	"niuma-house/internal/model"
 	"niuma-house/internal/mq"
 	"niuma-house/internal/repository"
 )
 
+// 一级评论下预览的回复数
+const replyPreviewSize = 3
+
 // CommentService 评论服务
 type CommentService struct {
 	commentRepo *repository.CommentRepository
 	postRepo    *repository.PostRepository
+	anon        *anonymizer
 }
 
 // NewCommentService 创建评论服务
@@ -19,13 +24,15 @@ func NewCommentService() *CommentService {
 	return &CommentService{
 		commentRepo: repository.NewCommentRepository(),
 		postRepo:    repository.NewPostRepository(),
+		anon:        newAnonymizer(),
 	}
 }
 
 // CreateCommentRequest 创建评论请求
 type CreateCommentRequest struct {
-	Content  string `json:"content" binding:"required"`
-	ParentID *uint  `json:"parent_id"`
+	Content   string `json:"content" binding:"required"`
+	ParentID  *uint  `json:"parent_id"`
+	Anonymous bool   `json:"anonymous"` // 匿名评论，在该帖子下使用固定化名
 }
 
 // Create 创建评论
@@ -36,29 +43,145 @@ func (s *CommentService) Create(postID, userID uint, req *CreateCommentRequest)
 		return nil, errors.New("帖子不存在")
 	}
 
+	// 敏感词和个人信息过滤，需打码的片段直接替换
+	result, err := filterContent(&req.Content)
+	if err != nil {
+		return nil, err
+	}
+
 	comment := &model.Comment{
-		PostID:   postID,
-		UserID:   userID,
-		Content:  req.Content,
-		ParentID: req.ParentID,
-		Status:   1,
+		PostID:    postID,
+		UserID:    userID,
+		Content:   req.Content,
+		Status:    1,
+		Anonymous: req.Anonymous,
 	}
+	hold := contentHold(result)
+	if hold != nil {
+		comment.Status = model.CommentStatusHidden
+	}
+
+	// 回复评论：父评论必须属于同一帖子，回复统一挂在一级评论下
+	var parent *model.Comment
+	if req.ParentID != nil {
+		parent, err = s.commentRepo.FindByID(*req.ParentID)
+		if err != nil || parent.Status != 1 {
+			return nil, errors.New("回复的评论不存在")
+		}
+		if parent.PostID != postID {
+			return nil, errors.New("回复的评论不属于该帖子")
+		}
 
-	if err := s.commentRepo.Create(comment); err != nil {
+		rootID := parent.ID
+		if parent.RootID != nil {
+			rootID = *parent.RootID
+		}
+		comment.ParentID = &parent.ID
+		comment.RootID = &rootID
+		comment.ReplyToUserID = &parent.UserID
+		comment.ReplyToAnon = parent.Anonymous
+	}
+
+	// 经验值和通知事件与评论在同一事务中写入 outbox
+	// 需要送审时事件暂存，审核驳回（评论恢复）后才发放经验和通知
+	err = s.commentRepo.Create(comment, func() []*model.OutboxEvent {
+		return commentEvents(post, parent, comment)
+	}, hold)
+	if err != nil {
 		return nil, err
 	}
+	s.anon.Comment(comment, userID)
+	return comment, nil
+}
+
+// commentEvents 评论产生的经验值和通知事件
+func commentEvents(post *model.Post, parent *model.Comment, comment *model.Comment) []*model.OutboxEvent {
+	var events []*model.OutboxEvent
+	userID := comment.UserID
+
+	// 匿名评论的通知不携带触发者
+	actorID := userID
+	if comment.Anonymous {
+		actorID = 0
+	}
 
-	// 给帖子作者加经验（自己评论自己的帖子不加）
+	// 给帖子作者加经验并通知（自己评论自己的帖子不加；作者同时是被回复者时只发回复通知）
 	if post.UserID != userID {
-		mq.PublishExpMessage(post.UserID, mq.ActionCommented, 1)
+		events = append(events, mq.ExpEvent(post.UserID, mq.ActionCommented, mq.CommentSubject(comment.ID), 1))
+		if parent == nil || parent.UserID != post.UserID {
+			events = append(events, mq.NotificationEvent(mq.NotificationMessage{
+				UserID:    post.UserID,
+				Type:      model.NotificationComment,
+				ActorID:   actorID,
+				PostID:    post.ID,
+				CommentID: comment.ID,
+				Content:   fmt.Sprintf("有人评论了你的帖子《%s》", post.Title),
+				DedupeKey: model.NotificationDedupeKey(post.UserID, model.NotificationComment,
+					mq.CommentSubject(comment.ID)),
+			}))
+		}
 	}
 
-	return comment, nil
+	// 通知被回复者并加经验（被回复者是帖子作者时不重复加经验）
+	if parent != nil && parent.UserID != userID {
+		if parent.UserID != post.UserID {
+			events = append(events, mq.ExpEvent(parent.UserID, mq.ActionReplied, mq.CommentSubject(comment.ID), 1))
+		}
+		events = append(events, mq.NotificationEvent(mq.NotificationMessage{
+			UserID:    parent.UserID,
+			Type:      model.NotificationReply,
+			ActorID:   actorID,
+			PostID:    post.ID,
+			CommentID: comment.ID,
+			Content:   fmt.Sprintf("有人回复了你在《%s》下的评论", post.Title),
+			DedupeKey: model.NotificationDedupeKey(parent.UserID, model.NotificationReply,
+				mq.CommentSubject(comment.ID)),
+		}))
+	}
+
+	return events
+}
+
+// List 一级评论列表，每条附带回复数和前几条回复，viewerID 为当前用户
+func (s *CommentService) List(postID uint, page, size int, viewerID uint) ([]model.Comment, int64, error) {
+	comments, total, err := s.commentRepo.ListRootsByPostID(postID, page, size)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rootIDs := make([]uint, len(comments))
+	for i := range comments {
+		rootIDs[i] = comments[i].ID
+	}
+
+	replyCounts := s.commentRepo.CountRepliesByRootIDs(rootIDs)
+	previews, err := s.commentRepo.ListReplyPreviews(rootIDs, replyPreviewSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for i := range comments {
+		comments[i].ReplyCount = replyCounts[comments[i].ID]
+		comments[i].Replies = previews[comments[i].ID]
+	}
+	s.anon.Comments(comments, viewerID)
+
+	return comments, total, nil
 }
 
-// List 评论列表
-func (s *CommentService) List(postID uint, page, size int) ([]model.Comment, int64, error) {
-	return s.commentRepo.ListByPostID(postID, page, size)
+// ListReplies 加载一级评论下的更多回复
+func (s *CommentService) ListReplies(rootID uint, page, size int, viewerID uint) ([]model.Comment, int64, error) {
+	root, err := s.commentRepo.FindByID(rootID)
+	if err != nil || root.Status != 1 || root.RootID != nil {
+		return nil, 0, errors.New("评论不存在")
+	}
+
+	replies, total, err := s.commentRepo.ListReplies(rootID, page, size)
+	if err != nil {
+		return nil, 0, err
+	}
+	s.anon.Comments(replies, viewerID)
+	return replies, total, nil
 }
 
 // Delete 删除评论
@@ -72,5 +195,25 @@ func (s *CommentService) Delete(commentID, userID uint) error {
 		return errors.New("无权删除他人评论")
 	}
 
-	return s.commentRepo.Delete(commentID)
+	// 撤销帖子作者和被回复者因该评论获得的经验（与发放条件一致）
+	var events repository.OutboxEvents
+	if post, err := s.postRepo.FindByID(comment.PostID); err == nil {
+		events = func() []*model.OutboxEvent {
+			return revokeCommentExp(post, comment)
+		}
+	}
+
+	return s.commentRepo.Delete(commentID, events)
+}
+
+// revokeCommentExp 删除评论时撤销的经验事件
+func revokeCommentExp(post *model.Post, comment *model.Comment) []*model.OutboxEvent {
+	var events []*model.OutboxEvent
+	if post.UserID != comment.UserID {
+		events = append(events, mq.ExpRevokeEvent(post.UserID, mq.ActionCommented, mq.CommentSubject(comment.ID)))
+	}
+	if to := comment.ReplyToUserID; to != nil && *to != comment.UserID && *to != post.UserID {
+		events = append(events, mq.ExpRevokeEvent(*to, mq.ActionReplied, mq.CommentSubject(comment.ID)))
+	}
+	return events
 }
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"errors"
+
+	"niuma-house/internal/model"
+	"niuma-house/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// CompanyReviewService 公司评价服务
+type CompanyReviewService struct {
+	reviewRepo  *repository.CompanyReviewRepository
+	companyRepo *repository.CompanyRepository
+	indexer     repository.SearchIndexer
+	anon        *anonymizer
+}
+
+// NewCompanyReviewService 创建公司评价服务
+func NewCompanyReviewService() *CompanyReviewService {
+	return &CompanyReviewService{
+		reviewRepo:  repository.NewCompanyReviewRepository(),
+		companyRepo: repository.NewCompanyRepository(),
+		indexer:     repository.NewMySQLSearcher(),
+		anon:        newAnonymizer(),
+	}
+}
+
+// ReviewRequest 提交评价请求
+type ReviewRequest struct {
+	RiskLevel int      `json:"risk_level" binding:"required,min=1,max=5"`
+	Tags      []string `json:"tags"`
+	Content   string   `json:"content"`
+	Evidence  []string `json:"evidence"`
+	Anonymous bool     `json:"anonymous"` // 匿名评价，在该公司下使用固定化名
+}
+
+// validateTags 校验标签必须来自预置标签
+func validateTags(tags []string) error {
+	for _, tag := range tags {
+		if !model.IsValidCompanyTag(tag) {
+			return errors.New("无效的标签: " + tag)
+		}
+	}
+	return nil
+}
+
+// Submit 提交评价，已评价过则覆盖原评价；评价已合并的公司时记在合并后的公司上
+func (s *CompanyReviewService) Submit(companyID, userID uint, req *ReviewRequest) (*model.CompanyReview, error) {
+	company, err := findMergeTarget(s.companyRepo, companyID)
+	if err != nil {
+		return nil, errors.New("公司不存在")
+	}
+	companyID = company.ID
+	if err := validateTags(req.Tags); err != nil {
+		return nil, err
+	}
+	if err := filterContentNoReview(&req.Content); err != nil {
+		return nil, err
+	}
+
+	review, err := s.reviewRepo.FindByCompanyAndUser(companyID, userID)
+	switch {
+	case err == nil:
+		review.RiskLevel = req.RiskLevel
+		review.Tags = req.Tags
+		review.Content = req.Content
+		review.Evidence = req.Evidence
+		review.Anonymous = req.Anonymous
+		review.Status = 1
+		err = s.reviewRepo.Update(review)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		review = &model.CompanyReview{
+			CompanyID: companyID,
+			UserID:    userID,
+			RiskLevel: req.RiskLevel,
+			Tags:      req.Tags,
+			Content:   req.Content,
+			Evidence:  req.Evidence,
+			Status:    1,
+			Anonymous: req.Anonymous,
+		}
+		err = s.reviewRepo.Create(review)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.refreshCompany(companyID); err != nil {
+		return nil, err
+	}
+	s.anon.Review(review, userID)
+	return review, nil
+}
+
+// List 公司评价列表，viewerID 为当前用户
+func (s *CompanyReviewService) List(companyID uint, page, size int, viewerID uint) ([]model.CompanyReview, int64, error) {
+	reviews, total, err := s.reviewRepo.ListByCompanyID(companyID, page, size)
+	if err != nil {
+		return nil, 0, err
+	}
+	s.anon.Reviews(reviews, viewerID)
+	return reviews, total, nil
+}
+
+// Delete 删除自己的评价
+func (s *CompanyReviewService) Delete(companyID, userID uint) error {
+	review, err := s.reviewRepo.FindByCompanyAndUser(companyID, userID)
+	if err != nil || review.Status != 1 {
+		return errors.New("评价不存在")
+	}
+
+	if err := s.reviewRepo.Delete(review.ID); err != nil {
+		return err
+	}
+	return s.refreshCompany(companyID)
+}
+
+// AdminDelete 管理员删除评价
+func (s *CompanyReviewService) AdminDelete(companyID, reviewID uint) error {
+	review, err := s.reviewRepo.FindByID(reviewID)
+	if err != nil || review.CompanyID != companyID {
+		return errors.New("评价不存在")
+	}
+
+	if err := s.reviewRepo.Delete(reviewID); err != nil {
+		return err
+	}
+	return s.refreshCompany(companyID)
+}
+
+// refreshCompany 重新计算公司聚合值并同步搜索索引
+func (s *CompanyReviewService) refreshCompany(companyID uint) error {
+	if err := s.companyRepo.RefreshAggregate(companyID); err != nil {
+		return err
+	}
+	indexCompany(s.indexer, s.companyRepo, companyID)
+	return nil
+}
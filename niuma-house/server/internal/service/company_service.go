@@ -2,26 +2,60 @@ package service
 
 import (
 	"context"
+	"errors"
+	"sort"
+	"strings"
 
 	"niuma-house/internal/model"
 	"niuma-house/internal/repository"
 )
 
+// 查重参数
+const (
+	duplicateThreshold     = 0.7 // 相似度阈值
+	duplicateCandidateSize = 5   // 最多返回的候选数
+	duplicateScanLimit     = 200 // 参与相似度计算的最大记录数
+	maxMergeRedirects      = 5   // 合并重定向的最大跳转次数
+)
+
+// CompanyCandidate 疑似重复的公司
+type CompanyCandidate struct {
+	Company    model.Company `json:"company"`
+	Similarity float64       `json:"similarity"`
+}
+
+// DuplicateCompanyError 创建公司时发现疑似重复
+type DuplicateCompanyError struct {
+	Candidates []CompanyCandidate
+}
+
+func (e *DuplicateCompanyError) Error() string {
+	return "已存在疑似相同的公司，请确认后再提交"
+}
+
 // CompanyService 公司服务
 type CompanyService struct {
 	companyRepo *repository.CompanyRepository
+	viewRepo    *repository.ViewRepository
 	searcher    repository.Searcher
+	indexer     repository.SearchIndexer
+	anon        *anonymizer
 }
 
 // NewCompanyService 创建公司服务
 func NewCompanyService() *CompanyService {
+	searcher := repository.NewMySQLSearcher() // 使用 MySQL 全文搜索实现
 	return &CompanyService{
 		companyRepo: repository.NewCompanyRepository(),
-		searcher:    repository.NewMySQLSearcher(), // 使用 MySQL 搜索实现
+		viewRepo:    repository.NewViewRepository(),
+		searcher:    searcher,
+		indexer:     searcher,
+		anon:        newAnonymizer(),
 	}
 }
 
 // CreateCompanyRequest 创建公司请求
+// 避雷等级、标签、证据和描述作为创建者的首条评价
 type CreateCompanyRequest struct {
 	Name      string   `json:"name" binding:"required,max=100"`
 	City      string   `json:"city" binding:"max=50"`
@@ -29,57 +63,246 @@ type CreateCompanyRequest struct {
 	RiskLevel int      `json:"risk_level" binding:"min=1,max=5"`
 	Evidence  []string `json:"evidence"`
 	Content   string   `json:"content"`
+	Force     bool     `json:"force"`     // 忽略疑似重复，强制创建
+	Anonymous bool     `json:"anonymous"` // 匿名曝光，首条评价同样匿名
 }
 
-// Create 创建公司
+// Create 创建公司，发现疑似重复且未强制创建时返回 DuplicateCompanyError
 func (s *CompanyService) Create(userID uint, req *CreateCompanyRequest) (*model.Company, error) {
+	if err := validateTags(req.Tags); err != nil {
+		return nil, err
+	}
+
+	if !req.Force {
+		candidates, err := s.FindDuplicates(req.Name)
+		if err != nil {
+			return nil, err
+		}
+		if len(candidates) > 0 {
+			return nil, &DuplicateCompanyError{Candidates: candidates}
+		}
+	}
+
+	// 曝光内容同时作为首条评价，过滤一次即可
+	result, err := filterContent(&req.Content)
+	if err != nil {
+		return nil, err
+	}
+
 	company := &model.Company{
 		Name:      req.Name,
 		City:      req.City,
-		Tags:      req.Tags,
-		RiskLevel: req.RiskLevel,
 		Evidence:  req.Evidence,
 		Content:   req.Content,
 		CreatorID: userID,
+		Status:    model.CompanyStatusNormal,
+		Anonymous: req.Anonymous,
+	}
+	hold := contentHold(result)
+	if hold != nil {
+		company.Status = model.CompanyStatusHidden
+	}
+	review := &model.CompanyReview{
+		UserID:    userID,
+		RiskLevel: req.RiskLevel,
+		Tags:      req.Tags,
+		Content:   req.Content,
+		Evidence:  req.Evidence,
 		Status:    1,
+		Anonymous: req.Anonymous,
 	}
 
-	if err := s.companyRepo.Create(company); err != nil {
+	if err := s.companyRepo.CreateWithReview(company, review, hold); err != nil {
 		return nil, err
 	}
 
+	// 送审中的公司对外不可见，不建索引
+	if hold != nil {
+		s.anon.Company(company, userID)
+		return company, nil
+	}
+
+	indexCompany(s.indexer, s.companyRepo, company.ID)
+	created, err := s.companyRepo.FindByID(company.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.anon.Company(created, userID)
+	return created, nil
+}
+
+// FindDuplicates 按名称查找疑似重复的公司，按相似度降序
+func (s *CompanyService) FindDuplicates(name string) ([]CompanyCandidate, error) {
+	normalized := model.NormalizeCompanyName(name)
+	companies, err := s.companyRepo.FindCandidates(normalized, duplicateScanLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]CompanyCandidate, 0)
+	for _, company := range companies {
+		similarity := companyNameSimilarity(normalized, company.NormalizedName)
+		if similarity >= duplicateThreshold {
+			s.anon.Company(&company, 0)
+			candidates = append(candidates, CompanyCandidate{Company: company, Similarity: similarity})
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Similarity > candidates[j].Similarity
+	})
+	if len(candidates) > duplicateCandidateSize {
+		candidates = candidates[:duplicateCandidateSize]
+	}
+	return candidates, nil
+}
+
+// companyNameSimilarity 归一化名称相似度：相同为 1，互相包含为 0.9，否则按编辑距离计算
+func companyNameSimilarity(a, b string) float64 {
+	if a == "" || b == "" {
+		return 0
+	}
+	if a == b {
+		return 1
+	}
+	if strings.Contains(a, b) || strings.Contains(b, a) {
+		return 0.9
+	}
+
+	ra, rb := []rune(a), []rune(b)
+	maxLen := len(ra)
+	if len(rb) > maxLen {
+		maxLen = len(rb)
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(maxLen)
+}
+
+// levenshtein 编辑距离
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// findMergeTarget 查找公司，已合并的公司沿 MergedIntoID 返回最终合并到的公司
+func findMergeTarget(companyRepo *repository.CompanyRepository, id uint) (*model.Company, error) {
+	company, err := companyRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; company.Status == model.CompanyStatusMerged && company.MergedIntoID != nil; i++ {
+		if i >= maxMergeRedirects {
+			return nil, errors.New("公司重定向次数过多")
+		}
+		if company, err = companyRepo.FindByID(*company.MergedIntoID); err != nil {
+			return nil, err
+		}
+	}
 	return company, nil
 }
 
-// GetByID 获取公司详情
-func (s *CompanyService) GetByID(id uint) (*model.Company, error) {
-	company, err := s.companyRepo.FindByID(id)
+// GetByID 获取公司详情，viewer 用于浏览量去重，viewerID 为当前用户
+// 已合并的公司会重定向到合并后的公司
+func (s *CompanyService) GetByID(id uint, viewer string, viewerID uint) (*model.Company, error) {
+	company, err := findMergeTarget(s.companyRepo, id)
 	if err != nil {
 		return nil, err
 	}
+	id = company.ID
 
-	// 增加浏览量
-	s.companyRepo.IncrementViews(id)
+	// 记录浏览量（缓冲在 Redis 中，定时落库），返回值合并未落库的增量
+	s.viewRepo.Record(repository.ViewTargetCompany, id, viewer)
+	company.ViewCount += s.viewRepo.Pending(repository.ViewTargetCompany, id)
 
+	s.anon.Company(company, viewerID)
 	return company, nil
 }
 
 // List 公司列表
-func (s *CompanyService) List(page, size int) ([]model.Company, int64, error) {
-	return s.companyRepo.List(page, size)
+func (s *CompanyService) List(page, size int, viewerID uint) ([]model.Company, int64, error) {
+	companies, total, err := s.companyRepo.List(page, size)
+	if err != nil {
+		return nil, 0, err
+	}
+	s.mergePendingViews(companies)
+	s.anon.Companies(companies, viewerID)
+	return companies, total, nil
+}
+
+// Search 全文搜索公司
+func (s *CompanyService) Search(ctx context.Context, q *repository.SearchQuery, viewerID uint) ([]repository.CompanyHit, int64, error) {
+	hits, total, err := s.searcher.SearchCompanies(ctx, q)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ids := make([]uint, len(hits))
+	for i := range hits {
+		ids[i] = hits[i].ID
+	}
+	pending := s.viewRepo.PendingMulti(repository.ViewTargetCompany, ids)
+	for i := range hits {
+		hits[i].ViewCount += pending[hits[i].ID]
+		s.anon.Company(&hits[i].Company, viewerID)
+	}
+	return hits, total, nil
 }
 
-// Search 搜索公司
-func (s *CompanyService) Search(ctx context.Context, keyword string, page, size int) ([]model.Company, int64, error) {
-	return s.searcher.SearchCompanies(ctx, keyword, page, size)
+// mergePendingViews 合并未落库的浏览增量
+func (s *CompanyService) mergePendingViews(companies []model.Company) {
+	ids := make([]uint, len(companies))
+	for i := range companies {
+		ids[i] = companies[i].ID
+	}
+	pending := s.viewRepo.PendingMulti(repository.ViewTargetCompany, ids)
+	for i := range companies {
+		companies[i].ViewCount += pending[companies[i].ID]
+	}
 }
 
-// AdminList 管理端列表
+// AdminList 管理端列表，匿名曝光的真实创建者需通过审计接口查看
 func (s *CompanyService) AdminList(page, size int) ([]model.Company, int64, error) {
-	return s.companyRepo.AdminList(page, size)
+	companies, total, err := s.companyRepo.AdminList(page, size)
+	if err != nil {
+		return nil, 0, err
+	}
+	s.anon.Companies(companies, 0)
+	return companies, total, nil
+}
+
+// AdminMerge 管理员合并公司：source 的评价、证据、浏览量并入 target，source 重定向到 target
+func (s *CompanyService) AdminMerge(sourceID, targetID uint) error {
+	if sourceID == targetID {
+		return errors.New("不能合并到自身")
+	}
+	if err := s.companyRepo.Merge(sourceID, targetID); err != nil {
+		return err
+	}
+	removeCompany(s.indexer, sourceID)
+	indexCompany(s.indexer, s.companyRepo, targetID)
+	return s.viewRepo.Move(repository.ViewTargetCompany, sourceID, targetID)
 }
 
 // AdminDelete 管理员删除
 func (s *CompanyService) AdminDelete(companyID uint) error {
-	return s.companyRepo.Delete(companyID)
+	if err := s.companyRepo.Delete(companyID); err != nil {
+		return err
+	}
+	removeCompany(s.indexer, companyID)
+	return nil
 }
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"math"
+	"testing"
+
+	"niuma-house/internal/model"
+)
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "腾讯", 2},
+		{"腾讯", "", 2},
+		{"腾讯科技", "腾讯科技", 0},
+		{"阿里巴巴网络技术", "阿里巴巴网路技术", 1}, // 替换
+		{"京东商城", "京东", 2},           // 删除
+		{"美团", "美团点评", 2},           // 插入
+		{"kitten", "sitting", 3},
+	}
+	for _, tt := range tests {
+		if got := levenshtein([]rune(tt.a), []rune(tt.b)); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := levenshtein([]rune(tt.b), []rune(tt.a)); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
+
+func TestCompanyNameSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"", "腾讯", 0},
+		{"腾讯", "", 0},
+		{"腾讯科技", "腾讯科技", 1},
+		{"北京字节跳动科技", "字节跳动科技", 0.9},
+		{"字节跳动", "北京字节跳动科技", 0.9},
+		{"阿里巴巴网络技术", "阿里巴巴网路技术", 0.875},
+		{"华为技术", "华润置地", 0.25},
+	}
+	for _, tt := range tests {
+		if got := companyNameSimilarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("companyNameSimilarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// TestCompanyNameDuplicate 同一公司的后缀、地区写法差异应判为疑似重复，不同公司不应误判
+func TestCompanyNameDuplicate(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"腾讯科技（深圳）有限公司", "腾讯科技(北京)有限公司", true},
+		{"腾讯科技有限公司", "腾讯科技集团", true},
+		{"北京字节跳动科技有限公司", "字节跳动科技有限公司", true},
+		{"字节跳动", "北京字节跳动网络技术有限公司", true},
+		{"中国平安保险（集团）股份有限公司", "中国平安保险有限公司", true},
+		{"阿里巴巴网络技术有限公司", "阿里巴巴网路技术有限公司", true},
+		{"小米科技有限责任公司", "小米科技有限公司", true},
+		{"ＡＢＣ　Ｔｅｃｈ有限公司", "abc tech 有限公司", true},
+		{"华为技术有限公司", "华润置地有限公司", false},
+		{"拼多多有限公司", "多点科技有限公司", false},
+		{"上海米哈游网络科技股份有限公司", "上海莉莉丝网络科技有限公司", false},
+	}
+	for _, tt := range tests {
+		similarity := companyNameSimilarity(model.NormalizeCompanyName(tt.a), model.NormalizeCompanyName(tt.b))
+		if got := similarity >= duplicateThreshold; got != tt.want {
+			t.Errorf("duplicate(%q, %q) = %v (similarity %.3f), want %v", tt.a, tt.b, got, similarity, tt.want)
+		}
+	}
+}
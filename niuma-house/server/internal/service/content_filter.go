@@ -0,0 +1,106 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"niuma-house/internal/repository"
+	"niuma-house/pkg/filter"
+)
+
+// ErrContentRejected 内容命中拒绝发布的敏感词或个人信息
+var ErrContentRejected = errors.New("内容包含不允许发布的信息")
+
+// 已加载的词库版本，-1 表示尚未加载
+var (
+	wordsMu      sync.Mutex
+	wordsVersion int64 = -1
+)
+
+// filterContent 依次过滤各字段，并将字段替换为打码后的文本；
+// 命中拒绝发布的内容时返回 ErrContentRejected，错误信息中附带命中的分类
+func filterContent(fields ...*string) (*filter.Result, error) {
+	merged := &filter.Result{}
+	for _, field := range fields {
+		if *field == "" {
+			continue
+		}
+		result := filter.Get().Run(*field)
+		*field = result.Text
+		merged.Hits = append(merged.Hits, result.Hits...)
+		if result.Action > merged.Action {
+			merged.Action = result.Action
+		}
+	}
+
+	if merged.Action == filter.ActionReject {
+		return merged, rejectedError(merged, filter.ActionReject)
+	}
+	return merged, nil
+}
+
+// filterContentNoReview 用于无法进入审核队列的内容（如公司评价），需人工审核的命中同样拒绝发布
+func filterContentNoReview(fields ...*string) error {
+	result, err := filterContent(fields...)
+	if err != nil {
+		return err
+	}
+	if result.Action == filter.ActionReview {
+		return rejectedError(result, filter.ActionReview)
+	}
+	return nil
+}
+
+// rejectedError 拒绝发布的错误，附带处理方式不低于 action 的命中分类
+func rejectedError(result *filter.Result, action filter.Action) error {
+	return fmt.Errorf("%w（%s），请修改后再发布",
+		ErrContentRejected, strings.Join(result.Labels(action), "、"))
+}
+
+// contentHold 过滤结果需要人工审核时返回送审信息，内容需以隐藏状态写入并与工单在同一事务中提交
+func contentHold(result *filter.Result) *repository.ContentHold {
+	if result.Action != filter.ActionReview {
+		return nil
+	}
+
+	hits := make([]string, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		hits = append(hits, hit.Category+":"+hit.Text)
+	}
+	summary := strings.Join(hits, "、")
+	if runes := []rune(summary); len(runes) > 1000 {
+		summary = string(runes[:1000])
+	}
+	return &repository.ContentHold{Hits: summary}
+}
+
+// ReloadSensitiveWords 词库版本变化时重新加载敏感词，force 为 true 时总是加载
+// 启动时、管理员修改词库后以及定时任务中调用，多实例通过 Redis 中的版本号同步
+func ReloadSensitiveWords(force bool) error {
+	wordRepo := repository.NewSensitiveWordRepository()
+
+	wordsMu.Lock()
+	defer wordsMu.Unlock()
+
+	version := wordRepo.Version()
+	if !force && version >= 0 && version == wordsVersion {
+		return nil
+	}
+
+	records, err := wordRepo.All()
+	if err != nil {
+		return err
+	}
+	words := make([]filter.Word, 0, len(records))
+	for _, w := range records {
+		words = append(words, filter.Word{Text: w.Word, Category: w.Category, Action: filter.ParseAction(w.Action)})
+	}
+	filter.Words().SetWords(words)
+	wordsVersion = version
+
+	log.Printf("Sensitive words loaded: %d words, version=%d", len(words), version)
+	return nil
+}
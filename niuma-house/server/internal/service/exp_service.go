@@ -0,0 +1,23 @@
+package service
+
+import (
+	"niuma-house/internal/model"
+	"niuma-house/internal/repository"
+)
+
+// ExpService 经验值服务
+type ExpService struct {
+	expRepo *repository.ExpRepository
+}
+
+// NewExpService 创建经验值服务
+func NewExpService() *ExpService {
+	return &ExpService{
+		expRepo: repository.NewExpRepository(),
+	}
+}
+
+// History 经验流水，包含被每日上限截断和已撤销的记录
+func (s *ExpService) History(userID uint, page, size int) ([]model.ExpLedger, int64, error) {
+	return s.expRepo.ListByUserID(userID, page, size)
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"log"
+	"strings"
+
+	"niuma-house/internal/model"
+	"niuma-house/internal/repository"
+	"niuma-house/pkg/filter"
+)
+
+// AddSensitiveWordsRequest 批量添加敏感词请求，单次最多 1000 个，已存在的词跳过
+type AddSensitiveWordsRequest struct {
+	Words    []string `json:"words" binding:"required,min=1,max=1000,dive,max=100"`
+	Category string   `json:"category" binding:"required,max=20"`
+	Action   string   `json:"action" binding:"required,oneof=mask review reject"`
+}
+
+// UpdateSensitiveWordRequest 修改敏感词请求
+type UpdateSensitiveWordRequest struct {
+	Category string `json:"category" binding:"required,max=20"`
+	Action   string `json:"action" binding:"required,oneof=mask review reject"`
+}
+
+// FilterTestRequest 过滤测试请求
+type FilterTestRequest struct {
+	Text string `json:"text" binding:"required,max=10000"`
+}
+
+// FilterService 内容过滤词库管理服务
+type FilterService struct {
+	wordRepo *repository.SensitiveWordRepository
+}
+
+// NewFilterService 创建内容过滤服务
+func NewFilterService() *FilterService {
+	return &FilterService{
+		wordRepo: repository.NewSensitiveWordRepository(),
+	}
+}
+
+// ListWords 敏感词列表
+func (s *FilterService) ListWords(keyword, category string, page, size int) ([]model.SensitiveWord, int64, error) {
+	return s.wordRepo.List(keyword, category, page, size)
+}
+
+// AddWords 批量添加敏感词，去掉首尾空白和重复项，返回新增条数
+func (s *FilterService) AddWords(creatorID uint, req *AddSensitiveWordsRequest) (int64, error) {
+	seen := make(map[string]bool)
+	words := make([]model.SensitiveWord, 0, len(req.Words))
+	for _, w := range req.Words {
+		w = strings.TrimSpace(w)
+		if w == "" || seen[w] {
+			continue
+		}
+		seen[w] = true
+		words = append(words, model.SensitiveWord{
+			Word:      w,
+			Category:  req.Category,
+			Action:    req.Action,
+			CreatorID: creatorID,
+		})
+	}
+
+	added, err := s.wordRepo.CreateBatch(words)
+	if err != nil {
+		return 0, err
+	}
+	s.reload()
+	return added, nil
+}
+
+// UpdateWord 修改敏感词的分类和处理方式
+func (s *FilterService) UpdateWord(id uint, req *UpdateSensitiveWordRequest) (*model.SensitiveWord, error) {
+	word, err := s.wordRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	word.Category = req.Category
+	word.Action = req.Action
+	if err := s.wordRepo.Update(word); err != nil {
+		return nil, err
+	}
+	s.reload()
+	return word, nil
+}
+
+// DeleteWord 删除敏感词
+func (s *FilterService) DeleteWord(id uint) error {
+	if _, err := s.wordRepo.FindByID(id); err != nil {
+		return err
+	}
+	if err := s.wordRepo.Delete(id); err != nil {
+		return err
+	}
+	s.reload()
+	return nil
+}
+
+// Test 用当前过滤链检查一段文本，不落库，便于调试词库和配置
+func (s *FilterService) Test(text string) *filter.Result {
+	return filter.Get().Run(text)
+}
+
+// reload 词库变更后立即重新加载本实例的词库，其他实例由定时任务同步
+func (s *FilterService) reload() {
+	if err := ReloadSensitiveWords(true); err != nil {
+		log.Printf("Failed to reload sensitive words: %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+
+	"niuma-house/internal/model"
+	"niuma-house/internal/mq"
+	"niuma-house/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// LevelService 等级服务
+type LevelService struct {
+	userRepo  *repository.UserRepository
+	levelRepo *repository.LevelRepository
+	badgeRepo *repository.BadgeRepository
+}
+
+// NewLevelService 创建等级服务
+func NewLevelService() *LevelService {
+	return &LevelService{
+		userRepo:  repository.NewUserRepository(),
+		levelRepo: repository.NewLevelRepository(),
+		badgeRepo: repository.NewBadgeRepository(),
+	}
+}
+
+// SaveLevelRequest 新增/修改等级请求
+type SaveLevelRequest struct {
+	Name           string `json:"name" binding:"required,max=50"`
+	MinExp         int    `json:"min_exp" binding:"min=0"`
+	DailyPostQuota int    `json:"daily_post_quota" binding:"min=0"`
+	CanMessage     bool   `json:"can_message"`
+	CanUploadImage bool   `json:"can_upload_image"`
+	BadgeName      string `json:"badge_name" binding:"max=50"`
+	BadgeIcon      string `json:"badge_icon" binding:"max=255"`
+}
+
+// List 全部等级，按经验阈值升序
+func (s *LevelService) List() ([]model.Level, error) {
+	return s.levelRepo.List()
+}
+
+// Perks 用户当前等级的权益
+func (s *LevelService) Perks(userID uint) (*model.Level, error) {
+	return userPerks(s.userRepo, s.levelRepo, userID)
+}
+
+// Badges 用户获得的徽章
+func (s *LevelService) Badges(userID uint) ([]model.UserBadge, error) {
+	return s.badgeRepo.ListByUserID(userID)
+}
+
+// Save 新增或修改等级，校验通过后按新配置校准所有用户等级
+func (s *LevelService) Save(level int, req *SaveLevelRequest) (*model.Level, error) {
+	levels, err := s.levelRepo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	saved := &model.Level{
+		Level:          level,
+		Name:           req.Name,
+		MinExp:         req.MinExp,
+		DailyPostQuota: req.DailyPostQuota,
+		CanMessage:     req.CanMessage,
+		CanUploadImage: req.CanUploadImage,
+		BadgeName:      req.BadgeName,
+		BadgeIcon:      req.BadgeIcon,
+	}
+
+	next := make([]model.Level, 0, len(levels)+1)
+	for _, l := range levels {
+		if l.Level != level {
+			next = append(next, l)
+		}
+	}
+	if err := validateLevels(append(next, *saved)); err != nil {
+		return nil, err
+	}
+
+	if err := s.levelRepo.Save(saved); err != nil {
+		return nil, err
+	}
+	s.recalculate()
+	return saved, nil
+}
+
+// Delete 删除等级，Lv.1 不能删除；原等级的用户在校准后落入相邻等级
+func (s *LevelService) Delete(level int) error {
+	if level == 1 {
+		return errors.New("不能删除 Lv.1")
+	}
+	levels, err := s.levelRepo.List()
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(levels, func(l model.Level) bool { return l.Level == level }) {
+		return gorm.ErrRecordNotFound
+	}
+	if err := s.levelRepo.Delete(level); err != nil {
+		return err
+	}
+	s.recalculate()
+	return nil
+}
+
+// recalculate 按最新配置校准用户等级，失败时由每日任务兜底
+func (s *LevelService) recalculate() {
+	levels, err := s.levelRepo.List()
+	if err != nil {
+		log.Printf("Failed to load levels: %v", err)
+		return
+	}
+	if _, err := s.userRepo.RecalculateLevels(levels, mq.LevelUpEvents); err != nil {
+		log.Printf("Failed to recalculate user levels: %v", err)
+	}
+}
+
+// validateLevels 校验等级配置：必须包含经验阈值为 0 的 Lv.1，等级越高阈值越高
+func validateLevels(levels []model.Level) error {
+	sort.Slice(levels, func(i, j int) bool { return levels[i].Level < levels[j].Level })
+
+	if len(levels) == 0 || levels[0].Level != 1 || levels[0].MinExp != 0 {
+		return errors.New("Lv.1 的经验阈值必须为 0")
+	}
+	for i := 1; i < len(levels); i++ {
+		if levels[i].MinExp <= levels[i-1].MinExp {
+			return fmt.Errorf("Lv.%d 的经验阈值必须高于 Lv.%d", levels[i].Level, levels[i-1].Level)
+		}
+	}
+	return nil
+}
+
+// userPerks 查询用户等级对应的权益配置
+func userPerks(userRepo *repository.UserRepository, levelRepo *repository.LevelRepository, userID uint) (*model.Level, error) {
+	level, err := userRepo.GetLevel(userID)
+	if err != nil {
+		return nil, err
+	}
+	return levelRepo.Find(level)
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"niuma-house/internal/model"
 	"niuma-house/internal/repository"
+	"niuma-house/internal/ws"
 )
 
 // MessageService 私信服务
@@ -43,9 +44,13 @@ func (s *MessageService) GetMessages(userID, otherUserID uint, page, size int) (
 	return s.messageRepo.ListByConversation(userID, otherUserID, page, size)
 }
 
-// MarkAsRead 标记消息已读
+// MarkAsRead 标记消息已读，并实时推送已读回执给发送方
 func (s *MessageService) MarkAsRead(receiverID, senderID uint) error {
-	return s.messageRepo.MarkAsRead(receiverID, senderID)
+	if err := s.messageRepo.MarkAsRead(receiverID, senderID); err != nil {
+		return err
+	}
+	ws.NotifyRead(senderID, receiverID, 0)
+	return nil
 }
 
 // GetUnreadCount 获取未读消息数
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"niuma-house/internal/model"
+	"niuma-house/internal/repository"
+)
+
+// NotificationService 通知服务
+type NotificationService struct {
+	notificationRepo *repository.NotificationRepository
+}
+
+// NewNotificationService 创建通知服务
+func NewNotificationService() *NotificationService {
+	return &NotificationService{
+		notificationRepo: repository.NewNotificationRepository(),
+	}
+}
+
+// List 通知列表
+func (s *NotificationService) List(userID uint, page, size int) ([]model.Notification, int64, error) {
+	return s.notificationRepo.ListByUserID(userID, page, size)
+}
+
+// GetUnreadCount 获取未读通知数
+func (s *NotificationService) GetUnreadCount(userID uint) int64 {
+	return s.notificationRepo.CountUnread(userID)
+}
+
+// MarkAllAsRead 标记全部通知已读
+func (s *NotificationService) MarkAllAsRead(userID uint) error {
+	return s.notificationRepo.MarkAllAsRead(userID)
+}
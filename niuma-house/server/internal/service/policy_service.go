@@ -0,0 +1,103 @@
+package service
+
+import (
+	"errors"
+
+	"niuma-house/pkg/rbac"
+)
+
+// PolicyService 权限策略服务
+type PolicyService struct{}
+
+// NewPolicyService 创建权限策略服务
+func NewPolicyService() *PolicyService {
+	return &PolicyService{}
+}
+
+// PolicyRequest 权限策略请求
+type PolicyRequest struct {
+	Role   string `json:"role" binding:"required"`
+	Path   string `json:"path" binding:"required"`
+	Method string `json:"method" binding:"required"`
+}
+
+// RoleRequest 角色继承请求
+type RoleRequest struct {
+	Role   string `json:"role" binding:"required"`
+	Parent string `json:"parent" binding:"required"`
+}
+
+// PolicyList 权限策略列表
+type PolicyList struct {
+	Policies [][]string `json:"policies"` // [角色, 路径, 方法]
+	Roles    [][]string `json:"roles"`    // [角色, 继承的角色]
+}
+
+// List 获取全部策略
+func (s *PolicyService) List() (*PolicyList, error) {
+	e := rbac.GetEnforcer()
+
+	policies, err := e.GetPolicy()
+	if err != nil {
+		return nil, err
+	}
+	roles, err := e.GetGroupingPolicy()
+	if err != nil {
+		return nil, err
+	}
+
+	return &PolicyList{Policies: policies, Roles: roles}, nil
+}
+
+// AddPolicy 新增策略
+func (s *PolicyService) AddPolicy(req *PolicyRequest) error {
+	added, err := rbac.GetEnforcer().AddPolicy(req.Role, req.Path, req.Method)
+	if err != nil {
+		return err
+	}
+	if !added {
+		return errors.New("策略已存在")
+	}
+	return nil
+}
+
+// RemovePolicy 删除策略
+func (s *PolicyService) RemovePolicy(req *PolicyRequest) error {
+	removed, err := rbac.GetEnforcer().RemovePolicy(req.Role, req.Path, req.Method)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return errors.New("策略不存在")
+	}
+	return nil
+}
+
+// AddRole 新增角色继承
+func (s *PolicyService) AddRole(req *RoleRequest) error {
+	added, err := rbac.GetEnforcer().AddGroupingPolicy(req.Role, req.Parent)
+	if err != nil {
+		return err
+	}
+	if !added {
+		return errors.New("角色继承已存在")
+	}
+	return nil
+}
+
+// RemoveRole 删除角色继承
+func (s *PolicyService) RemoveRole(req *RoleRequest) error {
+	removed, err := rbac.GetEnforcer().RemoveGroupingPolicy(req.Role, req.Parent)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return errors.New("角色继承不存在")
+	}
+	return nil
+}
+
+// Reload 从数据库重新加载策略
+func (s *PolicyService) Reload() error {
+	return rbac.Reload()
+}
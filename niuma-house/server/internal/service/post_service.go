@@ -1,7 +1,10 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"niuma-house/internal/model"
 	"niuma-house/internal/mq"
@@ -10,17 +13,30 @@ import (
 
 // PostService 帖子服务
 type PostService struct {
-	postRepo *repository.PostRepository
-	likeRepo *repository.LikeRepository
-	favRepo  *repository.FavoriteRepository
+	postRepo  *repository.PostRepository
+	userRepo  *repository.UserRepository
+	levelRepo *repository.LevelRepository
+	likeRepo  *repository.LikeRepository
+	favRepo   *repository.FavoriteRepository
+	viewRepo  *repository.ViewRepository
+	searcher  repository.Searcher
+	indexer   repository.SearchIndexer
+	anon      *anonymizer
 }
 
 // NewPostService 创建帖子服务
 func NewPostService() *PostService {
+	searcher := repository.NewMySQLSearcher()
 	return &PostService{
-		postRepo: repository.NewPostRepository(),
-		likeRepo: repository.NewLikeRepository(),
-		favRepo:  repository.NewFavoriteRepository(),
+		postRepo:  repository.NewPostRepository(),
+		userRepo:  repository.NewUserRepository(),
+		levelRepo: repository.NewLevelRepository(),
+		likeRepo:  repository.NewLikeRepository(),
+		favRepo:   repository.NewFavoriteRepository(),
+		viewRepo:  repository.NewViewRepository(),
+		searcher:  searcher,
+		indexer:   searcher,
+		anon:      newAnonymizer(),
 	}
 }
 
@@ -29,6 +45,7 @@ type CreatePostRequest struct {
 	Title        string `json:"title" binding:"required,max=200"`
 	Content      string `json:"content" binding:"required"`
 	OccupationID uint   `json:"occupation_id" binding:"required"`
+	Anonymous    bool   `json:"anonymous"` // 匿名发布，对外只展示化名和职业/等级区间
 }
 
 // UpdatePostRequest 更新帖子请求
@@ -37,52 +54,99 @@ type UpdatePostRequest struct {
 	Content string `json:"content"`
 }
 
-// Create 创建帖子
+// Create 创建帖子，内容需人工审核时帖子以隐藏状态写入并送审，返回的帖子状态为 PostStatusHidden，
+// 发帖经验在审核驳回（帖子恢复）后才发放
 func (s *PostService) Create(userID uint, req *CreatePostRequest) (*model.Post, error) {
+	if err := s.checkDailyQuota(userID); err != nil {
+		return nil, err
+	}
+
+	// 敏感词和个人信息过滤，需打码的片段直接替换
+	result, err := filterContent(&req.Title, &req.Content)
+	if err != nil {
+		return nil, err
+	}
+
 	post := &model.Post{
 		UserID:       userID,
 		OccupationID: req.OccupationID,
 		Title:        req.Title,
 		Content:      req.Content,
 		Status:       1,
+		Anonymous:    req.Anonymous,
+	}
+	hold := contentHold(result)
+	if hold != nil {
+		post.Status = model.PostStatusHidden
 	}
 
-	if err := s.postRepo.Create(post); err != nil {
+	// 经验值事件与帖子在同一事务中写入 outbox
+	err = s.postRepo.Create(post, func() []*model.OutboxEvent {
+		return []*model.OutboxEvent{mq.ExpEvent(userID, mq.ActionPost, mq.PostSubject(post.ID), 5)}
+	}, hold)
+	if err != nil {
 		return nil, err
 	}
+	if hold == nil {
+		indexPost(s.indexer, post)
+	}
 
-	// 发送经验值消息
-	mq.PublishExpMessage(userID, mq.ActionPost, 5)
-
+	s.anon.Post(post, userID)
 	return post, nil
 }
 
-// GetByID 获取帖子详情
-func (s *PostService) GetByID(id uint, userID uint) (*model.Post, bool, bool, error) {
+// checkDailyQuota 校验用户今日发帖数是否已达到等级的每日上限
+func (s *PostService) checkDailyQuota(userID uint) error {
+	perks, err := userPerks(s.userRepo, s.levelRepo, userID)
+	if err != nil || perks.DailyPostQuota == 0 {
+		return err
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	count, err := s.postRepo.CountByUserSince(userID, today)
+	if err != nil {
+		return err
+	}
+	if count >= int64(perks.DailyPostQuota) {
+		return fmt.Errorf("今日发帖已达上限（%d 篇），升级后可提高额度", perks.DailyPostQuota)
+	}
+	return nil
+}
+
+// GetByID 获取帖子详情，viewer 用于浏览量去重
+func (s *PostService) GetByID(id uint, userID uint, viewer string) (*model.Post, bool, bool, error) {
 	post, err := s.postRepo.FindByID(id)
 	if err != nil {
 		return nil, false, false, err
 	}
 
-	// 增加浏览量
-	s.postRepo.IncrementViews(id)
+	// 记录浏览量（缓冲在 Redis 中，定时落库），返回值合并未落库的增量
+	s.viewRepo.Record(repository.ViewTargetPost, id, viewer)
+	post.ViewsCount += s.viewRepo.Pending(repository.ViewTargetPost, id)
 
 	// 检查是否点赞/收藏
 	isLiked := s.likeRepo.IsLiked(id, userID)
 	isFavorited := s.favRepo.IsFavorited(id, userID)
 
+	s.anon.Post(post, userID)
 	return post, isLiked, isFavorited, nil
 }
 
-// Update 更新帖子
-func (s *PostService) Update(id, userID uint, req *UpdatePostRequest) error {
+// Update 更新帖子，返回帖子是否因内容需人工审核而被隐藏
+func (s *PostService) Update(id, userID uint, req *UpdatePostRequest) (bool, error) {
 	post, err := s.postRepo.FindByID(id)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	if post.UserID != userID {
-		return errors.New("无权编辑他人帖子")
+		return false, errors.New("无权编辑他人帖子")
+	}
+
+	result, err := filterContent(&req.Title, &req.Content)
+	if err != nil {
+		return false, err
 	}
 
 	if req.Title != "" {
@@ -92,7 +156,16 @@ func (s *PostService) Update(id, userID uint, req *UpdatePostRequest) error {
 		post.Content = req.Content
 	}
 
-	return s.postRepo.Update(post)
+	hold := contentHold(result)
+	if err := s.postRepo.UpdateContent(post.ID, post.Title, post.Content, hold); err != nil {
+		return false, err
+	}
+	if hold != nil {
+		removePost(s.indexer, post.ID)
+		return true, nil
+	}
+	indexPost(s.indexer, post)
+	return false, nil
 }
 
 // Delete 删除帖子
@@ -106,12 +179,53 @@ func (s *PostService) Delete(id, userID uint) error {
 		return errors.New("无权删除他人帖子")
 	}
 
-	return s.postRepo.Delete(id)
+	if err := s.postRepo.Delete(id, revokePostExp(post)); err != nil {
+		return err
+	}
+	removePost(s.indexer, id)
+	return nil
+}
+
+// List 帖子列表，viewerID 为当前用户
+func (s *PostService) List(occupationID uint, page, size int, viewerID uint) ([]model.Post, int64, error) {
+	posts, total, err := s.postRepo.List(occupationID, page, size)
+	if err != nil {
+		return nil, 0, err
+	}
+	s.mergePendingViews(posts)
+	s.anon.Posts(posts, viewerID)
+	return posts, total, nil
+}
+
+// Search 全文搜索帖子
+func (s *PostService) Search(ctx context.Context, q *repository.SearchQuery, viewerID uint) ([]repository.PostHit, int64, error) {
+	hits, total, err := s.searcher.SearchPosts(ctx, q)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ids := make([]uint, len(hits))
+	for i := range hits {
+		ids[i] = hits[i].ID
+	}
+	pending := s.viewRepo.PendingMulti(repository.ViewTargetPost, ids)
+	for i := range hits {
+		hits[i].ViewsCount += pending[hits[i].ID]
+		s.anon.Post(&hits[i].Post, viewerID)
+	}
+	return hits, total, nil
 }
 
-// List 帖子列表
-func (s *PostService) List(occupationID uint, page, size int) ([]model.Post, int64, error) {
-	return s.postRepo.List(occupationID, page, size)
+// mergePendingViews 合并未落库的浏览增量
+func (s *PostService) mergePendingViews(posts []model.Post) {
+	ids := make([]uint, len(posts))
+	for i := range posts {
+		ids[i] = posts[i].ID
+	}
+	pending := s.viewRepo.PendingMulti(repository.ViewTargetPost, ids)
+	for i := range posts {
+		posts[i].ViewsCount += pending[posts[i].ID]
+	}
 }
 
 // Like 点赞
@@ -120,19 +234,35 @@ func (s *PostService) Like(postID, userID uint) error {
 		return errors.New("已点赞过该帖子")
 	}
 
-	if err := s.likeRepo.Like(postID, userID); err != nil {
-		return err
+	post, err := s.postRepo.FindByID(postID)
+	if err != nil {
+		return errors.New("帖子不存在")
 	}
 
-	s.postRepo.IncrementLikes(postID)
+	// 给作者加经验并通知（自己点赞自己的帖子不加）
+	var events repository.OutboxEvents
+	if post.UserID != userID {
+		events = func() []*model.OutboxEvent {
+			return []*model.OutboxEvent{
+				mq.ExpEvent(post.UserID, mq.ActionLiked, mq.LikeSubject(postID, userID), 2),
+				mq.NotificationEvent(mq.NotificationMessage{
+					UserID:  post.UserID,
+					Type:    model.NotificationLike,
+					ActorID: userID,
+					PostID:  postID,
+					Content: fmt.Sprintf("有人赞了你的帖子《%s》", post.Title),
+					DedupeKey: model.NotificationDedupeKey(post.UserID, model.NotificationLike,
+						mq.LikeSubject(postID, userID)),
+				}),
+			}
+		}
+	}
 
-	// 获取帖子作者，给作者加经验
-	post, err := s.postRepo.FindByID(postID)
-	if err == nil && post.UserID != userID {
-		mq.PublishExpMessage(post.UserID, mq.ActionLiked, 2)
+	if err := s.likeRepo.Like(postID, userID, events); err != nil {
+		return err
 	}
 
-	return nil
+	return s.postRepo.IncrementLikes(postID)
 }
 
 // Unlike 取消点赞
@@ -141,7 +271,15 @@ func (s *PostService) Unlike(postID, userID uint) error {
 		return errors.New("未点赞过该帖子")
 	}
 
-	if err := s.likeRepo.Unlike(postID, userID); err != nil {
+	// 撤销作者因该点赞获得的经验，重新点赞不会再次发放
+	var events repository.OutboxEvents
+	if post, err := s.postRepo.FindByID(postID); err == nil && post.UserID != userID {
+		events = func() []*model.OutboxEvent {
+			return []*model.OutboxEvent{mq.ExpRevokeEvent(post.UserID, mq.ActionLiked, mq.LikeSubject(postID, userID))}
+		}
+	}
+
+	if err := s.likeRepo.Unlike(postID, userID, events); err != nil {
 		return err
 	}
 
@@ -164,17 +302,38 @@ func (s *PostService) Unfavorite(postID, userID uint) error {
 	return s.favRepo.Unfavorite(postID, userID)
 }
 
-// AdminList 管理端列表
+// AdminList 管理端列表，匿名帖子同样只展示化名，真实作者需通过审计接口查看
 func (s *PostService) AdminList(page, size int) ([]model.Post, int64, error) {
-	return s.postRepo.AdminList(page, size)
+	posts, total, err := s.postRepo.AdminList(page, size)
+	if err != nil {
+		return nil, 0, err
+	}
+	s.anon.Posts(posts, 0)
+	return posts, total, nil
 }
 
 // AdminDelete 管理员删除
 func (s *PostService) AdminDelete(postID uint) error {
-	return s.postRepo.Delete(postID)
+	post, err := s.postRepo.FindByID(postID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.postRepo.Delete(postID, revokePostExp(post)); err != nil {
+		return err
+	}
+	removePost(s.indexer, postID)
+	return nil
 }
 
 // SetTop 置顶
 func (s *PostService) SetTop(postID uint) error {
 	return s.postRepo.SetTop(postID)
 }
+
+// revokePostExp 删除帖子时撤销作者的发帖经验
+func revokePostExp(post *model.Post) repository.OutboxEvents {
+	return func() []*model.OutboxEvent {
+		return []*model.OutboxEvent{mq.ExpRevokeEvent(post.UserID, mq.ActionPost, mq.PostSubject(post.ID))}
+	}
+}
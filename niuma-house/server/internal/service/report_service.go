@@ -0,0 +1,250 @@
+package service
+
+import (
+	"errors"
+
+	"niuma-house/internal/model"
+	"niuma-house/internal/repository"
+	"niuma-house/pkg/config"
+
+	"gorm.io/gorm"
+)
+
+// 工单内容快照的最大长度（字符）
+const reportSnapshotMaxLen = 1000
+
+var (
+	// ErrReportSelf 不能举报自己或自己的内容
+	ErrReportSelf = errors.New("不能举报自己或自己的内容")
+	// ErrInvalidReportReason 举报理由不在预置分类中
+	ErrInvalidReportReason = errors.New("无效的举报理由")
+	// ErrInvalidModerationAction 该类内容不支持所选处置方式
+	ErrInvalidModerationAction = errors.New("该类内容不支持所选处置方式")
+)
+
+// ReportService 举报与审核服务
+type ReportService struct {
+	reportRepo    *repository.ReportRepository
+	userRepo      *repository.UserRepository
+	sanctionRepo  *repository.SanctionRepository
+	postRepo      *repository.PostRepository
+	commentRepo   *repository.CommentRepository
+	companyRepo   *repository.CompanyRepository
+	messageRepo   *repository.MessageRepository
+	indexer       repository.SearchIndexer
+	hideThreshold int
+}
+
+// NewReportService 创建举报服务
+func NewReportService() *ReportService {
+	return &ReportService{
+		reportRepo:    repository.NewReportRepository(),
+		userRepo:      repository.NewUserRepository(),
+		sanctionRepo:  repository.NewSanctionRepository(),
+		postRepo:      repository.NewPostRepository(),
+		commentRepo:   repository.NewCommentRepository(),
+		companyRepo:   repository.NewCompanyRepository(),
+		messageRepo:   repository.NewMessageRepository(),
+		indexer:       repository.NewMySQLSearcher(),
+		hideThreshold: config.GetConfig().Moderation.AutoHideThreshold,
+	}
+}
+
+// ReportRequest 举报请求
+type ReportRequest struct {
+	Reason string `json:"reason" binding:"required,max=20"` // 举报理由分类，见 model.ReportReasons
+	Detail string `json:"detail" binding:"max=500"`
+}
+
+// ResolveRequest 结案请求
+type ResolveRequest struct {
+	Status string `json:"status" binding:"required,oneof=approved rejected actioned"`
+	Action string `json:"action" binding:"omitempty,oneof=delete ban"` // actioned 时必填
+	Note   string `json:"note" binding:"required,max=1000"`
+}
+
+// AssignRequest 分配工单请求，AssigneeID 为空时分配给自己，为 0 时取消分配
+type AssignRequest struct {
+	AssigneeID *uint `json:"assignee_id"`
+}
+
+// Reasons 举报理由分类
+func (s *ReportService) Reasons() []model.ReportReason {
+	return model.ReportReasons
+}
+
+// Report 举报内容，同一用户对同一内容只能举报一次；私信只能由接收方举报
+func (s *ReportService) Report(reporterID uint, targetType string, targetID uint, req *ReportRequest) error {
+	if !model.IsValidReportReason(req.Reason) {
+		return ErrInvalidReportReason
+	}
+
+	if targetType == model.ReportTargetMessage {
+		message, err := s.messageRepo.FindByID(targetID)
+		if err != nil {
+			return err
+		}
+		if message.ReceiverID != reporterID {
+			return gorm.ErrRecordNotFound
+		}
+	}
+
+	target, err := s.reportRepo.Target(targetType, targetID)
+	if err != nil {
+		return err
+	}
+	if target.OwnerID == reporterID {
+		return ErrReportSelf
+	}
+	if runes := []rune(target.Snapshot); len(runes) > reportSnapshotMaxLen {
+		target.Snapshot = string(runes[:reportSnapshotMaxLen])
+	}
+
+	report := &model.Report{
+		ReporterID: reporterID,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Reason:     req.Reason,
+		Detail:     req.Detail,
+	}
+	_, err = s.reportRepo.Submit(report, target, s.hideThreshold)
+	return err
+}
+
+// ListCases 审核队列
+func (s *ReportService) ListCases(filter *repository.CaseFilter, page, size int) ([]model.ModerationCase, int64, error) {
+	cases, total, err := s.reportRepo.ListCases(filter, page, size)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range cases {
+		maskCaseAuthor(&cases[i])
+	}
+	return cases, total, nil
+}
+
+// GetCase 工单详情
+func (s *ReportService) GetCase(id uint) (*model.ModerationCase, error) {
+	mc, err := s.reportRepo.FindCase(id)
+	if err != nil {
+		return nil, err
+	}
+	maskCaseAuthor(mc)
+	return mc, nil
+}
+
+// Assign 分配工单，只能分配给管理员
+func (s *ReportService) Assign(caseID, adminID uint, req *AssignRequest) error {
+	assigneeID := &adminID
+	if req.AssigneeID != nil {
+		assigneeID = req.AssigneeID
+	}
+	if *assigneeID == 0 {
+		return s.reportRepo.Assign(caseID, nil)
+	}
+
+	assignee, err := s.userRepo.FindByID(*assigneeID)
+	if err != nil {
+		return err
+	}
+	if assignee.Role == "user" || assignee.Status == 0 {
+		return errors.New("只能分配给管理员")
+	}
+	return s.reportRepo.Assign(caseID, assigneeID)
+}
+
+// Resolve 结案，actioned 时先执行处置（删除内容或封禁作者）
+func (s *ReportService) Resolve(caseID, adminID uint, req *ResolveRequest) (*model.ModerationCase, error) {
+	mc, err := s.reportRepo.FindCase(caseID)
+	if err != nil {
+		return nil, err
+	}
+	if mc.Status == model.ModerationActioned {
+		return nil, repository.ErrCaseClosed
+	}
+
+	action := ""
+	if req.Status == model.ModerationActioned {
+		if req.Action == "" {
+			return nil, errors.New("请选择处置方式")
+		}
+		action = req.Action
+		if err := s.applyAction(mc, adminID, action, req.Note); err != nil {
+			return nil, err
+		}
+	}
+
+	resolved, err := s.reportRepo.Resolve(caseID, adminID, req.Status, action, req.Note)
+	if err != nil {
+		return nil, err
+	}
+	if resolved.Status == model.ModerationRejected {
+		s.reindexTarget(resolved)
+	}
+	maskCaseAuthor(resolved)
+	return resolved, nil
+}
+
+// applyAction 执行处置：删除内容时撤销相应经验并移除索引，封禁时记录永久封禁并踢下线
+func (s *ReportService) applyAction(mc *model.ModerationCase, adminID uint, action, note string) error {
+	if action == model.ModerationActionBan {
+		if runes := []rune(note); len(runes) > sanctionReasonMaxLen {
+			note = string(runes[:sanctionReasonMaxLen])
+		}
+		return issueSanction(s.sanctionRepo, s.userRepo, &model.UserSanction{
+			UserID:   mc.TargetUserID,
+			Type:     model.SanctionBan,
+			Reason:   note,
+			IssuerID: adminID,
+		})
+	}
+
+	switch mc.TargetType {
+	case model.ReportTargetPost:
+		post := &model.Post{ID: mc.TargetID, UserID: mc.TargetUserID}
+		if err := s.postRepo.Delete(mc.TargetID, revokePostExp(post)); err != nil {
+			return err
+		}
+		removePost(s.indexer, mc.TargetID)
+		return nil
+	case model.ReportTargetComment:
+		comment, err := s.commentRepo.FindByID(mc.TargetID)
+		if err != nil {
+			return err
+		}
+		var events repository.OutboxEvents
+		if post, err := s.postRepo.FindByID(comment.PostID); err == nil {
+			events = func() []*model.OutboxEvent {
+				return revokeCommentExp(post, comment)
+			}
+		}
+		return s.commentRepo.Delete(mc.TargetID, events)
+	case model.ReportTargetCompany:
+		if err := s.companyRepo.Delete(mc.TargetID); err != nil {
+			return err
+		}
+		removeCompany(s.indexer, mc.TargetID)
+		return nil
+	}
+	return ErrInvalidModerationAction
+}
+
+// reindexTarget 驳回后内容恢复可见，重新同步搜索索引（送审时新建的内容此前未建索引）
+func (s *ReportService) reindexTarget(mc *model.ModerationCase) {
+	switch mc.TargetType {
+	case model.ReportTargetPost:
+		if post, err := s.postRepo.FindByID(mc.TargetID); err == nil {
+			indexPost(s.indexer, post)
+		}
+	case model.ReportTargetCompany:
+		indexCompany(s.indexer, s.companyRepo, mc.TargetID)
+	}
+}
+
+// maskCaseAuthor 匿名内容的工单不展示真实作者，需通过审计接口查看
+func maskCaseAuthor(mc *model.ModerationCase) {
+	if mc.Anonymous {
+		mc.TargetUserID = 0
+		mc.TargetUser = nil
+	}
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"niuma-house/internal/model"
+	"niuma-house/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// 定时任务每批解除的到期处罚数
+const sanctionLiftBatch = 500
+
+// 处罚理由的最大字符数，与处罚记录字段长度一致
+const sanctionReasonMaxLen = 500
+
+// ErrSanctionSelf 不能处罚自己
+var ErrSanctionSelf = errors.New("不能处罚自己")
+
+// IssueSanctionRequest 处罚请求
+type IssueSanctionRequest struct {
+	Type     string `json:"type" binding:"required,oneof=mute_post mute_dm ban"`
+	Reason   string `json:"reason" binding:"required,max=500"`
+	Duration int    `json:"duration" binding:"min=0"` // 处罚时长（小时），0 表示永久
+}
+
+// BanRequest 封禁请求，兼容旧版封禁接口，理由和时长均可不填
+type BanRequest struct {
+	Reason   string `json:"reason" binding:"max=500"`
+	Duration int    `json:"duration" binding:"min=0"` // 封禁时长（小时），0 表示永久
+}
+
+// SanctionService 用户处罚服务
+type SanctionService struct {
+	sanctionRepo *repository.SanctionRepository
+	userRepo     *repository.UserRepository
+}
+
+// NewSanctionService 创建用户处罚服务
+func NewSanctionService() *SanctionService {
+	return &SanctionService{
+		sanctionRepo: repository.NewSanctionRepository(),
+		userRepo:     repository.NewUserRepository(),
+	}
+}
+
+// Issue 处罚用户
+func (s *SanctionService) Issue(userID, issuerID uint, req *IssueSanctionRequest) (*model.UserSanction, error) {
+	if userID == issuerID {
+		return nil, ErrSanctionSelf
+	}
+	if _, err := s.userRepo.FindByID(userID); err != nil {
+		return nil, err
+	}
+
+	sanction := &model.UserSanction{
+		UserID:   userID,
+		Type:     req.Type,
+		Reason:   req.Reason,
+		IssuerID: issuerID,
+	}
+	if req.Duration > 0 {
+		expiresAt := time.Now().Add(time.Duration(req.Duration) * time.Hour)
+		sanction.ExpiresAt = &expiresAt
+	}
+	if err := issueSanction(s.sanctionRepo, s.userRepo, sanction); err != nil {
+		return nil, err
+	}
+	return sanction, nil
+}
+
+// Ban 封禁用户，未填写理由时记为管理员封禁
+func (s *SanctionService) Ban(userID, issuerID uint, req *BanRequest) (*model.UserSanction, error) {
+	reason := req.Reason
+	if reason == "" {
+		reason = "管理员封禁"
+	}
+	return s.Issue(userID, issuerID, &IssueSanctionRequest{
+		Type:     model.SanctionBan,
+		Reason:   reason,
+		Duration: req.Duration,
+	})
+}
+
+// Lift 提前解除处罚，解除封禁后若没有其他生效的封禁则恢复账号
+func (s *SanctionService) Lift(id, adminID uint) error {
+	sanction, err := s.sanctionRepo.FindByID(id)
+	if err != nil {
+		return err
+	}
+	if err := s.sanctionRepo.Lift(id, &adminID); err != nil {
+		return err
+	}
+	if sanction.Type == model.SanctionBan {
+		return restoreBannedUser(s.sanctionRepo, s.userRepo, sanction.UserID)
+	}
+	return nil
+}
+
+// Unban 解除用户所有生效中的封禁并恢复账号
+func (s *SanctionService) Unban(userID, adminID uint) error {
+	if _, err := s.sanctionRepo.LiftByType(userID, model.SanctionBan, &adminID); err != nil {
+		return err
+	}
+	return s.userRepo.Unban(userID)
+}
+
+// List 处罚记录列表
+func (s *SanctionService) List(filter *repository.SanctionFilter, page, size int) ([]model.UserSanction, int64, error) {
+	return s.sanctionRepo.List(filter, page, size)
+}
+
+// LiftExpired 解除所有已到期的处罚，返回解除的条数
+func (s *SanctionService) LiftExpired() (int, error) {
+	lifted := 0
+	for {
+		sanctions, err := s.sanctionRepo.Expired(time.Now(), sanctionLiftBatch)
+		if err != nil {
+			return lifted, err
+		}
+
+		for _, sanction := range sanctions {
+			if err := s.sanctionRepo.Lift(sanction.ID, nil); err != nil {
+				if errors.Is(err, repository.ErrSanctionLifted) {
+					continue
+				}
+				return lifted, err
+			}
+			lifted++
+
+			if sanction.Type == model.SanctionBan {
+				if err := restoreBannedUser(s.sanctionRepo, s.userRepo, sanction.UserID); err != nil {
+					log.Printf("Failed to restore banned user: userID=%d, err=%v", sanction.UserID, err)
+				}
+			}
+		}
+
+		if len(sanctions) < sanctionLiftBatch {
+			return lifted, nil
+		}
+	}
+}
+
+// issueSanction 记录处罚，封禁时同步账号状态并踢下线，审核处置时也会调用
+func issueSanction(sanctionRepo *repository.SanctionRepository, userRepo *repository.UserRepository, sanction *model.UserSanction) error {
+	if err := sanctionRepo.Create(sanction); err != nil {
+		return err
+	}
+	if sanction.Type == model.SanctionBan {
+		return banUser(userRepo, sanction.UserID)
+	}
+	return nil
+}
+
+// restoreBannedUser 用户没有其他生效中的封禁时恢复账号
+func restoreBannedUser(sanctionRepo *repository.SanctionRepository, userRepo *repository.UserRepository, userID uint) error {
+	_, err := sanctionRepo.FindActive(userID, model.SanctionBan)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return userRepo.Unban(userID)
+	}
+	return err
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"niuma-house/internal/model"
+	"niuma-house/internal/repository"
+)
+
+// 搜索索引同步：失败只记录日志，不影响主流程
+
+// indexPost 同步帖子索引
+func indexPost(indexer repository.SearchIndexer, post *model.Post) {
+	if err := indexer.IndexPost(context.Background(), post); err != nil {
+		log.Printf("Failed to index post: id=%d, err=%v", post.ID, err)
+	}
+}
+
+// removePost 移除帖子索引
+func removePost(indexer repository.SearchIndexer, postID uint) {
+	if err := indexer.RemovePost(context.Background(), postID); err != nil {
+		log.Printf("Failed to remove post index: id=%d, err=%v", postID, err)
+	}
+}
+
+// indexCompany 重新读取公司并同步索引（评价变化会改变标签和避雷等级）
+func indexCompany(indexer repository.SearchIndexer, companyRepo *repository.CompanyRepository, companyID uint) {
+	company, err := companyRepo.FindByID(companyID)
+	if err != nil {
+		log.Printf("Failed to load company for indexing: id=%d, err=%v", companyID, err)
+		return
+	}
+	if err := indexer.IndexCompany(context.Background(), company); err != nil {
+		log.Printf("Failed to index company: id=%d, err=%v", companyID, err)
+	}
+}
+
+// removeCompany 移除公司索引
+func removeCompany(indexer repository.SearchIndexer, companyID uint) {
+	if err := indexer.RemoveCompany(context.Background(), companyID); err != nil {
+		log.Printf("Failed to remove company index: id=%d, err=%v", companyID, err)
+	}
+}
@@ -2,9 +2,11 @@ package service
 
 import (
 	"errors"
+	"log"
 
 	"niuma-house/internal/model"
 	"niuma-house/internal/repository"
+	"niuma-house/internal/ws"
 	"niuma-house/pkg/jwt"
 
 	"gorm.io/gorm"
@@ -12,13 +14,21 @@ import (
 
 // UserService 用户服务
 type UserService struct {
-	userRepo *repository.UserRepository
+	userRepo     *repository.UserRepository
+	levelRepo    *repository.LevelRepository
+	checkinRepo  *repository.CheckinRepository
+	outboxRepo   *repository.OutboxRepository
+	sanctionRepo *repository.SanctionRepository
 }
 
 // NewUserService 创建用户服务
 func NewUserService() *UserService {
 	return &UserService{
-		userRepo: repository.NewUserRepository(),
+		userRepo:     repository.NewUserRepository(),
+		levelRepo:    repository.NewLevelRepository(),
+		checkinRepo:  repository.NewCheckinRepository(),
+		outboxRepo:   repository.NewOutboxRepository(),
+		sanctionRepo: repository.NewSanctionRepository(),
 	}
 }
 
@@ -37,8 +47,22 @@ type LoginRequest struct {
 
 // LoginResponse 登录响应
 type LoginResponse struct {
-	Token string      `json:"token"`
-	User  *model.User `json:"user"`
+	Token        string         `json:"token"`
+	RefreshToken string         `json:"refresh_token"`
+	ExpiresIn    int64          `json:"expires_in"`
+	User         *model.User    `json:"user"`
+	Checkin      *CheckinResult `json:"checkin,omitempty"` // 当天首次登录时自动签到的结果
+}
+
+// RefreshRequest 刷新令牌请求
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// ChangePasswordRequest 修改密码请求
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=6,max=32"`
 }
 
 // Register 用户注册
@@ -93,28 +117,113 @@ func (s *UserService) Login(req *LoginRequest) (*LoginResponse, error) {
 		return nil, errors.New("用户名或密码错误")
 	}
 
-	// 生成 Token
-	token, err := jwt.GenerateToken(user.ID, user.Username, user.Role)
+	// 创建会话并签发 Token
+	pair, err := jwt.NewSession(user.ID, user.Username, user.Role)
 	if err != nil {
 		return nil, err
 	}
 
+	// 每天首次登录自动签到，签到失败不影响登录
+	result, err := checkin(s.checkinRepo, s.outboxRepo, user.ID)
+	if err != nil && !errors.Is(err, ErrAlreadyCheckedIn) {
+		log.Printf("Failed to check in on login: userID=%d, err=%v", user.ID, err)
+	}
+
 	// 清除密码
 	user.Password = ""
+	s.fillLevelName(user)
 
 	return &LoginResponse{
-		Token: token,
-		User:  user,
+		Token:        pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+		ExpiresIn:    pair.ExpiresIn,
+		User:         user,
+		Checkin:      result,
 	}, nil
 }
 
-// GetProfile 获取用户资料
+// Refresh 使用刷新令牌换取新的令牌对
+func (s *UserService) Refresh(req *RefreshRequest) (*jwt.TokenPair, error) {
+	claims, err := jwt.ParseToken(req.RefreshToken)
+	if err != nil {
+		return nil, errors.New("刷新令牌无效或已过期")
+	}
+	if claims.TokenType != jwt.TokenTypeRefresh {
+		return nil, jwt.ErrWrongTokenType
+	}
+
+	// 重新读取用户，角色变更和封禁即时生效
+	user, err := s.userRepo.FindByID(claims.UserID)
+	if err != nil {
+		return nil, jwt.ErrSessionRevoked
+	}
+	if user.Status == 0 {
+		jwt.RevokeUserSessions(user.ID)
+		return nil, errors.New("账号已被封禁")
+	}
+
+	return jwt.Rotate(claims, user.Username, user.Role)
+}
+
+// Logout 退出登录，注销当前会话
+func (s *UserService) Logout(userID uint, sessionID string) error {
+	return jwt.RevokeSession(userID, sessionID)
+}
+
+// ChangePassword 修改密码，成功后注销该用户所有会话
+func (s *UserService) ChangePassword(userID uint, req *ChangePasswordRequest) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+
+	if !user.CheckPassword(req.OldPassword) {
+		return errors.New("原密码错误")
+	}
+
+	if err := user.SetPassword(req.NewPassword); err != nil {
+		return err
+	}
+	if err := s.userRepo.UpdatePassword(userID, user.Password); err != nil {
+		return err
+	}
+
+	return s.Kick(userID)
+}
+
+// Kick 踢下线：注销用户所有会话并断开 WebSocket
+func (s *UserService) Kick(userID uint) error {
+	return kickUser(userID)
+}
+
+// kickUser 注销用户所有会话并断开 WebSocket
+func kickUser(userID uint) error {
+	if err := jwt.RevokeUserSessions(userID); err != nil {
+		return err
+	}
+	ws.GetHub().Disconnect(userID)
+	return nil
+}
+
+// banUser 封禁账号并踢下线，由 issueSanction 在记录封禁处罚后调用
+func banUser(userRepo *repository.UserRepository, userID uint) error {
+	if err := userRepo.Ban(userID); err != nil {
+		return err
+	}
+	return kickUser(userID)
+}
+
+// GetProfile 获取用户资料，附带当前生效的处罚
 func (s *UserService) GetProfile(userID uint) (*model.User, error) {
 	user, err := s.userRepo.FindByID(userID)
 	if err != nil {
 		return nil, err
 	}
 	user.Password = ""
+	s.fillLevelName(user)
+	if sanctions, err := s.sanctionRepo.Active(userID); err == nil {
+		user.Sanctions = sanctions
+	}
 	return user, nil
 }
 
@@ -145,6 +254,26 @@ func (s *UserService) UpdateProfile(userID uint, req *UpdateProfileRequest) erro
 	return s.userRepo.Update(user)
 }
 
+// PrivacyRequest 隐私设置请求
+type PrivacyRequest struct {
+	MessagePrivacy  string `json:"message_privacy" binding:"required,oneof=everyone level nobody"`
+	MessageMinLevel int    `json:"message_min_level" binding:"omitempty,min=1"`
+}
+
+// UpdatePrivacy 更新私信隐私设置
+func (s *UserService) UpdatePrivacy(userID uint, req *PrivacyRequest) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+
+	user.MessagePrivacy = req.MessagePrivacy
+	if req.MessageMinLevel > 0 {
+		user.MessageMinLevel = req.MessageMinLevel
+	}
+	return s.userRepo.Update(user)
+}
+
 // AddExp 增加经验值
 func (s *UserService) AddExp(userID uint, exp int) error {
 	if err := s.userRepo.UpdateExp(userID, exp); err != nil {
@@ -157,7 +286,10 @@ func (s *UserService) AddExp(userID uint, exp int) error {
 		return err
 	}
 
-	newLevel := model.CalculateLevel(user.Exp)
+	newLevel, err := s.levelRepo.ForExp(user.Exp)
+	if err != nil {
+		return err
+	}
 	if newLevel != user.Level {
 		return s.userRepo.UpdateLevel(userID, newLevel)
 	}
@@ -165,17 +297,21 @@ func (s *UserService) AddExp(userID uint, exp int) error {
 	return nil
 }
 
-// Ban 封禁用户
-func (s *UserService) Ban(userID uint) error {
-	return s.userRepo.Ban(userID)
-}
-
-// Unban 解封用户
-func (s *UserService) Unban(userID uint) error {
-	return s.userRepo.Unban(userID)
-}
-
 // List 用户列表
 func (s *UserService) List(page, size int) ([]model.User, int64, error) {
-	return s.userRepo.List(page, size)
+	users, total, err := s.userRepo.List(page, size)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range users {
+		s.fillLevelName(&users[i])
+	}
+	return users, total, nil
+}
+
+// fillLevelName 填充等级名称，等级配置读取失败时留空
+func (s *UserService) fillLevelName(user *model.User) {
+	if level, err := s.levelRepo.Find(user.Level); err == nil {
+		user.LevelName = level.Name
+	}
 }
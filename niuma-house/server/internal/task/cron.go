@@ -2,15 +2,20 @@ package task
 
 import (
 	"log"
+	"time"
 
-	"niuma-house/internal/model"
-	"niuma-house/pkg/database"
+	"niuma-house/internal/mq"
+	"niuma-house/internal/repository"
+	"niuma-house/internal/service"
 
 	"github.com/robfig/cron/v3"
 )
 
 var cronScheduler *cron.Cron
 
+// 已发送的 outbox 事件保留时间，便于排查
+const outboxRetention = 7 * 24 * time.Hour
+
 // StartCronJobs 启动定时任务
 func StartCronJobs() {
 	cronScheduler = cron.New()
@@ -21,6 +26,15 @@ func StartCronJobs() {
 	// 每小时执行一次 - 清理过期数据
 	cronScheduler.AddFunc("0 * * * *", hourlyCleanup)
 
+	// 每分钟执行一次 - 浏览量落库
+	cronScheduler.AddFunc("@every 1m", flushViewCounts)
+
+	// 每分钟执行一次 - 解除到期的处罚
+	cronScheduler.AddFunc("@every 1m", liftExpiredSanctions)
+
+	// 每分钟执行一次 - 同步其他实例修改的敏感词库
+	cronScheduler.AddFunc("@every 1m", reloadSensitiveWords)
+
 	cronScheduler.Start()
 	log.Println("Cron jobs started")
 }
@@ -36,28 +50,76 @@ func StopCronJobs() {
 func dailyTask() {
 	log.Println("Running daily task...")
 
-	db := database.GetDB()
+	// 补发昨日签到未写入的经验事件
+	yesterday := time.Now().AddDate(0, 0, -1)
+	reissued, err := service.NewCheckinService().Reconcile(yesterday)
+	if err != nil {
+		log.Printf("Failed to reconcile checkins: %v", err)
+	} else if reissued > 0 {
+		log.Printf("Reissued checkin exp events: %d users", reissued)
+	}
+
+	// 按当前等级配置重新计算所有用户等级（校准）
+	levels, err := repository.NewLevelRepository().List()
+	if err != nil {
+		log.Printf("Failed to load levels: %v", err)
+		return
+	}
+	calibrated, err := repository.NewUserRepository().RecalculateLevels(levels, mq.LevelUpEvents)
+	if err != nil {
+		log.Printf("Failed to calibrate user levels: %v", err)
+	} else if calibrated > 0 {
+		log.Printf("User levels calibrated: %d users", calibrated)
+	}
+
+	log.Println("Daily task completed")
+}
 
-	// 重新计算所有用户等级（校准）
-	var users []model.User
-	db.Find(&users)
+// flushViewCounts 将 Redis 中缓冲的浏览量批量写回 MySQL
+func flushViewCounts() {
+	viewRepo := repository.NewViewRepository()
 
-	for _, user := range users {
-		newLevel := model.CalculateLevel(user.Exp)
-		if newLevel != user.Level {
-			db.Model(&user).Update("level", newLevel)
-			log.Printf("User %d level calibrated: %d -> %d", user.ID, user.Level, newLevel)
+	for _, target := range []string{repository.ViewTargetPost, repository.ViewTargetCompany} {
+		flushed, err := viewRepo.Flush(target)
+		if err != nil {
+			log.Printf("Failed to flush %s view counts: %v", target, err)
+			continue
+		}
+		if flushed > 0 {
+			log.Printf("Flushed %s view counts: %d rows", target, flushed)
 		}
 	}
+}
 
-	log.Println("Daily task completed")
+// liftExpiredSanctions 解除到期的禁言、禁私信和封禁，封禁到期后恢复账号
+func liftExpiredSanctions() {
+	lifted, err := service.NewSanctionService().LiftExpired()
+	if err != nil {
+		log.Printf("Failed to lift expired sanctions: %v", err)
+	}
+	if lifted > 0 {
+		log.Printf("Lifted expired sanctions: %d", lifted)
+	}
+}
+
+// reloadSensitiveWords 词库版本变化时重新加载敏感词
+func reloadSensitiveWords() {
+	if err := service.ReloadSensitiveWords(false); err != nil {
+		log.Printf("Failed to reload sensitive words: %v", err)
+	}
 }
 
 // hourlyCleanup 每小时清理
 func hourlyCleanup() {
 	log.Println("Running hourly cleanup...")
 
-	// 可以添加清理逻辑，如清理过期会话等
+	// 清理已发送超过保留期的 outbox 事件
+	purged, err := repository.NewOutboxRepository().PurgeSent(time.Now().Add(-outboxRetention))
+	if err != nil {
+		log.Printf("Failed to purge outbox events: %v", err)
+	} else if purged > 0 {
+		log.Printf("Purged sent outbox events: %d rows", purged)
+	}
 
 	log.Println("Hourly cleanup completed")
 }
@@ -0,0 +1,72 @@
+package ws
+
+import (
+	"encoding/json"
+	"log"
+
+	"niuma-house/pkg/cache"
+	"niuma-house/pkg/config"
+)
+
+// 分发后端类型
+const (
+	BackendLocal = "local" // 单实例，进程内投递
+	BackendRedis = "redis" // 多实例，Redis Pub/Sub 广播
+)
+
+// delivery 待投递给某个用户全部连接的数据，跨实例传输时序列化为 JSON
+type delivery struct {
+	UserID     uint            `json:"user_id"`
+	Data       json.RawMessage `json:"data,omitempty"`
+	Disconnect bool            `json:"disconnect,omitempty"` // 断开该用户的全部连接
+	Presence   bool            `json:"presence,omitempty"`   // 该用户的在线状态发生变化
+	Unwatch    uint            `json:"unwatch,omitempty"`    // 该用户的连接取消对此用户在线状态的订阅
+}
+
+// Backend 跨实例分发后端
+// 负责把投递广播到所有实例，并维护集群范围的在线状态
+type Backend interface {
+	// Publish 广播投递，所有实例（包括自身）的订阅回调都会收到
+	Publish(d *delivery) error
+	// Subscribe 注册订阅回调并开始接收
+	Subscribe(handler func(d *delivery))
+	// SetOnline 本实例上用户的首个连接建立 / 最后一个连接断开
+	SetOnline(userID uint, online bool)
+	// RefreshOnline 续期本实例上在线用户的状态
+	RefreshOnline(userIDs []uint)
+	// IsOnline 用户是否在其他实例上在线
+	IsOnline(userID uint) bool
+}
+
+// newBackend 按配置创建分发后端
+func newBackend(cfg *config.WSConfig) Backend {
+	if cfg != nil && cfg.Backend == BackendRedis {
+		log.Println("WebSocket backend: redis")
+		return newRedisBackend(cache.GetRedis())
+	}
+	log.Println("WebSocket backend: local")
+	return &localBackend{}
+}
+
+// localBackend 单实例后端，直接在进程内回调
+type localBackend struct {
+	handler func(d *delivery)
+}
+
+func (b *localBackend) Publish(d *delivery) error {
+	b.handler(d)
+	return nil
+}
+
+func (b *localBackend) Subscribe(handler func(d *delivery)) {
+	b.handler = handler
+}
+
+func (b *localBackend) SetOnline(userID uint, online bool) {}
+
+func (b *localBackend) RefreshOnline(userIDs []uint) {}
+
+// IsOnline 单实例下在线状态完全由本地连接决定
+func (b *localBackend) IsOnline(userID uint) bool {
+	return false
+}
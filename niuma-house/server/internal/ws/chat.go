@@ -0,0 +1,183 @@
+package ws
+
+import (
+	"log"
+	"strings"
+
+	"niuma-house/internal/model"
+	"niuma-house/pkg/filter"
+)
+
+// 单次补发的最大私信数，需小于连接发送缓冲
+const syncBatchSize = 100
+
+// chatPayload 将私信转为推送负载
+func chatPayload(m *model.Message) *ChatMessagePayload {
+	p := &ChatMessagePayload{
+		ID:         m.ID,
+		SenderID:   m.SenderID,
+		ReceiverID: m.ReceiverID,
+		Content:    m.Content,
+		CreatedAt:  m.CreatedAt.Unix(),
+	}
+	if m.ClientMsgID != nil {
+		p.ClientMsgID = *m.ClientMsgID
+	}
+	return p
+}
+
+// handleChatSend 持久化并投递私信，按 client_msg_id 去重
+// 接收方离线时私信只落库，重连后通过 chat.sync 补发
+func (c *Client) handleChatSend(env *Envelope) {
+	var p ChatSendPayload
+	if err := decodePayload(env, &p); err != nil {
+		c.sendError(env, ErrCodeInvalidPayload, err.Error())
+		return
+	}
+	content := strings.TrimSpace(p.Content)
+	if content == "" {
+		c.sendError(env, ErrCodeInvalidPayload, "消息内容不能为空")
+		return
+	}
+	if e := c.checkReceiver(p.ReceiverID); e != nil {
+		c.sendError(env, e.Code, e.Message)
+		return
+	}
+
+	// 敏感词和个人信息过滤：私信不进入审核队列，需审核的内容同样拒绝发送
+	result := filter.Get().Run(content)
+	if result.Action >= filter.ActionReview {
+		c.sendError(env, ErrCodeContentRejected,
+			"私信包含不允许发送的信息（"+strings.Join(result.Labels(filter.ActionReview), "、")+"）")
+		return
+	}
+	content = result.Text
+
+	dbMsg := &model.Message{
+		SenderID:   c.userID,
+		ReceiverID: p.ReceiverID,
+		Content:    content,
+	}
+	if p.ClientMsgID != "" {
+		dbMsg.ClientMsgID = &p.ClientMsgID
+	}
+
+	created, err := c.messageRepo.CreateDedup(dbMsg)
+	if err != nil {
+		log.Printf("Failed to save message: %v", err)
+		c.sendError(env, ErrCodeServerError, "消息发送失败")
+		return
+	}
+
+	payload := chatPayload(dbMsg)
+	if created {
+		c.hub.SendToUser(p.ReceiverID, NewEnvelope(EventChatMessage, payload))
+	}
+
+	// 发送回执给发送者的全部设备，保持多端同步；重复提交时只补发回执
+	c.hub.SendToUser(c.userID, NewEnvelope(EventChatSent, payload))
+}
+
+// handleChatAck 接收方确认送达/已读，回执推送给发送方
+func (c *Client) handleChatAck(env *Envelope) {
+	var p ChatAckPayload
+	if err := decodePayload(env, &p); err != nil {
+		c.sendError(env, ErrCodeInvalidPayload, err.Error())
+		return
+	}
+
+	m, err := c.messageRepo.FindByID(p.MessageID)
+	if err != nil || m.ReceiverID != c.userID {
+		c.sendError(env, ErrCodeInvalidPayload, "消息不存在")
+		return
+	}
+
+	if p.Status == AckDelivered {
+		updated, err := c.messageRepo.MarkDelivered(c.userID, m.ID)
+		if err != nil {
+			log.Printf("Failed to mark message delivered: id=%d, err=%v", m.ID, err)
+			return
+		}
+		if updated {
+			c.hub.SendToUser(m.SenderID, NewEnvelope(EventChatAck, &ChatAckPayload{
+				MessageID:  m.ID,
+				Status:     AckDelivered,
+				SenderID:   m.SenderID,
+				ReceiverID: m.ReceiverID,
+			}))
+		}
+		return
+	}
+
+	count, err := c.messageRepo.MarkReadUpTo(c.userID, m.SenderID, m.ID)
+	if err != nil {
+		log.Printf("Failed to mark messages read: id=%d, err=%v", m.ID, err)
+		return
+	}
+	if count > 0 {
+		NotifyRead(m.SenderID, c.userID, m.ID)
+	}
+}
+
+// NotifyRead 推送已读回执给发送方，并同步给接收方的其他设备
+// maxID 为 0 表示与该用户的全部私信
+func NotifyRead(senderID, receiverID, maxID uint) {
+	env := NewEnvelope(EventChatAck, &ChatAckPayload{
+		MessageID:  maxID,
+		Status:     AckRead,
+		SenderID:   senderID,
+		ReceiverID: receiverID,
+	})
+	hub := GetHub()
+	hub.SendToUser(senderID, env)
+	hub.SendToUser(receiverID, env)
+}
+
+// handleChatSync 补发 last_id 之后收发的私信
+func (c *Client) handleChatSync(env *Envelope) {
+	var p ChatSyncPayload
+	if err := decodePayload(env, &p); err != nil {
+		c.sendError(env, ErrCodeInvalidPayload, err.Error())
+		return
+	}
+	c.sync(p.LastID)
+}
+
+// sync 补发 lastID 之后收发的私信（只发给当前连接），结束后发送 chat.synced
+func (c *Client) sync(lastID uint) {
+	messages, err := c.messageRepo.ListSince(c.userID, lastID, syncBatchSize)
+	if err != nil {
+		log.Printf("Failed to sync messages: userID=%d, err=%v", c.userID, err)
+		return
+	}
+
+	for i := range messages {
+		if !c.hub.sendToClient(c, NewEnvelope(EventChatMessage, chatPayload(&messages[i]))) {
+			return
+		}
+		lastID = messages[i].ID
+	}
+
+	c.hub.sendToClient(c, NewEnvelope(EventChatSynced, &ChatSyncedPayload{
+		LastID:  lastID,
+		HasMore: len(messages) == syncBatchSize,
+	}))
+}
+
+// handleTyping 转发输入状态给对方的全部设备，不允许发私信时同样不转发
+func (c *Client) handleTyping(env *Envelope) {
+	var p TypingPayload
+	if err := decodePayload(env, &p); err != nil {
+		c.sendError(env, ErrCodeInvalidPayload, err.Error())
+		return
+	}
+	if e := c.checkReceiver(p.ReceiverID); e != nil {
+		c.sendError(env, e.Code, e.Message)
+		return
+	}
+
+	c.hub.SendToUser(p.ReceiverID, NewEnvelope(env.Type, &TypingPayload{
+		ReceiverID: p.ReceiverID,
+		SenderID:   c.userID,
+	}))
+}
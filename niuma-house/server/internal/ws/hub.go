@@ -4,12 +4,14 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
 	"niuma-house/internal/middleware"
 	"niuma-house/internal/model"
 	"niuma-house/internal/repository"
+	"niuma-house/pkg/config"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
@@ -25,98 +27,238 @@ var upgrader = websocket.Upgrader{
 
 // Client WebSocket 客户端
 type Client struct {
-	hub      *Hub
-	conn     *websocket.Conn
-	send     chan []byte
-	userID   uint
-	username string
+	hub          *Hub
+	conn         *websocket.Conn
+	send         chan []byte
+	userID       uint
+	username     string
+	level        int    // 连接建立时的用户等级，用于放宽限流配额
+	removed      bool   // 已从 Hub 移除、send 已关闭，受 Hub.mutex 保护
+	watching     []uint // 订阅在线状态的用户，受 Hub.mutex 保护
+	messageRepo  *repository.MessageRepository
+	userRepo     *repository.UserRepository
+	blockRepo    *repository.BlockRepository
+	levelRepo    *repository.LevelRepository
+	sanctionRepo *repository.SanctionRepository
 }
 
 // Hub WebSocket 中心
+// 同一用户可以有多个连接（多标签页、多设备），消息投递到该用户的全部连接
 type Hub struct {
-	clients    map[uint]*Client
+	clients    map[uint]map[*Client]bool
 	register   chan *Client
 	unregister chan *Client
-	broadcast  chan *Message
+	broadcast  chan *delivery
+	watchers   map[uint]map[*Client]bool // 被订阅用户 -> 订阅其在线状态的本地连接
+	backend    Backend
 	mutex      sync.RWMutex
 }
 
-// Message 消息结构
-type Message struct {
-	Type       string `json:"type"` // message, notification
-	SenderID   uint   `json:"sender_id"`
-	ReceiverID uint   `json:"receiver_id"`
-	Content    string `json:"content"`
-	Timestamp  int64  `json:"timestamp"`
-}
+// 在线状态续期间隔
+const presenceRefreshInterval = 30 * time.Second
 
 var hub *Hub
 var once sync.Once
 
-// GetHub 获取 Hub 单例
+// InitHub 按配置初始化 Hub，多实例部署时使用 Redis 后端
+func InitHub(cfg *config.WSConfig) *Hub {
+	once.Do(func() {
+		hub = newHub(newBackend(cfg))
+	})
+	return hub
+}
+
+// GetHub 获取 Hub 单例，未初始化时使用单实例后端
 func GetHub() *Hub {
 	once.Do(func() {
-		hub = &Hub{
-			clients:    make(map[uint]*Client),
-			register:   make(chan *Client),
-			unregister: make(chan *Client),
-			broadcast:  make(chan *Message, 256),
-		}
-		go hub.run()
+		hub = newHub(newBackend(nil))
 	})
 	return hub
 }
 
+func newHub(backend Backend) *Hub {
+	h := &Hub{
+		clients:    make(map[uint]map[*Client]bool),
+		register:   make(chan *Client),
+		unregister: make(chan *Client),
+		broadcast:  make(chan *delivery, 256),
+		watchers:   make(map[uint]map[*Client]bool),
+		backend:    backend,
+	}
+	backend.Subscribe(h.receive)
+	go h.run()
+	go h.refreshPresence()
+	return h
+}
+
+// receive 处理后端分发过来的投递，只投递给本实例上的连接
+func (h *Hub) receive(d *delivery) {
+	if d.Disconnect {
+		h.disconnectLocal(d.UserID)
+		return
+	}
+	if d.Presence {
+		h.notifyWatchers(d.UserID)
+		return
+	}
+	if d.Unwatch != 0 {
+		h.unwatchLocal(d.UserID, d.Unwatch)
+		return
+	}
+
+	h.mutex.RLock()
+	_, ok := h.clients[d.UserID]
+	h.mutex.RUnlock()
+	if ok {
+		h.broadcast <- d
+	}
+}
+
+// refreshPresence 定期续期本实例上在线用户的状态
+func (h *Hub) refreshPresence() {
+	ticker := time.NewTicker(presenceRefreshInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		h.mutex.RLock()
+		userIDs := make([]uint, 0, len(h.clients))
+		for userID := range h.clients {
+			userIDs = append(userIDs, userID)
+		}
+		h.mutex.RUnlock()
+
+		h.backend.RefreshOnline(userIDs)
+	}
+}
+
 // run 运行 Hub
 func (h *Hub) run() {
 	for {
 		select {
 		case client := <-h.register:
 			h.mutex.Lock()
-			h.clients[client.userID] = client
+			devices, ok := h.clients[client.userID]
+			if !ok {
+				devices = make(map[*Client]bool)
+				h.clients[client.userID] = devices
+			}
+			devices[client] = true
 			h.mutex.Unlock()
-			log.Printf("WebSocket client registered: userID=%d", client.userID)
+			if !ok {
+				h.backend.SetOnline(client.userID, true)
+				go h.publishPresence(client.userID)
+			}
+			log.Printf("WebSocket client registered: userID=%d, devices=%d", client.userID, len(devices))
 
 		case client := <-h.unregister:
 			h.mutex.Lock()
-			if _, ok := h.clients[client.userID]; ok {
-				delete(h.clients, client.userID)
-				close(client.send)
-			}
+			h.removeClient(client)
 			h.mutex.Unlock()
 			log.Printf("WebSocket client unregistered: userID=%d", client.userID)
 
-		case message := <-h.broadcast:
-			h.mutex.RLock()
-			// 发送给接收者
-			if client, ok := h.clients[message.ReceiverID]; ok {
-				data, _ := json.Marshal(message)
+		case d := <-h.broadcast:
+			h.mutex.Lock()
+			for client := range h.clients[d.UserID] {
 				select {
-				case client.send <- data:
+				case client.send <- d.Data:
 				default:
-					close(client.send)
-					delete(h.clients, client.userID)
+					// 发送缓冲已满，视为连接失效
+					h.removeClient(client)
 				}
 			}
-			h.mutex.RUnlock()
+			h.mutex.Unlock()
 		}
 	}
 }
 
-// SendMessage 发送消息
-func (h *Hub) SendMessage(msg *Message) {
-	h.broadcast <- msg
+// removeClient 移除单个连接并关闭其发送通道，调用方需持有写锁
+// 只有仍在连接集合中的客户端才会被关闭，避免重复关闭
+func (h *Hub) removeClient(client *Client) {
+	devices, ok := h.clients[client.userID]
+	if !ok || !devices[client] {
+		return
+	}
+	delete(devices, client)
+	h.unwatch(client)
+	client.removed = true
+	close(client.send)
+	if len(devices) == 0 {
+		delete(h.clients, client.userID)
+		h.backend.SetOnline(client.userID, false)
+		go h.publishPresence(client.userID)
+	}
+}
+
+// sendToClient 只发送给单个连接，连接已移除或缓冲已满时返回 false
+func (h *Hub) sendToClient(client *Client, env *Envelope) bool {
+	data, err := json.Marshal(env)
+	if err != nil {
+		log.Printf("Failed to marshal message: %v", err)
+		return false
+	}
+
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	if client.removed {
+		return false
+	}
+	select {
+	case client.send <- data:
+		return true
+	default:
+		return false
+	}
+}
+
+// SendToUser 发送消息给指定用户的全部设备（跨实例）
+func (h *Hub) SendToUser(userID uint, env *Envelope) {
+	data, err := json.Marshal(env)
+	if err != nil {
+		log.Printf("Failed to marshal message: %v", err)
+		return
+	}
+	if err := h.backend.Publish(&delivery{UserID: userID, Data: data}); err != nil {
+		log.Printf("Failed to publish message: userID=%d, err=%v", userID, err)
+	}
+}
+
+// Notify 推送系统通知给用户的全部设备
+func (h *Hub) Notify(userID uint, notification *model.Notification) {
+	h.SendToUser(userID, NewEnvelope(EventNotification, notification))
 }
 
-// IsOnline 检查用户是否在线
+// IsOnline 检查用户是否在线（集群内任一设备在线即为在线）
 func (h *Hub) IsOnline(userID uint) bool {
+	return h.DeviceCount(userID) > 0 || h.backend.IsOnline(userID)
+}
+
+// DeviceCount 用户在本实例上的连接数
+func (h *Hub) DeviceCount(userID uint) int {
 	h.mutex.RLock()
 	defer h.mutex.RUnlock()
-	_, ok := h.clients[userID]
-	return ok
+	return len(h.clients[userID])
+}
+
+// Disconnect 断开用户在所有实例上的 WebSocket 连接（会话注销后调用）
+func (h *Hub) Disconnect(userID uint) {
+	if err := h.backend.Publish(&delivery{UserID: userID, Disconnect: true}); err != nil {
+		log.Printf("Failed to publish disconnect: userID=%d, err=%v", userID, err)
+		h.disconnectLocal(userID)
+	}
+}
+
+// disconnectLocal 断开用户在本实例上的全部连接
+func (h *Hub) disconnectLocal(userID uint) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	for client := range h.clients[userID] {
+		h.removeClient(client)
+	}
+	log.Printf("WebSocket client disconnected: userID=%d", userID)
 }
 
 // HandleWebSocket 处理 WebSocket 连接
+// 携带 last_id 参数时，连接建立后补发该 ID 之后的私信
 func HandleWebSocket(c *gin.Context) {
 	userID := middleware.GetCurrentUserID(c)
 	username := middleware.GetCurrentUsername(c)
@@ -126,24 +268,43 @@ func HandleWebSocket(c *gin.Context) {
 		return
 	}
 
+	lastID, syncOnConnect := c.GetQuery("last_id")
+
 	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
 		log.Printf("WebSocket upgrade failed: %v", err)
 		return
 	}
 
+	userRepo := repository.NewUserRepository()
+	level, err := userRepo.GetLevel(userID)
+	if err != nil {
+		level = 1
+	}
+
 	client := &Client{
-		hub:      GetHub(),
-		conn:     conn,
-		send:     make(chan []byte, 256),
-		userID:   userID,
-		username: username,
+		hub:          GetHub(),
+		conn:         conn,
+		send:         make(chan []byte, 256),
+		userID:       userID,
+		username:     username,
+		level:        level,
+		messageRepo:  repository.NewMessageRepository(),
+		userRepo:     userRepo,
+		blockRepo:    repository.NewBlockRepository(),
+		levelRepo:    repository.NewLevelRepository(),
+		sanctionRepo: repository.NewSanctionRepository(),
 	}
 
 	client.hub.register <- client
 
 	go client.writePump()
 	go client.readPump()
+
+	if syncOnConnect {
+		id, _ := strconv.ParseUint(lastID, 10, 64)
+		go client.sync(uint(id))
+	}
 }
 
 // readPump 读取消息
@@ -160,8 +321,6 @@ func (c *Client) readPump() {
 		return nil
 	})
 
-	messageRepo := repository.NewMessageRepository()
-
 	for {
 		_, data, err := c.conn.ReadMessage()
 		if err != nil {
@@ -171,36 +330,44 @@ func (c *Client) readPump() {
 			break
 		}
 
-		var msg Message
-		if err := json.Unmarshal(data, &msg); err != nil {
-			log.Printf("Failed to unmarshal message: %v", err)
+		var env Envelope
+		if err := json.Unmarshal(data, &env); err != nil {
+			c.sendError(&env, ErrCodeInvalidFrame, "无法解析的消息帧")
 			continue
 		}
-
-		msg.SenderID = c.userID
-		msg.Timestamp = time.Now().Unix()
-
-		// 持久化消息
-		dbMsg := &model.Message{
-			SenderID:   msg.SenderID,
-			ReceiverID: msg.ReceiverID,
-			Content:    msg.Content,
-			IsRead:     false,
-		}
-		if err := messageRepo.Create(dbMsg); err != nil {
-			log.Printf("Failed to save message: %v", err)
+		if env.V != ProtocolVersion {
+			c.sendError(&env, ErrCodeUnsupportedVersion, "不支持的协议版本")
+			continue
 		}
 
-		// 发送给接收者
-		c.hub.SendMessage(&msg)
+		if !c.allowFrame(&env) {
+			continue
+		}
 
-		// 发送回执给发送者
-		msg.Type = "sent"
-		response, _ := json.Marshal(msg)
-		c.send <- response
+		switch env.Type {
+		case EventChatSend:
+			c.handleChatSend(&env)
+		case EventChatAck:
+			c.handleChatAck(&env)
+		case EventChatSync:
+			c.handleChatSync(&env)
+		case EventTypingStart, EventTypingStop:
+			c.handleTyping(&env)
+		case EventPresenceSubscribe:
+			c.handlePresenceSubscribe(&env)
+		default:
+			c.sendError(&env, ErrCodeUnknownType, "未知的消息类型: "+env.Type)
+		}
 	}
 }
 
+// sendError 发送 error 帧给当前连接
+func (c *Client) sendError(env *Envelope, code, message string) {
+	reply := NewEnvelope(EventError, &ErrorPayload{Code: code, Message: message})
+	reply.Ref = env.Ref
+	c.hub.sendToClient(c, reply)
+}
+
 // writePump 写入消息
 func (c *Client) writePump() {
 	ticker := time.NewTicker(30 * time.Second)
@@ -0,0 +1,127 @@
+package ws
+
+import "log"
+
+// handlePresenceSubscribe 订阅一组用户的在线状态（覆盖之前的订阅），并立即推送当前状态
+// 不公开在线状态的用户不会被订阅，始终显示为离线
+func (c *Client) handlePresenceSubscribe(env *Envelope) {
+	var p PresenceSubscribePayload
+	if err := decodePayload(env, &p); err != nil {
+		c.sendError(env, ErrCodeInvalidPayload, err.Error())
+		return
+	}
+
+	visible := c.visibleUsers(p.UserIDs)
+	watching := make([]uint, 0, len(visible))
+	for _, userID := range p.UserIDs {
+		if visible[userID] {
+			watching = append(watching, userID)
+		}
+	}
+
+	c.hub.setWatching(c, watching)
+	for _, userID := range p.UserIDs {
+		c.hub.sendToClient(c, NewEnvelope(EventPresenceUpdate, &PresenceUpdatePayload{
+			UserID: userID,
+			Online: visible[userID] && c.hub.IsOnline(userID),
+		}))
+	}
+}
+
+// Unwatch 取消 watcherID 的全部连接对 userID 在线状态的订阅（如 userID 拉黑了 watcherID），广播到所有实例
+func (h *Hub) Unwatch(watcherID, userID uint) {
+	d := &delivery{UserID: watcherID, Unwatch: userID}
+	if err := h.backend.Publish(d); err != nil {
+		log.Printf("Failed to publish unwatch: watcherID=%d, userID=%d, err=%v", watcherID, userID, err)
+		h.unwatchLocal(watcherID, userID)
+	}
+}
+
+// unwatchLocal 取消 watcherID 在本实例上的连接对 userID 的订阅，并推送一次离线状态
+func (h *Hub) unwatchLocal(watcherID, userID uint) {
+	h.mutex.Lock()
+	var affected []*Client
+	for client := range h.clients[watcherID] {
+		if !h.watchers[userID][client] {
+			continue
+		}
+		delete(h.watchers[userID], client)
+		if len(h.watchers[userID]) == 0 {
+			delete(h.watchers, userID)
+		}
+		watching := client.watching[:0:0]
+		for _, id := range client.watching {
+			if id != userID {
+				watching = append(watching, id)
+			}
+		}
+		client.watching = watching
+		affected = append(affected, client)
+	}
+	h.mutex.Unlock()
+
+	env := NewEnvelope(EventPresenceUpdate, &PresenceUpdatePayload{UserID: userID, Online: false})
+	for _, client := range affected {
+		h.sendToClient(client, env)
+	}
+}
+
+// setWatching 替换连接订阅的用户列表
+func (h *Hub) setWatching(client *Client, userIDs []uint) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if client.removed {
+		return
+	}
+
+	h.unwatch(client)
+	for _, userID := range userIDs {
+		clients, ok := h.watchers[userID]
+		if !ok {
+			clients = make(map[*Client]bool)
+			h.watchers[userID] = clients
+		}
+		clients[client] = true
+	}
+	client.watching = userIDs
+}
+
+// unwatch 取消连接的全部订阅，调用方需持有写锁
+func (h *Hub) unwatch(client *Client) {
+	for _, userID := range client.watching {
+		if clients, ok := h.watchers[userID]; ok {
+			delete(clients, client)
+			if len(clients) == 0 {
+				delete(h.watchers, userID)
+			}
+		}
+	}
+	client.watching = nil
+}
+
+// publishPresence 广播用户在线状态变化，各实例通知本地的订阅者
+func (h *Hub) publishPresence(userID uint) {
+	h.backend.Publish(&delivery{UserID: userID, Presence: true})
+}
+
+// notifyWatchers 推送用户当前的在线状态给本实例上的订阅者
+// 状态以集群范围的 IsOnline 为准，避免某个实例的最后一个连接断开时误报离线
+func (h *Hub) notifyWatchers(userID uint) {
+	h.mutex.RLock()
+	clients := make([]*Client, 0, len(h.watchers[userID]))
+	for client := range h.watchers[userID] {
+		clients = append(clients, client)
+	}
+	h.mutex.RUnlock()
+	if len(clients) == 0 {
+		return
+	}
+
+	env := NewEnvelope(EventPresenceUpdate, &PresenceUpdatePayload{
+		UserID: userID,
+		Online: h.IsOnline(userID),
+	})
+	for _, client := range clients {
+		h.sendToClient(client, env)
+	}
+}
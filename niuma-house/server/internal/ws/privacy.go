@@ -0,0 +1,69 @@
+package ws
+
+import "niuma-house/internal/model"
+
+// visibleUsers 过滤出当前用户可以查看在线状态的用户：
+// 与私信规则一致，拉黑了当前用户或隐私设置不接收其私信的用户不公开在线状态
+func (c *Client) visibleUsers(userIDs []uint) map[uint]bool {
+	visible := make(map[uint]bool, len(userIDs))
+	viewer, err := c.userRepo.FindByID(c.userID)
+	if err != nil {
+		return visible
+	}
+	users, err := c.userRepo.FindByIDs(userIDs)
+	if err != nil {
+		return visible
+	}
+
+	blockers := c.blockRepo.BlockersOf(userIDs, c.userID)
+	for i := range users {
+		u := &users[i]
+		if u.ID == c.userID || (!blockers[u.ID] && u.AcceptsMessageFrom(viewer)) {
+			visible[u.ID] = true
+		}
+	}
+	return visible
+}
+
+// checkReceiver 校验当前用户能否给 receiverID 发私信，允许时返回 nil
+func (c *Client) checkReceiver(receiverID uint) *ErrorPayload {
+	if receiverID == c.userID {
+		return &ErrorPayload{Code: ErrCodeInvalidReceiver, Message: "不能给自己发私信"}
+	}
+
+	if sanction, err := c.sanctionRepo.FindActive(c.userID, model.SanctionMuteDM); err == nil {
+		message := "你已被禁止发送私信"
+		if sanction.ExpiresAt != nil {
+			message += "，解除时间 " + sanction.ExpiresAt.Format("2006-01-02 15:04")
+		}
+		return &ErrorPayload{Code: ErrCodeMuted, Message: message}
+	}
+
+	receiver, err := c.userRepo.FindByID(receiverID)
+	if err != nil {
+		return &ErrorPayload{Code: ErrCodeInvalidReceiver, Message: "用户不存在"}
+	}
+	if receiver.Status == 0 {
+		return &ErrorPayload{Code: ErrCodeInvalidReceiver, Message: "该用户已被封禁"}
+	}
+
+	if c.blockRepo.IsBlocked(receiverID, c.userID) {
+		return &ErrorPayload{Code: ErrCodeBlocked, Message: "对方已将你拉黑"}
+	}
+
+	sender, err := c.userRepo.FindByID(c.userID)
+	if err != nil {
+		return &ErrorPayload{Code: ErrCodeServerError, Message: "获取用户信息失败"}
+	}
+	if perks, err := c.levelRepo.Find(sender.Level); err == nil && !perks.CanMessage {
+		return &ErrorPayload{Code: ErrCodeLevelRequired, Message: "当前等级暂不能发送私信"}
+	}
+	if !receiver.AcceptsMessageFrom(sender) {
+		if receiver.MessagePrivacy == model.MessagePrivacyNobody {
+			return &ErrorPayload{Code: ErrCodePrivacy, Message: "对方已关闭私信"}
+		}
+		return &ErrorPayload{Code: ErrCodePrivacy, Message: "对方只接收指定等级以上用户的私信"}
+	}
+
+	return nil
+}
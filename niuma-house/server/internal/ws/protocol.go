@@ -0,0 +1,142 @@
+package ws
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+// ProtocolVersion 当前协议版本，客户端帧的 v 不一致时返回 error 帧
+const ProtocolVersion = 1
+
+// 事件类型
+const (
+	// 客户端 -> 服务端
+	EventChatSend          = "chat.send"          // 发送私信
+	EventChatAck           = "chat.ack"           // 送达/已读确认，服务端也用它把回执推送给发送方
+	EventChatSync          = "chat.sync"          // 请求补发 last_id 之后的私信
+	EventTypingStart       = "typing.start"       // 开始输入，服务端转发给对方
+	EventTypingStop        = "typing.stop"        // 停止输入
+	EventPresenceSubscribe = "presence.subscribe" // 订阅一组用户的在线状态（覆盖之前的订阅）
+
+	// 服务端 -> 客户端
+	EventChatMessage    = "chat.message"    // 新私信
+	EventChatSent       = "chat.sent"       // 发送回执，携带私信 ID，推送给发送者的全部设备
+	EventChatSynced     = "chat.synced"     // 补发完成
+	EventPresenceUpdate = "presence.update" // 订阅用户的在线状态
+	EventNotification   = "notification"    // 系统通知（点赞、评论、升级等）
+	EventError          = "error"           // 错误
+)
+
+// 确认状态
+const (
+	AckDelivered = "delivered"
+	AckRead      = "read"
+)
+
+// 错误码
+const (
+	ErrCodeInvalidFrame       = "invalid_frame"
+	ErrCodeUnsupportedVersion = "unsupported_version"
+	ErrCodeUnknownType        = "unknown_type"
+	ErrCodeInvalidPayload     = "invalid_payload"
+	ErrCodeInvalidReceiver    = "invalid_receiver" // 接收者不存在、已封禁或为自己
+	ErrCodeBlocked            = "blocked"          // 被接收者拉黑
+	ErrCodePrivacy            = "privacy"          // 接收者的隐私设置不允许
+	ErrCodeRateLimited        = "rate_limited"     // 发送过于频繁，retry_after 秒后重试
+	ErrCodeLevelRequired      = "level_required"   // 发送者当前等级没有私信权限
+	ErrCodeMuted              = "muted"            // 发送者被禁止发送私信
+	ErrCodeContentRejected    = "content_rejected" // 私信包含不允许发送的敏感词或个人信息
+	ErrCodeServerError        = "server_error"
+)
+
+// Envelope 协议信封
+type Envelope struct {
+	V         int             `json:"v"`
+	Type      string          `json:"type"`
+	Ref       string          `json:"ref,omitempty"` // 客户端帧标识，error 帧原样带回
+	Payload   json.RawMessage `json:"payload,omitempty"`
+	Timestamp int64           `json:"ts"`
+}
+
+// NewEnvelope 创建服务端推送帧
+func NewEnvelope(eventType string, payload interface{}) *Envelope {
+	env := &Envelope{V: ProtocolVersion, Type: eventType, Timestamp: time.Now().Unix()}
+	if payload != nil {
+		env.Payload, _ = json.Marshal(payload)
+	}
+	return env
+}
+
+// ChatSendPayload chat.send
+type ChatSendPayload struct {
+	ReceiverID  uint   `json:"receiver_id" binding:"required"`
+	Content     string `json:"content" binding:"required,max=2000"`
+	ClientMsgID string `json:"client_msg_id" binding:"max=64"` // 客户端生成的去重键
+}
+
+// ChatAckPayload chat.ack
+// 客户端上报时只需 message_id 和 status；read 表示该 ID 及之前来自同一发送者的私信都已读
+type ChatAckPayload struct {
+	MessageID  uint   `json:"message_id" binding:"required"`
+	Status     string `json:"status" binding:"required,oneof=delivered read"`
+	SenderID   uint   `json:"sender_id,omitempty"`
+	ReceiverID uint   `json:"receiver_id,omitempty"`
+}
+
+// ChatSyncPayload chat.sync
+type ChatSyncPayload struct {
+	LastID uint `json:"last_id"`
+}
+
+// ChatSyncedPayload chat.synced
+type ChatSyncedPayload struct {
+	LastID  uint `json:"last_id"`
+	HasMore bool `json:"has_more"` // 为 true 时客户端应以 last_id 继续请求 chat.sync
+}
+
+// ChatMessagePayload chat.message / chat.sent
+type ChatMessagePayload struct {
+	ID          uint   `json:"id"`
+	ClientMsgID string `json:"client_msg_id,omitempty"`
+	SenderID    uint   `json:"sender_id"`
+	ReceiverID  uint   `json:"receiver_id"`
+	Content     string `json:"content"`
+	CreatedAt   int64  `json:"created_at"`
+}
+
+// TypingPayload typing.start / typing.stop
+// 客户端上报 receiver_id，服务端转发时填入 sender_id
+type TypingPayload struct {
+	ReceiverID uint `json:"receiver_id" binding:"required"`
+	SenderID   uint `json:"sender_id,omitempty"`
+}
+
+// PresenceSubscribePayload presence.subscribe
+type PresenceSubscribePayload struct {
+	UserIDs []uint `json:"user_ids" binding:"max=200"`
+}
+
+// PresenceUpdatePayload presence.update
+type PresenceUpdatePayload struct {
+	UserID uint `json:"user_id"`
+	Online bool `json:"online"`
+}
+
+// ErrorPayload error
+type ErrorPayload struct {
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+	RetryAfter int    `json:"retry_after,omitempty"` // rate_limited 时建议的等待秒数
+}
+
+// decodePayload 解析并校验负载
+func decodePayload(env *Envelope, v interface{}) error {
+	if len(env.Payload) > 0 {
+		if err := json.Unmarshal(env.Payload, v); err != nil {
+			return err
+		}
+	}
+	return binding.Validator.ValidateStruct(v)
+}
@@ -0,0 +1,48 @@
+package ws
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"niuma-house/pkg/ratelimit"
+)
+
+// 消息帧限流规则，Limit 为 Lv.1 用户的配额，按等级放宽
+// 同一用户的多个连接共享配额
+var (
+	rateChatRule   = ratelimit.Rule{Name: "ws:chat", Limit: 20, Window: 10 * time.Second}
+	rateTypingRule = ratelimit.Rule{Name: "ws:typing", Limit: 30, Window: 10 * time.Second}
+	rateFrameRule  = ratelimit.Rule{Name: "ws:frame", Limit: 60, Window: 10 * time.Second}
+)
+
+// frameRules 消息类型对应的限流规则，未列出的类型使用 rateFrameRule
+var frameRules = map[string]ratelimit.Rule{
+	EventChatSend:    rateChatRule,
+	EventTypingStart: rateTypingRule,
+	EventTypingStop:  rateTypingRule,
+}
+
+// allowFrame 检查当前用户是否超出该类型消息帧的配额，超出时回复 error 帧
+func (c *Client) allowFrame(env *Envelope) bool {
+	rule, ok := frameRules[env.Type]
+	if !ok {
+		rule = rateFrameRule
+	}
+
+	key := fmt.Sprintf("%s:u:%d", rule.Name, c.userID)
+	res := ratelimit.Allow(context.Background(), key, ratelimit.ScaleByLevel(rule.Limit, c.level), rule.Window)
+	if res.Allowed {
+		return true
+	}
+
+	reply := NewEnvelope(EventError, &ErrorPayload{
+		Code:       ErrCodeRateLimited,
+		Message:    "发送过于频繁，请稍后再试",
+		RetryAfter: int(math.Ceil(res.RetryAfter.Seconds())),
+	})
+	reply.Ref = env.Ref
+	c.hub.sendToClient(c, reply)
+	return false
+}
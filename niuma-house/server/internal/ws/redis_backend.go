@@ -0,0 +1,106 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis 分发频道
+const wsDeliverChannel = "ws:deliver"
+
+// 在线状态有效期，实例需在此期间内续期，宕机实例的在线状态会自动过期
+const presenceTTL = 90 * time.Second
+
+// redisBackend 基于 Redis Pub/Sub 的多实例后端
+// 在线状态存储在 ws:presence:<userID> 有序集合中，成员为实例 ID，分值为过期时间戳
+type redisBackend struct {
+	rdb        *redis.Client
+	instanceID string
+}
+
+func newRedisBackend(rdb *redis.Client) *redisBackend {
+	return &redisBackend{rdb: rdb, instanceID: uuid.NewString()}
+}
+
+func presenceKey(userID uint) string {
+	return fmt.Sprintf("ws:presence:%d", userID)
+}
+
+func (b *redisBackend) Publish(d *delivery) error {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	return b.rdb.Publish(context.Background(), wsDeliverChannel, data).Err()
+}
+
+func (b *redisBackend) Subscribe(handler func(d *delivery)) {
+	// go-redis 会在连接断开后自动重新订阅
+	pubsub := b.rdb.Subscribe(context.Background(), wsDeliverChannel)
+	go func() {
+		for msg := range pubsub.Channel() {
+			var d delivery
+			if err := json.Unmarshal([]byte(msg.Payload), &d); err != nil {
+				log.Printf("Failed to unmarshal ws delivery: %v", err)
+				continue
+			}
+			handler(&d)
+		}
+	}()
+}
+
+func (b *redisBackend) SetOnline(userID uint, online bool) {
+	ctx := context.Background()
+	key := presenceKey(userID)
+
+	var err error
+	if online {
+		_, err = b.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.ZAdd(ctx, key, redis.Z{Score: b.expireAt(), Member: b.instanceID})
+			pipe.Expire(ctx, key, presenceTTL)
+			return nil
+		})
+	} else {
+		err = b.rdb.ZRem(ctx, key, b.instanceID).Err()
+	}
+	if err != nil {
+		log.Printf("Failed to update presence: userID=%d, err=%v", userID, err)
+	}
+}
+
+func (b *redisBackend) RefreshOnline(userIDs []uint) {
+	if len(userIDs) == 0 {
+		return
+	}
+
+	ctx := context.Background()
+	expireAt := b.expireAt()
+	_, err := b.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, userID := range userIDs {
+			key := presenceKey(userID)
+			pipe.ZAdd(ctx, key, redis.Z{Score: expireAt, Member: b.instanceID})
+			pipe.Expire(ctx, key, presenceTTL)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Failed to refresh presence: %v", err)
+	}
+}
+
+func (b *redisBackend) IsOnline(userID uint) bool {
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	count, err := b.rdb.ZCount(context.Background(), presenceKey(userID), "("+now, "+inf").Result()
+	return err == nil && count > 0
+}
+
+func (b *redisBackend) expireAt() float64 {
+	return float64(time.Now().Add(presenceTTL).Unix())
+}
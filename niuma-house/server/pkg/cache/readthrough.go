@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Stats 缓存命中统计
+type Stats struct {
+	Name   string  `json:"name"`
+	Hits   int64   `json:"hits"`
+	Misses int64   `json:"misses"`
+	Ratio  float64 `json:"ratio"` // 命中率
+}
+
+type counter struct {
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+var counters sync.Map // name -> *counter
+
+func getCounter(name string) *counter {
+	c, _ := counters.LoadOrStore(name, &counter{})
+	return c.(*counter)
+}
+
+// Remember 读穿缓存：命中时直接返回缓存值，未命中时调用 loader 并写回缓存
+// name 用于命中统计分组；Redis 异常时降级为直接调用 loader
+func Remember[T any](ctx context.Context, name, key string, ttl time.Duration, loader func() (T, error)) (T, error) {
+	c := getCounter(name)
+
+	var value T
+	data, err := GetRedis().Get(ctx, key).Bytes()
+	if err == nil {
+		if err := json.Unmarshal(data, &value); err == nil {
+			c.hits.Add(1)
+			return value, nil
+		}
+	} else if !errors.Is(err, redis.Nil) {
+		log.Printf("Cache get failed: key=%s, err=%v", key, err)
+	}
+
+	c.misses.Add(1)
+	value, err = loader()
+	if err != nil {
+		return value, err
+	}
+
+	if data, err := json.Marshal(value); err == nil {
+		if err := GetRedis().Set(ctx, key, data, ttl).Err(); err != nil {
+			log.Printf("Cache set failed: key=%s, err=%v", key, err)
+		}
+	}
+	return value, nil
+}
+
+// Invalidate 删除缓存
+func Invalidate(ctx context.Context, keys ...string) {
+	if len(keys) == 0 {
+		return
+	}
+	if err := GetRedis().Del(ctx, keys...).Err(); err != nil {
+		log.Printf("Cache invalidate failed: keys=%v, err=%v", keys, err)
+	}
+}
+
+// GetStats 获取各分组的缓存命中统计
+func GetStats() []Stats {
+	var list []Stats
+	counters.Range(func(key, value interface{}) bool {
+		c := value.(*counter)
+		s := Stats{
+			Name:   key.(string),
+			Hits:   c.hits.Load(),
+			Misses: c.misses.Load(),
+		}
+		if total := s.Hits + s.Misses; total > 0 {
+			s.Ratio = float64(s.Hits) / float64(total)
+		}
+		list = append(list, s)
+		return true
+	})
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
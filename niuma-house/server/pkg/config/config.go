@@ -9,13 +9,18 @@ import (
 )
 
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	MySQL    MySQLConfig    `mapstructure:"mysql"`
-	Redis    RedisConfig    `mapstructure:"redis"`
-	MinIO    MinIOConfig    `mapstructure:"minio"`
-	RabbitMQ RabbitMQConfig `mapstructure:"rabbitmq"`
-	JWT      JWTConfig      `mapstructure:"jwt"`
-	Casbin   CasbinConfig   `mapstructure:"casbin"`
+	Server        ServerConfig        `mapstructure:"server"`
+	MySQL         MySQLConfig         `mapstructure:"mysql"`
+	Redis         RedisConfig         `mapstructure:"redis"`
+	MinIO         MinIOConfig         `mapstructure:"minio"`
+	RabbitMQ      RabbitMQConfig      `mapstructure:"rabbitmq"`
+	JWT           JWTConfig           `mapstructure:"jwt"`
+	Casbin        CasbinConfig        `mapstructure:"casbin"`
+	WS            WSConfig            `mapstructure:"ws"`
+	Queue         QueueConfig         `mapstructure:"queue"`
+	Anonymous     AnonymousConfig     `mapstructure:"anonymous"`
+	Moderation    ModerationConfig    `mapstructure:"moderation"`
+	ContentFilter ContentFilterConfig `mapstructure:"content_filter"`
 }
 
 type ServerConfig struct {
@@ -59,8 +64,9 @@ type RabbitMQConfig struct {
 }
 
 type JWTConfig struct {
-	Secret      string `mapstructure:"secret"`
-	ExpireHours int    `mapstructure:"expire_hours"`
+	Secret              string `mapstructure:"secret"`
+	AccessExpireMinutes int    `mapstructure:"access_expire_minutes"`
+	RefreshExpireHours  int    `mapstructure:"refresh_expire_hours"`
 }
 
 type CasbinConfig struct {
@@ -68,6 +74,28 @@ type CasbinConfig struct {
 	PolicyPath string `mapstructure:"policy_path"`
 }
 
+type WSConfig struct {
+	Backend string `mapstructure:"backend"` // local: 单实例, redis: 多实例
+}
+
+type QueueConfig struct {
+	Backend string `mapstructure:"backend"` // rabbitmq: RabbitMQ（默认）, memory: 进程内队列
+}
+
+type AnonymousConfig struct {
+	Secret string `mapstructure:"secret"` // 生成匿名化名的密钥，为空时使用 JWT 密钥；更换后所有化名都会改变
+}
+
+type ModerationConfig struct {
+	AutoHideThreshold int `mapstructure:"auto_hide_threshold"` // 同一内容被不同用户举报达到该次数后自动隐藏，0 表示不自动隐藏
+}
+
+type ContentFilterConfig struct {
+	Phone    string `mapstructure:"phone"`     // 手机号的处理方式：mask 打码，review 隐藏待审核，reject 拒绝发布，留空不检测
+	IDCard   string `mapstructure:"id_card"`   // 身份证号的处理方式，同上
+	BankCard string `mapstructure:"bank_card"` // 银行卡号的处理方式，同上
+}
+
 var (
 	cfg  *Config
 	once sync.Once
@@ -0,0 +1,124 @@
+package filter
+
+import "unicode"
+
+// Match 一次命中，Start / End 为 rune 下标，区间 [Start, End)
+type Match struct {
+	Pattern int // 命中的模式在构建时的下标
+	Start   int
+	End     int
+}
+
+// Matcher Aho-Corasick 多模式匹配器，忽略大小写；构建后只读，可并发使用
+type Matcher struct {
+	nodes    []acNode
+	patterns [][]rune
+}
+
+type acNode struct {
+	next    map[rune]int
+	fail    int
+	pattern int // 以该节点结尾的模式下标，-1 表示无
+	output  int // fail 链上最近一个有模式结尾的节点，-1 表示无
+}
+
+// NewMatcher 构建匹配器，空模式会被忽略
+func NewMatcher(patterns []string) *Matcher {
+	m := &Matcher{
+		nodes:    []acNode{{next: map[rune]int{}, pattern: -1, output: -1}},
+		patterns: make([][]rune, len(patterns)),
+	}
+
+	// 构建字典树
+	for i, p := range patterns {
+		runes := normalizeRunes([]rune(p))
+		m.patterns[i] = runes
+		if len(runes) == 0 {
+			continue
+		}
+		cur := 0
+		for _, r := range runes {
+			nxt, ok := m.nodes[cur].next[r]
+			if !ok {
+				nxt = len(m.nodes)
+				m.nodes = append(m.nodes, acNode{next: map[rune]int{}, pattern: -1, output: -1})
+				m.nodes[cur].next[r] = nxt
+			}
+			cur = nxt
+		}
+		if m.nodes[cur].pattern < 0 {
+			m.nodes[cur].pattern = i
+		}
+	}
+
+	// 按层序计算失配指针和输出链
+	queue := make([]int, 0, len(m.nodes))
+	for _, child := range m.nodes[0].next {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for r, child := range m.nodes[cur].next {
+			fail := m.nodes[cur].fail
+			for fail > 0 {
+				if _, ok := m.nodes[fail].next[r]; ok {
+					break
+				}
+				fail = m.nodes[fail].fail
+			}
+			if nxt, ok := m.nodes[fail].next[r]; ok && nxt != child {
+				m.nodes[child].fail = nxt
+			}
+			f := m.nodes[child].fail
+			if m.nodes[f].pattern >= 0 {
+				m.nodes[child].output = f
+			} else {
+				m.nodes[child].output = m.nodes[f].output
+			}
+			queue = append(queue, child)
+		}
+	}
+	return m
+}
+
+// FindAll 查找 text 中所有模式的出现位置（可重叠），按结束位置排序
+func (m *Matcher) FindAll(text []rune) []Match {
+	var matches []Match
+	cur := 0
+	for i, r := range normalizeRunes(text) {
+		for cur > 0 {
+			if _, ok := m.nodes[cur].next[r]; ok {
+				break
+			}
+			cur = m.nodes[cur].fail
+		}
+		if nxt, ok := m.nodes[cur].next[r]; ok {
+			cur = nxt
+		}
+
+		for n := cur; n >= 0; n = m.nodes[n].output {
+			if p := m.nodes[n].pattern; p >= 0 {
+				matches = append(matches, Match{Pattern: p, Start: i + 1 - len(m.patterns[p]), End: i + 1})
+			}
+			if n == 0 {
+				break
+			}
+		}
+	}
+	return matches
+}
+
+// normalizeRunes 统一为小写并将全角字符转为半角，返回新切片
+func normalizeRunes(runes []rune) []rune {
+	out := make([]rune, len(runes))
+	for i, r := range runes {
+		if r == 0x3000 {
+			r = ' '
+		} else if r >= 0xFF01 && r <= 0xFF5E {
+			r -= 0xFEE0
+		}
+		out[i] = unicode.ToLower(r)
+	}
+	return out
+}
@@ -0,0 +1,96 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatcherFindAll(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		text     string
+		want     []Match
+	}{
+		{
+			name:     "经典用例，沿输出链报告所有模式",
+			patterns: []string{"he", "she", "his", "hers"},
+			text:     "ushers",
+			want:     []Match{{Pattern: 1, Start: 1, End: 4}, {Pattern: 0, Start: 2, End: 4}, {Pattern: 3, Start: 2, End: 6}},
+		},
+		{
+			name:     "失配后沿失配指针继续匹配",
+			patterns: []string{"abcd", "bce"},
+			text:     "abce",
+			want:     []Match{{Pattern: 1, Start: 1, End: 4}},
+		},
+		{
+			name:     "同一模式重叠出现",
+			patterns: []string{"aa"},
+			text:     "aaa",
+			want:     []Match{{Pattern: 0, Start: 0, End: 2}, {Pattern: 0, Start: 1, End: 3}},
+		},
+		{
+			name:     "模式是另一模式的后缀",
+			patterns: []string{"微信号", "信号"},
+			text:     "加微信号",
+			want:     []Match{{Pattern: 0, Start: 1, End: 4}, {Pattern: 1, Start: 2, End: 4}},
+		},
+		{
+			name:     "忽略大小写和全半角",
+			patterns: []string{"VX"},
+			text:     "加ｖｘ",
+			want:     []Match{{Pattern: 0, Start: 1, End: 3}},
+		},
+		{
+			name:     "重复模式只报告第一个",
+			patterns: []string{"ab", "AB"},
+			text:     "ab",
+			want:     []Match{{Pattern: 0, Start: 0, End: 2}},
+		},
+		{
+			name:     "空模式被忽略",
+			patterns: []string{"", "a"},
+			text:     "ba",
+			want:     []Match{{Pattern: 1, Start: 1, End: 2}},
+		},
+		{
+			name:     "无命中",
+			patterns: []string{"abc"},
+			text:     "ababab",
+			want:     nil,
+		},
+		{
+			name:     "空词库",
+			patterns: nil,
+			text:     "abc",
+			want:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewMatcher(tt.patterns).FindAll([]rune(tt.text))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindAll(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeRunes(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"ABC", "abc"},
+		{"ＡＢＣ１２３", "abc123"},
+		{"微　信", "微 信"},
+		{"！", "!"},
+		{"中文", "中文"},
+	}
+	for _, tt := range tests {
+		if got := string(normalizeRunes([]rune(tt.in))); got != tt.want {
+			t.Errorf("normalizeRunes(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package filter
+
+import (
+	"log"
+
+	"niuma-house/pkg/config"
+)
+
+// Action 命中后的处理方式，数值越大越严重
+type Action int
+
+const (
+	ActionPass   Action = iota // 放行
+	ActionMask                 // 打码后放行
+	ActionReview               // 发布后隐藏，等待人工审核
+	ActionReject               // 拒绝发布
+)
+
+var actionNames = []string{"pass", "mask", "review", "reject"}
+
+// ParseAction 解析配置或词库中的处理方式，无法识别时返回 ActionPass
+func ParseAction(s string) Action {
+	for i, name := range actionNames {
+		if name == s {
+			return Action(i)
+		}
+	}
+	return ActionPass
+}
+
+// String 处理方式名称
+func (a Action) String() string {
+	if a < 0 || int(a) >= len(actionNames) {
+		return actionNames[ActionPass]
+	}
+	return actionNames[a]
+}
+
+// MarshalText 序列化为名称
+func (a Action) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// Hit 一次命中
+type Hit struct {
+	Category string `json:"category"` // 敏感词分类，或 phone / id_card / bank_card
+	Text     string `json:"text"`     // 命中的敏感词，个人信息为打码后的号码
+	Action   Action `json:"action"`
+}
+
+// Result 过滤结果
+type Result struct {
+	Text   string // 处理后的文本，需打码的片段已替换
+	Action Action // 所有命中中最严重的处理方式
+	Hits   []Hit
+}
+
+// categoryLabels 个人信息分类名称，敏感词统一显示为"敏感词"，不暴露具体分类
+var categoryLabels = map[string]string{
+	CategoryPhone:    "手机号",
+	CategoryIDCard:   "身份证号",
+	CategoryBankCard: "银行卡号",
+}
+
+// Labels 处理方式不低于 action 的命中的分类名称，去重，用于提示用户
+func (r *Result) Labels(action Action) []string {
+	var labels []string
+	seen := make(map[string]bool)
+	for _, hit := range r.Hits {
+		if hit.Action < action {
+			continue
+		}
+		label, ok := categoryLabels[hit.Category]
+		if !ok {
+			label = "敏感词"
+		}
+		if !seen[label] {
+			seen[label] = true
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+// Filter 内容过滤器，返回处理后的文本和命中记录
+type Filter interface {
+	Apply(text string) (string, []Hit)
+}
+
+// Chain 过滤链，依次执行各过滤器，后一个过滤器处理前一个打码后的文本
+type Chain struct {
+	filters []Filter
+}
+
+// NewChain 创建过滤链
+func NewChain(filters ...Filter) *Chain {
+	return &Chain{filters: filters}
+}
+
+// Run 执行过滤链
+func (c *Chain) Run(text string) *Result {
+	result := &Result{Text: text}
+	for _, f := range c.filters {
+		var hits []Hit
+		result.Text, hits = f.Apply(result.Text)
+		for _, hit := range hits {
+			if hit.Action > result.Action {
+				result.Action = hit.Action
+			}
+		}
+		result.Hits = append(result.Hits, hits...)
+	}
+	return result
+}
+
+var (
+	chain = NewChain(words)
+	words = NewWordFilter()
+)
+
+// Init 按配置组装全局过滤链：敏感词 → 身份证号 → 银行卡号 → 手机号
+func Init(cfg *config.ContentFilterConfig) {
+	chain = NewChain(
+		words,
+		IDCardDetector(ParseAction(cfg.IDCard)),
+		BankCardDetector(ParseAction(cfg.BankCard)),
+		PhoneDetector(ParseAction(cfg.Phone)),
+	)
+	log.Printf("Content filter initialized: phone=%s, id_card=%s, bank_card=%s", cfg.Phone, cfg.IDCard, cfg.BankCard)
+}
+
+// Get 获取全局过滤链，未初始化时只包含敏感词过滤
+func Get() *Chain {
+	return chain
+}
+
+// Words 全局敏感词过滤器，词库由调用方加载
+func Words() *WordFilter {
+	return words
+}
@@ -0,0 +1,65 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChainRun(t *testing.T) {
+	words := NewWordFilter()
+	words.SetWords([]Word{
+		{Text: "微信", Category: "ad", Action: ActionMask},
+		{Text: "代开发票", Category: "fraud", Action: ActionReview},
+	})
+	chain := NewChain(
+		words,
+		IDCardDetector(ActionReview),
+		BankCardDetector(ActionMask),
+		PhoneDetector(ActionMask),
+	)
+
+	result := chain.Run("加微信 13812345678，代开发票，身份证 320106200002294562")
+
+	wantText := "加** 138****5678，代开发票，身份证 320106200002294562"
+	if result.Text != wantText {
+		t.Errorf("Text = %q, want %q", result.Text, wantText)
+	}
+	if result.Action != ActionReview {
+		t.Errorf("Action = %v, want %v", result.Action, ActionReview)
+	}
+	wantHits := []Hit{
+		{Category: "ad", Text: "微信", Action: ActionMask},
+		{Category: "fraud", Text: "代开发票", Action: ActionReview},
+		{Category: CategoryIDCard, Text: "3201************62", Action: ActionReview},
+		{Category: CategoryPhone, Text: "138****5678", Action: ActionMask},
+	}
+	if !reflect.DeepEqual(result.Hits, wantHits) {
+		t.Errorf("Hits = %v, want %v", result.Hits, wantHits)
+	}
+
+	if got, want := result.Labels(ActionReview), []string{"敏感词", "身份证号"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Labels(review) = %v, want %v", got, want)
+	}
+	if got, want := result.Labels(ActionMask), []string{"敏感词", "身份证号", "手机号"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Labels(mask) = %v, want %v", got, want)
+	}
+}
+
+func TestParseAction(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Action
+	}{
+		{"pass", ActionPass},
+		{"mask", ActionMask},
+		{"review", ActionReview},
+		{"reject", ActionReject},
+		{"", ActionPass},
+		{"unknown", ActionPass},
+	}
+	for _, tt := range tests {
+		if got := ParseAction(tt.in); got != tt.want {
+			t.Errorf("ParseAction(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
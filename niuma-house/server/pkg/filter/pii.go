@@ -0,0 +1,161 @@
+package filter
+
+import (
+	"regexp"
+	"strings"
+)
+
+// 个人信息分类
+const (
+	CategoryPhone    = "phone"
+	CategoryIDCard   = "id_card"
+	CategoryBankCard = "bank_card"
+)
+
+// digitRunPattern 连续的数字串，允许以空格或连字符分隔（如 138 1234 5678），身份证末位可为 X；
+// 同时识别全角数字、空格和连字符（如 １３８－１２３４－５６７８）
+var digitRunPattern = regexp.MustCompile(`[0-9０-９][0-9０-９ 　\-－]*[0-9０-９XxＸｘ]`)
+
+// 身份证号校验位计算参数（GB 11643）
+var (
+	idCardWeights = []int{7, 9, 10, 5, 8, 4, 2, 1, 6, 3, 7, 9, 10, 5, 8, 4, 2}
+	idCardChecks  = "10X98765432"
+)
+
+// Detector 个人信息检测器，识别文本中的数字串
+type Detector struct {
+	category string
+	action   Action
+	match    func(digits string) bool
+	keepHead int // 打码时保留的前几位
+	keepTail int // 打码时保留的后几位
+}
+
+// PhoneDetector 中国大陆手机号：11 位，1 开头，第二位为 3-9
+func PhoneDetector(action Action) *Detector {
+	return &Detector{category: CategoryPhone, action: action, match: isPhone, keepHead: 3, keepTail: 4}
+}
+
+// IDCardDetector 18 位居民身份证号，校验出生日期格式和校验位
+func IDCardDetector(action Action) *Detector {
+	return &Detector{category: CategoryIDCard, action: action, match: isIDCard, keepHead: 4, keepTail: 2}
+}
+
+// BankCardDetector 16-19 位银行卡号，通过 Luhn 校验且不是身份证号
+func BankCardDetector(action Action) *Detector {
+	return &Detector{category: CategoryBankCard, action: action, match: isBankCard, keepHead: 4, keepTail: 4}
+}
+
+// Apply 查找个人信息，Action 为 ActionMask 时打码；命中记录中的文本同样是打码后的，避免扩散原文
+func (d *Detector) Apply(text string) (string, []Hit) {
+	if d.action == ActionPass {
+		return text, nil
+	}
+
+	var hits []Hit
+	result := digitRunPattern.ReplaceAllStringFunc(text, func(original string) string {
+		run := halfWidthDigits(original)
+
+		// 整串不匹配时按分隔符拆开逐段识别，避免相邻的两个号码被当成一串
+		candidates := []string{run}
+		if digits := stripSeparators(run); !d.match(digits) {
+			candidates = strings.FieldsFunc(run, func(r rune) bool { return r == ' ' || r == '-' })
+		}
+
+		// 打码后的数字串统一为半角，未命中时保留原文
+		replaced := run
+		matched := false
+		for _, candidate := range candidates {
+			digits := stripSeparators(candidate)
+			if !d.match(digits) {
+				continue
+			}
+			masked := maskMiddle(digits, d.keepHead, d.keepTail)
+			hits = append(hits, Hit{Category: d.category, Text: masked, Action: d.action})
+			if d.action == ActionMask {
+				replaced = strings.Replace(replaced, candidate, masked, 1)
+				matched = true
+			}
+		}
+		if !matched {
+			return original
+		}
+		return replaced
+	})
+	return result, hits
+}
+
+func isPhone(digits string) bool {
+	return len(digits) == 11 && digits[0] == '1' && digits[1] >= '3' && digits[1] <= '9' && isDigits(digits)
+}
+
+func isIDCard(digits string) bool {
+	if len(digits) != 18 || !isDigits(digits[:17]) {
+		return false
+	}
+	// 出生日期：19xx / 20xx 年，月 01-12，日 01-31
+	year, month, day := digits[6:8], digits[10:12], digits[12:14]
+	if (year != "19" && year != "20") || month < "01" || month > "12" || day < "01" || day > "31" {
+		return false
+	}
+
+	sum := 0
+	for i, w := range idCardWeights {
+		sum += int(digits[i]-'0') * w
+	}
+	return strings.ToUpper(digits[17:]) == string(idCardChecks[sum%11])
+}
+
+func isBankCard(digits string) bool {
+	if len(digits) < 16 || len(digits) > 19 || !isDigits(digits) || isIDCard(digits) {
+		return false
+	}
+
+	// Luhn 校验：从右往左，偶数位乘 2
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		n := int(digits[i] - '0')
+		if (len(digits)-1-i)%2 == 1 {
+			n *= 2
+			if n > 9 {
+				n -= 9
+			}
+		}
+		sum += n
+	}
+	return sum%10 == 0
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// halfWidthDigits 将全角数字、X、空格和连字符转为半角，其余字符不变
+func halfWidthDigits(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= '０' && r <= '９', r == 'Ｘ', r == 'ｘ', r == '－':
+			return r - 0xFEE0
+		case r == '　':
+			return ' '
+		}
+		return r
+	}, s)
+}
+
+func stripSeparators(s string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(s)
+}
+
+// maskMiddle 保留前 head 位和后 tail 位，其余替换为 *
+func maskMiddle(s string, head, tail int) string {
+	if len(s) <= head+tail {
+		return strings.Repeat("*", len(s))
+	}
+	return s[:head] + strings.Repeat("*", len(s)-head-tail) + s[len(s)-tail:]
+}
@@ -0,0 +1,194 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIsIDCard(t *testing.T) {
+	tests := []struct {
+		digits string
+		want   bool
+	}{
+		{"11010519491231002X", true},
+		{"11010519491231002x", true},
+		{"44030419900307123X", true},
+		{"320106200002294562", true},
+		{"110105194912310021", false}, // 校验位错误
+		{"110105184912310025", false}, // 出生年份不是 19xx / 20xx
+		{"110105194913310026", false}, // 月份超出范围
+		{"110105194912320028", false}, // 日期超出范围
+		{"1101051949123100X2", false}, // X 不在末位
+		{"11010519491231002", false},  // 长度不足
+	}
+	for _, tt := range tests {
+		if got := isIDCard(tt.digits); got != tt.want {
+			t.Errorf("isIDCard(%q) = %v, want %v", tt.digits, got, tt.want)
+		}
+	}
+}
+
+func TestIsBankCard(t *testing.T) {
+	tests := []struct {
+		digits string
+		want   bool
+	}{
+		{"6222021234567890128", true},
+		{"6217001234567893", true},
+		{"4111111111111111", true},
+		{"6222021234567890127", false},  // Luhn 校验失败
+		{"4111111111111", false},        // 长度不足 16 位
+		{"41111111111111111111", false}, // 超过 19 位
+		{"320106200002294562", false},   // 身份证号不算银行卡
+	}
+	for _, tt := range tests {
+		if got := isBankCard(tt.digits); got != tt.want {
+			t.Errorf("isBankCard(%q) = %v, want %v", tt.digits, got, tt.want)
+		}
+	}
+}
+
+func TestIsPhone(t *testing.T) {
+	tests := []struct {
+		digits string
+		want   bool
+	}{
+		{"13812345678", true},
+		{"19912345678", true},
+		{"12812345678", false}, // 第二位不在 3-9
+		{"23812345678", false},
+		{"1381234567", false},
+		{"138123456789", false},
+	}
+	for _, tt := range tests {
+		if got := isPhone(tt.digits); got != tt.want {
+			t.Errorf("isPhone(%q) = %v, want %v", tt.digits, got, tt.want)
+		}
+	}
+}
+
+func TestMaskMiddle(t *testing.T) {
+	tests := []struct {
+		s          string
+		head, tail int
+		want       string
+	}{
+		{"13812345678", 3, 4, "138****5678"},
+		{"11010519491231002X", 4, 2, "1101************2X"},
+		{"6222021234567890128", 4, 4, "6222***********0128"},
+		{"1234567", 3, 4, "*******"},
+		{"123", 3, 4, "***"},
+	}
+	for _, tt := range tests {
+		if got := maskMiddle(tt.s, tt.head, tt.tail); got != tt.want {
+			t.Errorf("maskMiddle(%q, %d, %d) = %q, want %q", tt.s, tt.head, tt.tail, got, tt.want)
+		}
+	}
+}
+
+func TestDetectorApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		detector *Detector
+		text     string
+		wantText string
+		wantHits []Hit
+	}{
+		{
+			name:     "手机号打码",
+			detector: PhoneDetector(ActionMask),
+			text:     "电话13812345678",
+			wantText: "电话138****5678",
+			wantHits: []Hit{{Category: CategoryPhone, Text: "138****5678", Action: ActionMask}},
+		},
+		{
+			name:     "带分隔符的手机号",
+			detector: PhoneDetector(ActionMask),
+			text:     "电话 138-1234-5678 找我",
+			wantText: "电话 138****5678 找我",
+			wantHits: []Hit{{Category: CategoryPhone, Text: "138****5678", Action: ActionMask}},
+		},
+		{
+			name:     "全角数字的手机号",
+			detector: PhoneDetector(ActionMask),
+			text:     "电话１３８１２３４５６７８哦",
+			wantText: "电话138****5678哦",
+			wantHits: []Hit{{Category: CategoryPhone, Text: "138****5678", Action: ActionMask}},
+		},
+		{
+			name:     "全角空格和连字符分隔的手机号",
+			detector: PhoneDetector(ActionMask),
+			text:     "１３８－１２３４　５６７８",
+			wantText: "138****5678",
+			wantHits: []Hit{{Category: CategoryPhone, Text: "138****5678", Action: ActionMask}},
+		},
+		{
+			name:     "整串不匹配时拆开逐段识别",
+			detector: PhoneDetector(ActionMask),
+			text:     "13812345678 13987654321",
+			wantText: "138****5678 139****4321",
+			wantHits: []Hit{
+				{Category: CategoryPhone, Text: "138****5678", Action: ActionMask},
+				{Category: CategoryPhone, Text: "139****4321", Action: ActionMask},
+			},
+		},
+		{
+			name:     "拆开后只打码命中的一段",
+			detector: IDCardDetector(ActionMask),
+			text:     "11010519491231002X-6222021234567890128",
+			wantText: "1101************2X-6222021234567890128",
+			wantHits: []Hit{{Category: CategoryIDCard, Text: "1101************2X", Action: ActionMask}},
+		},
+		{
+			name:     "未命中时保留全角原文",
+			detector: PhoneDetector(ActionMask),
+			text:     "订单号１２３４５",
+			wantText: "订单号１２３４５",
+		},
+		{
+			name:     "身份证号打码",
+			detector: IDCardDetector(ActionMask),
+			text:     "身份证 44030419900307123x",
+			wantText: "身份证 4403************3x",
+			wantHits: []Hit{{Category: CategoryIDCard, Text: "4403************3x", Action: ActionMask}},
+		},
+		{
+			name:     "银行卡号打码",
+			detector: BankCardDetector(ActionMask),
+			text:     "卡号 6217 0012 3456 7893",
+			wantText: "卡号 6217********7893",
+			wantHits: []Hit{{Category: CategoryBankCard, Text: "6217********7893", Action: ActionMask}},
+		},
+		{
+			name:     "身份证号不会被识别为银行卡号",
+			detector: BankCardDetector(ActionMask),
+			text:     "320106200002294562",
+			wantText: "320106200002294562",
+		},
+		{
+			name:     "送审时不改动原文，命中记录仍打码",
+			detector: PhoneDetector(ActionReview),
+			text:     "13812345678",
+			wantText: "13812345678",
+			wantHits: []Hit{{Category: CategoryPhone, Text: "138****5678", Action: ActionReview}},
+		},
+		{
+			name:     "放行时不检测",
+			detector: PhoneDetector(ActionPass),
+			text:     "13812345678",
+			wantText: "13812345678",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotText, gotHits := tt.detector.Apply(tt.text)
+			if gotText != tt.wantText {
+				t.Errorf("text = %q, want %q", gotText, tt.wantText)
+			}
+			if !reflect.DeepEqual(gotHits, tt.wantHits) {
+				t.Errorf("hits = %v, want %v", gotHits, tt.wantHits)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package filter
+
+import (
+	"sync/atomic"
+	"unicode"
+)
+
+// Word 敏感词词库条目
+type Word struct {
+	Text     string
+	Category string // 分类，如 abuse、porn，命中时记入 Hit.Category
+	Action   Action // 命中后的处理方式
+}
+
+// WordFilter 敏感词过滤器，匹配时跳过空白和标点（"傻 逼"、"傻*逼" 同样命中）
+// 词库可在运行时整体替换，替换与匹配可并发进行
+type WordFilter struct {
+	dict atomic.Pointer[wordDict]
+}
+
+type wordDict struct {
+	matcher *Matcher
+	words   []Word
+}
+
+// NewWordFilter 创建空词库的敏感词过滤器
+func NewWordFilter() *WordFilter {
+	f := &WordFilter{}
+	f.SetWords(nil)
+	return f
+}
+
+// SetWords 替换词库
+func (f *WordFilter) SetWords(words []Word) {
+	patterns := make([]string, len(words))
+	for i, w := range words {
+		patterns[i] = string(significantRunes([]rune(w.Text)))
+	}
+	f.dict.Store(&wordDict{matcher: NewMatcher(patterns), words: words})
+}
+
+// Size 词库条目数
+func (f *WordFilter) Size() int {
+	return len(f.dict.Load().words)
+}
+
+// Apply 匹配敏感词，Action 为 ActionMask 的词替换为 *
+func (f *WordFilter) Apply(text string) (string, []Hit) {
+	dict := f.dict.Load()
+	if len(dict.words) == 0 {
+		return text, nil
+	}
+
+	// 去掉空白和标点后匹配，positions 记录每个字符在原文中的下标
+	runes := []rune(text)
+	significant := make([]rune, 0, len(runes))
+	positions := make([]int, 0, len(runes))
+	for i, r := range runes {
+		if isSignificant(r) {
+			significant = append(significant, r)
+			positions = append(positions, i)
+		}
+	}
+
+	var hits []Hit
+	seen := make(map[int]bool)
+	masked := false
+	for _, m := range dict.matcher.FindAll(significant) {
+		word := dict.words[m.Pattern]
+		if !seen[m.Pattern] {
+			seen[m.Pattern] = true
+			hits = append(hits, Hit{Category: word.Category, Text: word.Text, Action: word.Action})
+		}
+		if word.Action == ActionMask {
+			for i := m.Start; i < m.End; i++ {
+				runes[positions[i]] = '*'
+			}
+			masked = true
+		}
+	}
+
+	if masked {
+		text = string(runes)
+	}
+	return text, hits
+}
+
+// significantRunes 去掉空白和标点
+func significantRunes(runes []rune) []rune {
+	out := make([]rune, 0, len(runes))
+	for _, r := range runes {
+		if isSignificant(r) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// isSignificant 参与匹配的字符：字母、数字和汉字等，空白、标点和符号会被跳过
+func isSignificant(r rune) bool {
+	return !unicode.IsSpace(r) && !unicode.IsPunct(r) && !unicode.IsSymbol(r)
+}
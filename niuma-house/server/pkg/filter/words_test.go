@@ -0,0 +1,114 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWordFilterApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		words    []Word
+		text     string
+		wantText string
+		wantHits []Hit
+	}{
+		{
+			name:     "空词库",
+			words:    nil,
+			text:     "加微信",
+			wantText: "加微信",
+		},
+		{
+			name:     "打码",
+			words:    []Word{{Text: "微信", Category: "ad", Action: ActionMask}},
+			text:     "加微信聊",
+			wantText: "加**聊",
+			wantHits: []Hit{{Category: "ad", Text: "微信", Action: ActionMask}},
+		},
+		{
+			name:     "跳过词中的空白和标点，只打码有效字符",
+			words:    []Word{{Text: "微信", Category: "ad", Action: ActionMask}},
+			text:     "加 微 - 信",
+			wantText: "加 * - *",
+			wantHits: []Hit{{Category: "ad", Text: "微信", Action: ActionMask}},
+		},
+		{
+			name:     "词库中的词带标点时同样按有效字符匹配",
+			words:    []Word{{Text: "微-信", Category: "ad", Action: ActionMask}},
+			text:     "微信",
+			wantText: "**",
+			wantHits: []Hit{{Category: "ad", Text: "微-信", Action: ActionMask}},
+		},
+		{
+			name:     "忽略大小写和全半角",
+			words:    []Word{{Text: "vx", Category: "ad", Action: ActionMask}},
+			text:     "加ＶＸ号",
+			wantText: "加**号",
+			wantHits: []Hit{{Category: "ad", Text: "vx", Action: ActionMask}},
+		},
+		{
+			name:     "同一个词多次出现只记一次命中",
+			words:    []Word{{Text: "微信", Category: "ad", Action: ActionMask}},
+			text:     "微信，微信",
+			wantText: "**，**",
+			wantHits: []Hit{{Category: "ad", Text: "微信", Action: ActionMask}},
+		},
+		{
+			name: "送审和拒绝的词不改动原文",
+			words: []Word{
+				{Text: "代开发票", Category: "fraud", Action: ActionReview},
+				{Text: "枪支", Category: "illegal", Action: ActionReject},
+			},
+			text:     "代开发票，出售枪支",
+			wantText: "代开发票，出售枪支",
+			wantHits: []Hit{
+				{Category: "fraud", Text: "代开发票", Action: ActionReview},
+				{Category: "illegal", Text: "枪支", Action: ActionReject},
+			},
+		},
+		{
+			name: "重叠的词分别命中",
+			words: []Word{
+				{Text: "加微信", Category: "ad", Action: ActionMask},
+				{Text: "微信", Category: "ad2", Action: ActionReview},
+			},
+			text:     "请加微信",
+			wantText: "请***",
+			wantHits: []Hit{
+				{Category: "ad", Text: "加微信", Action: ActionMask},
+				{Category: "ad2", Text: "微信", Action: ActionReview},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewWordFilter()
+			f.SetWords(tt.words)
+			gotText, gotHits := f.Apply(tt.text)
+			if gotText != tt.wantText {
+				t.Errorf("text = %q, want %q", gotText, tt.wantText)
+			}
+			if !reflect.DeepEqual(gotHits, tt.wantHits) {
+				t.Errorf("hits = %v, want %v", gotHits, tt.wantHits)
+			}
+		})
+	}
+}
+
+func TestWordFilterSetWords(t *testing.T) {
+	f := NewWordFilter()
+	if f.Size() != 0 {
+		t.Fatalf("Size() = %d, want 0", f.Size())
+	}
+
+	f.SetWords([]Word{{Text: "旧词", Action: ActionMask}})
+	f.SetWords([]Word{{Text: "新词", Action: ActionMask}, {Text: "另一个", Action: ActionMask}})
+	if f.Size() != 2 {
+		t.Fatalf("Size() = %d, want 2", f.Size())
+	}
+	if text, _ := f.Apply("旧词新词"); text != "旧词**" {
+		t.Errorf("Apply after SetWords = %q, want %q", text, "旧词**")
+	}
+}
@@ -7,47 +7,105 @@ import (
 	"niuma-house/pkg/config"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// Token 类型
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
 )
 
 // Claims 自定义 JWT Claims
 type Claims struct {
-	UserID   uint   `json:"user_id"`
-	Username string `json:"username"`
-	Role     string `json:"role"`
+	UserID    uint   `json:"user_id"`
+	Username  string `json:"username"`
+	Role      string `json:"role"`
+	SessionID string `json:"sid"`
+	TokenType string `json:"typ"`
 	jwt.RegisteredClaims
 }
 
-var jwtSecret []byte
-var expireHours int
+// TokenPair 访问令牌 + 刷新令牌
+type TokenPair struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"` // 访问令牌有效期（秒）
+
+	refreshID string
+}
+
+var (
+	jwtSecret     []byte
+	accessExpire  time.Duration
+	refreshExpire time.Duration
+)
 
 // Init 初始化 JWT 配置
 func Init(cfg *config.JWTConfig) {
 	jwtSecret = []byte(cfg.Secret)
-	expireHours = cfg.ExpireHours
+
+	accessExpire = time.Duration(cfg.AccessExpireMinutes) * time.Minute
+	if accessExpire <= 0 {
+		accessExpire = 30 * time.Minute
+	}
+	refreshExpire = time.Duration(cfg.RefreshExpireHours) * time.Hour
+	if refreshExpire <= 0 {
+		refreshExpire = 7 * 24 * time.Hour
+	}
 }
 
-// GenerateToken 生成 JWT Token
-func GenerateToken(userID uint, username string, role string) (string, error) {
+// generateToken 生成指定类型的 JWT Token
+func generateToken(userID uint, username, role, sessionID, tokenType string, expire time.Duration) (string, string, error) {
+	now := time.Now()
+	tokenID := uuid.New().String()
 	claims := Claims{
-		UserID:   userID,
-		Username: username,
-		Role:     role,
+		UserID:    userID,
+		Username:  username,
+		Role:      role,
+		SessionID: sessionID,
+		TokenType: tokenType,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Duration(expireHours) * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        tokenID,
+			ExpiresAt: jwt.NewNumericDate(now.Add(expire)),
+			IssuedAt:  jwt.NewNumericDate(now),
 			Issuer:    "niuma-house",
 		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	signed, err := token.SignedString(jwtSecret)
+	if err != nil {
+		return "", "", err
+	}
+	return signed, tokenID, nil
+}
+
+// generateTokenPair 为会话生成一对令牌
+func generateTokenPair(userID uint, username, role, sessionID string) (*TokenPair, error) {
+	accessToken, _, err := generateToken(userID, username, role, sessionID, TokenTypeAccess, accessExpire)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, refreshID, err := generateToken(userID, username, role, sessionID, TokenTypeRefresh, refreshExpire)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(accessExpire / time.Second),
+		refreshID:    refreshID,
+	}, nil
 }
 
 // ParseToken 解析 JWT Token
 func ParseToken(tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
 		return jwtSecret, nil
-	})
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
 
 	if err != nil {
 		return nil, err
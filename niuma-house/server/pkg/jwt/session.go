@@ -0,0 +1,155 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"niuma-house/pkg/cache"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// 会话相关错误
+var (
+	ErrSessionRevoked = errors.New("登录已失效，请重新登录")
+	ErrTokenReused    = errors.New("刷新令牌已被使用，会话已注销")
+	ErrWrongTokenType = errors.New("Token 类型错误")
+)
+
+// sessionKey 会话 Key，Hash 结构: user_id, refresh_id
+func sessionKey(sessionID string) string {
+	return "auth:session:" + sessionID
+}
+
+// userSessionsKey 用户会话集合 Key
+func userSessionsKey(userID uint) string {
+	return fmt.Sprintf("auth:user_sessions:%d", userID)
+}
+
+// rotateSessionScript 的返回值
+const (
+	rotateOK      = 1
+	rotateRevoked = 0  // 会话不存在或不属于该用户
+	rotateReused  = -1 // 旧刷新令牌已被轮换过
+)
+
+// rotateSessionScript 比较并替换会话中的刷新令牌 ID，成功时续期会话
+// KEYS: 会话 Key、用户会话集合 Key；ARGV: user_id、旧 refresh_id、新 refresh_id、有效期秒数、会话 ID
+var rotateSessionScript = redis.NewScript(`
+local stored = redis.call('HMGET', KEYS[1], 'user_id', 'refresh_id')
+if not stored[1] or stored[1] ~= ARGV[1] then
+	return 0
+end
+if stored[2] ~= ARGV[2] then
+	return -1
+end
+redis.call('HSET', KEYS[1], 'refresh_id', ARGV[3])
+redis.call('EXPIRE', KEYS[1], ARGV[4])
+redis.call('SADD', KEYS[2], ARGV[5])
+redis.call('EXPIRE', KEYS[2], ARGV[4])
+return 1
+`)
+
+// NewSession 创建登录会话并签发令牌
+func NewSession(userID uint, username, role string) (*TokenPair, error) {
+	sessionID := uuid.New().String()
+	pair, err := generateTokenPair(userID, username, role, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := saveSession(userID, sessionID, pair.refreshID); err != nil {
+		return nil, err
+	}
+	return pair, nil
+}
+
+// Rotate 使用刷新令牌轮换出新的令牌对，旧刷新令牌随即失效
+// 如果检测到旧刷新令牌被重复使用，整个会话将被注销
+func Rotate(claims *Claims, username, role string) (*TokenPair, error) {
+	if claims.TokenType != TokenTypeRefresh {
+		return nil, ErrWrongTokenType
+	}
+
+	pair, err := generateTokenPair(claims.UserID, username, role, claims.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 校验旧刷新令牌与写入新令牌必须原子完成，否则并发刷新可能都通过校验
+	status, err := rotateSessionScript.Run(context.Background(), cache.GetRedis(),
+		[]string{sessionKey(claims.SessionID), userSessionsKey(claims.UserID)},
+		claims.UserID, claims.ID, pair.refreshID, int64(refreshExpire.Seconds()), claims.SessionID).Int()
+	if err != nil {
+		return nil, err
+	}
+	switch status {
+	case rotateOK:
+		return pair, nil
+	case rotateReused:
+		RevokeSession(claims.UserID, claims.SessionID)
+		return nil, ErrTokenReused
+	default:
+		return nil, ErrSessionRevoked
+	}
+}
+
+// saveSession 保存会话，有效期与刷新令牌一致
+func saveSession(userID uint, sessionID, refreshID string) error {
+	ctx := context.Background()
+	pipe := cache.GetRedis().TxPipeline()
+	pipe.HSet(ctx, sessionKey(sessionID), "user_id", userID, "refresh_id", refreshID)
+	pipe.Expire(ctx, sessionKey(sessionID), refreshExpire)
+	pipe.SAdd(ctx, userSessionsKey(userID), sessionID)
+	pipe.Expire(ctx, userSessionsKey(userID), refreshExpire)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// ValidateSession 检查访问令牌所属会话是否仍然有效
+func ValidateSession(claims *Claims) (bool, error) {
+	if claims.SessionID == "" {
+		return false, nil
+	}
+
+	userID, err := cache.GetRedis().HGet(context.Background(), sessionKey(claims.SessionID), "user_id").Result()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return userID == strconv.FormatUint(uint64(claims.UserID), 10), nil
+}
+
+// RevokeSession 注销单个会话
+func RevokeSession(userID uint, sessionID string) error {
+	ctx := context.Background()
+	pipe := cache.GetRedis().TxPipeline()
+	pipe.Del(ctx, sessionKey(sessionID))
+	pipe.SRem(ctx, userSessionsKey(userID), sessionID)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// RevokeUserSessions 注销用户的全部会话（封禁、改密码、踢下线）
+func RevokeUserSessions(userID uint) error {
+	ctx := context.Background()
+	rdb := cache.GetRedis()
+
+	sessionIDs, err := rdb.SMembers(ctx, userSessionsKey(userID)).Result()
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(sessionIDs)+1)
+	for _, sessionID := range sessionIDs {
+		keys = append(keys, sessionKey(sessionID))
+	}
+	keys = append(keys, userSessionsKey(userID))
+
+	return rdb.Del(ctx, keys...).Err()
+}
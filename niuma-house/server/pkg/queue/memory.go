@@ -0,0 +1,173 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// 进程内队列的容量，写满后 Publish 阻塞直到 ctx 超时
+const memoryQueueSize = 10000
+
+// memoryMessage 进程内消息
+type memoryMessage struct {
+	id         string
+	routingKey string
+	body       []byte
+	failures   int // 已失败次数
+}
+
+// memoryDeadLetter 进程内死信
+type memoryDeadLetter struct {
+	letter DeadLetter
+	msg    *memoryMessage
+	queue  string
+}
+
+// memoryBackend 进程内后端，重试和死信语义与 RabbitMQ 后端一致，但消息不持久化
+type memoryBackend struct {
+	queues map[string]chan *memoryMessage // 队列名 -> 消息
+	routes map[string][]string            // routing key -> 队列名
+
+	mutex sync.Mutex
+	dead  []memoryDeadLetter
+}
+
+func newMemoryBackend() *memoryBackend {
+	b := &memoryBackend{
+		queues: make(map[string]chan *memoryMessage),
+		routes: make(map[string][]string),
+	}
+	for queueName, routingKey := range Bindings {
+		b.queues[queueName] = make(chan *memoryMessage, memoryQueueSize)
+		b.routes[routingKey] = append(b.routes[routingKey], queueName)
+	}
+	return b
+}
+
+// Publish 投递到绑定该 routing key 的全部队列，没有绑定的消息直接丢弃
+func (b *memoryBackend) Publish(ctx context.Context, routingKey string, body []byte) error {
+	id := uuid.NewString()
+	for _, queueName := range b.routes[routingKey] {
+		msg := &memoryMessage{id: id, routingKey: routingKey, body: body}
+		if err := b.enqueue(ctx, queueName, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *memoryBackend) enqueue(ctx context.Context, queueName string, msg *memoryMessage) error {
+	ch, ok := b.queues[queueName]
+	if !ok {
+		return errors.New("memory queue: unknown queue " + queueName)
+	}
+	select {
+	case ch <- msg:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Consume 持续消费队列
+func (b *memoryBackend) Consume(queueName string, handler Handler) {
+	ch, ok := b.queues[queueName]
+	if !ok {
+		log.Printf("Consumer %s stopped: unknown queue", queueName)
+		return
+	}
+
+	log.Printf("Consumer %s started, waiting for messages...", queueName)
+	for msg := range ch {
+		if err := handler(msg.body); err != nil {
+			log.Printf("Failed to process message from %s: %v", queueName, err)
+			b.retry(queueName, msg, err)
+		}
+	}
+}
+
+// retry 延迟后重新入队，重试耗尽或永久性错误时转入死信
+func (b *memoryBackend) retry(queueName string, msg *memoryMessage, cause error) {
+	var perm *permanentError
+	if !errors.As(cause, &perm) && msg.failures < len(retryDelays) {
+		delay := retryDelays[msg.failures]
+		msg.failures++
+		time.AfterFunc(delay, func() {
+			b.queues[queueName] <- msg
+		})
+		return
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.dead = append(b.dead, memoryDeadLetter{
+		letter: DeadLetter{
+			MessageID:  msg.id,
+			Queue:      queueName,
+			RoutingKey: msg.routingKey,
+			Retries:    msg.failures,
+			Error:      cause.Error(),
+			DeadAt:     time.Now(),
+			Body:       string(msg.body),
+		},
+		msg:   msg,
+		queue: queueName,
+	})
+	log.Printf("Message %s dead-lettered after %d attempts: %v", msg.id, msg.failures+1, cause)
+}
+
+// ListDeadLetters 查看死信
+func (b *memoryBackend) ListDeadLetters(ctx context.Context, limit int) ([]DeadLetter, int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	n := min(limit, maxDeadLetterBatch, len(b.dead))
+	letters := make([]DeadLetter, n)
+	for i := 0; i < n; i++ {
+		letters[i] = b.dead[i].letter
+	}
+	return letters, len(b.dead), nil
+}
+
+// ReplayDeadLetters 将死信重新投递到原队列，重试次数清零
+func (b *memoryBackend) ReplayDeadLetters(ctx context.Context, messageIDs []string, limit int) (int, error) {
+	wanted := make(map[string]bool, len(messageIDs))
+	for _, id := range messageIDs {
+		wanted[id] = true
+	}
+	limit = min(limit, maxDeadLetterBatch)
+
+	b.mutex.Lock()
+	var replay []memoryDeadLetter
+	remaining := b.dead[:0:0]
+	for _, d := range b.dead {
+		matched := wanted[d.msg.id] || (len(wanted) == 0 && len(replay) < limit)
+		if matched {
+			replay = append(replay, d)
+		} else {
+			remaining = append(remaining, d)
+		}
+	}
+	b.dead = remaining
+	b.mutex.Unlock()
+
+	for i, d := range replay {
+		d.msg.failures = 0
+		if err := b.enqueue(ctx, d.queue, d.msg); err != nil {
+			// 未能投递的放回死信
+			b.mutex.Lock()
+			b.dead = append(replay[i:], b.dead...)
+			b.mutex.Unlock()
+			return i, err
+		}
+	}
+	return len(replay), nil
+}
+
+// Close 进程内队列无需释放资源
+func (b *memoryBackend) Close() {}
@@ -0,0 +1,108 @@
+package queue
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"niuma-house/pkg/config"
+)
+
+// 消息队列后端类型
+const (
+	BackendRabbitMQ = "rabbitmq" // RabbitMQ，生产环境使用
+	BackendMemory   = "memory"   // 进程内队列，用于本地开发和集成测试，重启后未消费的消息丢失
+)
+
+// Bindings 业务队列名 -> routing key
+var Bindings = map[string]string{
+	"exp_queue":          "exp",
+	"notification_queue": "notification",
+	"level_queue":        "level_up",
+}
+
+// retryDelays 各次重试前的等待时间，用完后转入死信队列
+var retryDelays = []time.Duration{5 * time.Second, 30 * time.Second, 2 * time.Minute, 10 * time.Minute}
+
+// 单次查看/重放的死信数上限
+const maxDeadLetterBatch = 200
+
+// Handler 消息处理函数，返回 nil 表示处理成功
+// 返回错误时按退避策略重试，重试耗尽或 Permanent 错误转入死信队列
+type Handler func(body []byte) error
+
+// Backend 消息队列后端
+type Backend interface {
+	// Publish 按 routing key 发布消息，返回 nil 表示后端已可靠接收
+	Publish(ctx context.Context, routingKey string, body []byte) error
+	// Consume 持续消费队列（阻塞调用）
+	Consume(queueName string, handler Handler)
+	// ListDeadLetters 查看死信，不会移除消息
+	ListDeadLetters(ctx context.Context, limit int) ([]DeadLetter, int, error)
+	// ReplayDeadLetters 将死信重新投递到原队列，messageIDs 为空时重放头部的 limit 条
+	ReplayDeadLetters(ctx context.Context, messageIDs []string, limit int) (int, error)
+	// Close 关闭后端
+	Close()
+}
+
+// DeadLetter 死信消息
+type DeadLetter struct {
+	MessageID  string    `json:"message_id"`
+	Queue      string    `json:"queue"`
+	RoutingKey string    `json:"routing_key"`
+	Retries    int       `json:"retries"`
+	Error      string    `json:"error"`
+	DeadAt     time.Time `json:"dead_at"`
+	Body       string    `json:"body"`
+}
+
+var (
+	backend Backend
+	once    sync.Once
+)
+
+// Init 按配置初始化消息队列后端
+func Init(cfg *config.QueueConfig, rabbitCfg *config.RabbitMQConfig) Backend {
+	once.Do(func() {
+		if cfg != nil && cfg.Backend == BackendMemory {
+			log.Println("Queue backend: memory")
+			backend = newMemoryBackend()
+			return
+		}
+		log.Println("Queue backend: rabbitmq")
+		backend = newRabbitMQBackend(rabbitCfg)
+	})
+	return backend
+}
+
+// Get 获取消息队列后端单例
+func Get() Backend {
+	if backend == nil {
+		log.Fatal("Queue not initialized. Call Init first.")
+	}
+	return backend
+}
+
+// Close 关闭消息队列后端
+func Close() {
+	if backend != nil {
+		backend.Close()
+	}
+}
+
+// permanentError 重试也无法成功的错误（消息格式错误、数据已不存在等）
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent 标记为永久性错误，消息直接转入死信队列而不重试
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
@@ -1,100 +1,296 @@
 package queue
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"sync"
+	"time"
 
 	"niuma-house/pkg/config"
 
+	"github.com/google/uuid"
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
-var (
-	conn    *amqp.Connection
-	channel *amqp.Channel
-	once    sync.Once
+// 交换机
+const (
+	ExchangeActivity   = "user_activity"      // 业务消息
+	ExchangeDeadLetter = "user_activity.dlx"  // 重试耗尽或无法处理的消息
+	QueueDeadLetter    = "user_activity.dead" // 死信队列
 )
 
-// InitRabbitMQ 初始化 RabbitMQ 连接
-func InitRabbitMQ(cfg *config.RabbitMQConfig) *amqp.Channel {
-	once.Do(func() {
-		url := fmt.Sprintf("amqp://%s:%s@%s:%d%s",
+// 断线重连的退避区间
+const (
+	reconnectMinDelay = time.Second
+	reconnectMaxDelay = 30 * time.Second
+)
+
+// 每个消费者未确认消息的上限
+const consumerPrefetch = 10
+
+// rabbitMQBackend RabbitMQ 后端：断线后自动重连并重新声明拓扑
+type rabbitMQBackend struct {
+	url string
+
+	mutex  sync.RWMutex
+	conn   *amqp.Connection
+	ready  chan struct{} // 连接可用时关闭，断线后替换为新的 channel
+	closed bool
+
+	pubMutex sync.Mutex
+	pub      *amqp.Channel // confirm 模式的发布 channel，受 pubMutex 保护
+}
+
+// newRabbitMQBackend 连接 RabbitMQ，之后连接断开会在后台自动重连
+func newRabbitMQBackend(cfg *config.RabbitMQConfig) *rabbitMQBackend {
+	b := &rabbitMQBackend{
+		url: fmt.Sprintf("amqp://%s:%s@%s:%d%s",
 			cfg.Username,
 			cfg.Password,
 			cfg.Host,
 			cfg.Port,
 			cfg.VHost,
-		)
+		),
+		ready: make(chan struct{}),
+	}
 
-		var err error
-		conn, err = amqp.Dial(url)
-		if err != nil {
-			log.Fatalf("Failed to connect to RabbitMQ: %v", err)
+	if err := b.connect(); err != nil {
+		log.Fatalf("Failed to connect to RabbitMQ: %v", err)
+	}
+	log.Println("RabbitMQ connected successfully")
+	return b
+}
+
+// connect 建立连接、声明拓扑并开启发布确认
+func (b *rabbitMQBackend) connect() error {
+	conn, err := amqp.Dial(b.url)
+	if err != nil {
+		return err
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	if err := declareTopology(ch); err != nil {
+		conn.Close()
+		return err
+	}
+	if err := ch.Confirm(false); err != nil {
+		conn.Close()
+		return err
+	}
+
+	b.pubMutex.Lock()
+	b.pub = ch
+	b.pubMutex.Unlock()
+
+	b.mutex.Lock()
+	b.conn = conn
+	close(b.ready)
+	b.mutex.Unlock()
+
+	go b.watch(conn)
+	return nil
+}
+
+// watch 连接断开后按指数退避重连
+func (b *rabbitMQBackend) watch(conn *amqp.Connection) {
+	err := <-conn.NotifyClose(make(chan *amqp.Error, 1))
+
+	b.mutex.Lock()
+	if b.closed {
+		b.mutex.Unlock()
+		return
+	}
+	b.ready = make(chan struct{})
+	b.mutex.Unlock()
+	log.Printf("RabbitMQ connection lost: %v", err)
+
+	delay := reconnectMinDelay
+	for {
+		time.Sleep(delay)
+		if err := b.connect(); err != nil {
+			log.Printf("RabbitMQ reconnect failed: %v", err)
+			delay = min(delay*2, reconnectMaxDelay)
+			continue
 		}
+		log.Println("RabbitMQ reconnected")
+		return
+	}
+}
 
-		channel, err = conn.Channel()
-		if err != nil {
-			log.Fatalf("Failed to open RabbitMQ channel: %v", err)
+// connection 等待连接可用
+func (b *rabbitMQBackend) connection(ctx context.Context) (*amqp.Connection, error) {
+	b.mutex.RLock()
+	ready, closed := b.ready, b.closed
+	b.mutex.RUnlock()
+	if closed {
+		return nil, errors.New("rabbitmq: connection closed")
+	}
+
+	select {
+	case <-ready:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	return b.conn, nil
+}
+
+// declareTopology 声明交换机、业务队列、重试队列和死信队列
+// 业务队列保持无参数声明，兼容已存在的队列；死信由消费端显式投递到死信交换机
+func declareTopology(ch *amqp.Channel) error {
+	if err := ch.ExchangeDeclare(ExchangeActivity, "direct", true, false, false, false, nil); err != nil {
+		return fmt.Errorf("declare exchange %s: %w", ExchangeActivity, err)
+	}
+
+	for queueName, routingKey := range Bindings {
+		if _, err := ch.QueueDeclare(queueName, true, false, false, false, nil); err != nil {
+			return fmt.Errorf("declare queue %s: %w", queueName, err)
+		}
+		if err := ch.QueueBind(queueName, routingKey, ExchangeActivity, false, nil); err != nil {
+			return fmt.Errorf("bind queue %s: %w", queueName, err)
+		}
+	}
+
+	// 每档延迟一个 fanout 交换机 + 带 TTL 的队列，到期后按原 routing key 投回业务交换机
+	for _, delay := range retryDelays {
+		name := retryExchange(delay)
+		if err := ch.ExchangeDeclare(name, "fanout", true, false, false, false, nil); err != nil {
+			return fmt.Errorf("declare exchange %s: %w", name, err)
+		}
+		args := amqp.Table{
+			"x-message-ttl":          delay.Milliseconds(),
+			"x-dead-letter-exchange": ExchangeActivity,
+		}
+		if _, err := ch.QueueDeclare(name, true, false, false, false, args); err != nil {
+			return fmt.Errorf("declare queue %s: %w", name, err)
+		}
+		if err := ch.QueueBind(name, "", name, false, nil); err != nil {
+			return fmt.Errorf("bind queue %s: %w", name, err)
 		}
+	}
+
+	if err := ch.ExchangeDeclare(ExchangeDeadLetter, "fanout", true, false, false, false, nil); err != nil {
+		return fmt.Errorf("declare exchange %s: %w", ExchangeDeadLetter, err)
+	}
+	if _, err := ch.QueueDeclare(QueueDeadLetter, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("declare queue %s: %w", QueueDeadLetter, err)
+	}
+	if err := ch.QueueBind(QueueDeadLetter, "", ExchangeDeadLetter, false, nil); err != nil {
+		return fmt.Errorf("bind queue %s: %w", QueueDeadLetter, err)
+	}
+	return nil
+}
+
+// Publish 发布持久化 JSON 消息到业务交换机并等待 broker 确认，未确认或超时返回错误
+func (b *rabbitMQBackend) Publish(ctx context.Context, routingKey string, body []byte) error {
+	return b.publish(ctx, ExchangeActivity, routingKey, amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		MessageId:    uuid.NewString(),
+		Timestamp:    time.Now(),
+		Body:         body,
+	})
+}
 
-		// 声明交换机
-		err = channel.ExchangeDeclare(
-			"user_activity", // name
-			"direct",        // type
-			true,            // durable
-			false,           // auto-deleted
-			false,           // internal
-			false,           // no-wait
-			nil,             // arguments
-		)
+func (b *rabbitMQBackend) publish(ctx context.Context, exchange, routingKey string, msg amqp.Publishing) error {
+	conn, err := b.connection(ctx)
+	if err != nil {
+		return err
+	}
+
+	b.pubMutex.Lock()
+	defer b.pubMutex.Unlock()
+
+	// 发布 channel 可能因协议错误单独关闭，连接仍可用时重新打开
+	if b.pub == nil || b.pub.IsClosed() {
+		ch, err := conn.Channel()
 		if err != nil {
-			log.Fatalf("Failed to declare exchange: %v", err)
+			return err
 		}
+		if err := ch.Confirm(false); err != nil {
+			ch.Close()
+			return err
+		}
+		b.pub = ch
+	}
+
+	confirm, err := b.pub.PublishWithDeferredConfirmWithContext(ctx, exchange, routingKey, false, false, msg)
+	if err != nil {
+		return err
+	}
+	acked, err := confirm.WaitContext(ctx)
+	if err != nil {
+		return err
+	}
+	if !acked {
+		return errors.New("rabbitmq: publish not acknowledged by broker")
+	}
+	return nil
+}
 
-		// 声明队列
-		_, err = channel.QueueDeclare(
-			"exp_queue", // name
-			true,        // durable
-			false,       // delete when unused
-			false,       // exclusive
-			false,       // no-wait
-			nil,         // arguments
-		)
+// Consume 持续消费队列，连接断开后自动重新订阅
+func (b *rabbitMQBackend) Consume(queueName string, handler Handler) {
+	for {
+		conn, err := b.connection(context.Background())
 		if err != nil {
-			log.Fatalf("Failed to declare queue: %v", err)
+			log.Printf("Consumer %s stopped: %v", queueName, err)
+			return
 		}
 
-		// 绑定队列到交换机
-		err = channel.QueueBind(
-			"exp_queue",     // queue name
-			"exp",           // routing key
-			"user_activity", // exchange
-			false,
-			nil,
-		)
+		deliveries, err := subscribe(conn, queueName)
 		if err != nil {
-			log.Fatalf("Failed to bind queue: %v", err)
+			log.Printf("Failed to register consumer %s: %v", queueName, err)
+			time.Sleep(reconnectMinDelay)
+			continue
 		}
+		log.Printf("Consumer %s started, waiting for messages...", queueName)
 
-		log.Println("RabbitMQ connected successfully")
-	})
-	return channel
-}
+		for d := range deliveries {
+			if err := handler(d.Body); err != nil {
+				log.Printf("Failed to process message from %s: %v", queueName, err)
+				b.retry(queueName, d, err)
+				continue
+			}
+			d.Ack(false)
+		}
 
-// GetChannel 获取 RabbitMQ Channel 单例
-func GetChannel() *amqp.Channel {
-	if channel == nil {
-		log.Fatal("RabbitMQ not initialized. Call InitRabbitMQ first.")
+		log.Printf("Consumer %s disconnected, resubscribing...", queueName)
+		time.Sleep(reconnectMinDelay)
 	}
-	return channel
 }
 
-// Close 关闭连接
-func Close() {
-	if channel != nil {
-		channel.Close()
+func subscribe(conn *amqp.Connection, queueName string) (<-chan amqp.Delivery, error) {
+	ch, err := conn.Channel()
+	if err != nil {
+		return nil, err
+	}
+	if err := ch.Qos(consumerPrefetch, 0, false); err != nil {
+		ch.Close()
+		return nil, err
 	}
+	deliveries, err := ch.Consume(queueName, "", false, false, false, false, nil)
+	if err != nil {
+		ch.Close()
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// Close 关闭连接，不再重连
+func (b *rabbitMQBackend) Close() {
+	b.mutex.Lock()
+	b.closed = true
+	conn := b.conn
+	b.mutex.Unlock()
+
 	if conn != nil {
 		conn.Close()
 	}
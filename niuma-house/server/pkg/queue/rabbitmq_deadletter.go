@@ -0,0 +1,109 @@
+package queue
+
+import (
+	"context"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// ListDeadLetters 查看死信队列头部的消息，不会移除消息
+// 通过未确认读取后关闭 channel 的方式让消息重新入队
+func (b *rabbitMQBackend) ListDeadLetters(ctx context.Context, limit int) ([]DeadLetter, int, error) {
+	ch, err := b.deadLetterChannel(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer ch.Close()
+
+	q, err := ch.QueueDeclarePassive(QueueDeadLetter, true, false, false, false, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	letters := make([]DeadLetter, 0)
+	for i := 0; i < min(limit, maxDeadLetterBatch); i++ {
+		d, ok, err := ch.Get(QueueDeadLetter, false)
+		if err != nil {
+			return nil, 0, err
+		}
+		if !ok {
+			break
+		}
+		letters = append(letters, toDeadLetter(d))
+	}
+	return letters, q.Messages, nil
+}
+
+// ReplayDeadLetters 将死信按原 routing key 重新投递到业务交换机，重试次数清零
+// messageIDs 为空时重放队列头部的 limit 条消息，否则只重放指定消息
+func (b *rabbitMQBackend) ReplayDeadLetters(ctx context.Context, messageIDs []string, limit int) (int, error) {
+	ch, err := b.deadLetterChannel(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer ch.Close() // 未匹配的消息未确认，关闭 channel 后重新入队
+
+	wanted := make(map[string]bool, len(messageIDs))
+	for _, id := range messageIDs {
+		wanted[id] = true
+	}
+	if len(wanted) > 0 {
+		limit = maxDeadLetterBatch
+	}
+
+	replayed := 0
+	for i := 0; i < min(limit, maxDeadLetterBatch); i++ {
+		d, ok, err := ch.Get(QueueDeadLetter, false)
+		if err != nil {
+			return replayed, err
+		}
+		if !ok {
+			break
+		}
+		if len(wanted) > 0 && !wanted[d.MessageId] {
+			continue
+		}
+
+		err = b.publish(ctx, ExchangeActivity, d.RoutingKey, amqp.Publishing{
+			ContentType:  d.ContentType,
+			DeliveryMode: amqp.Persistent,
+			MessageId:    d.MessageId,
+			Timestamp:    time.Now(),
+			Body:         d.Body,
+		})
+		if err != nil {
+			return replayed, err
+		}
+		d.Ack(false)
+		replayed++
+
+		if len(wanted) > 0 && replayed == len(wanted) {
+			break
+		}
+	}
+	return replayed, nil
+}
+
+func (b *rabbitMQBackend) deadLetterChannel(ctx context.Context) (*amqp.Channel, error) {
+	conn, err := b.connection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return conn.Channel()
+}
+
+func toDeadLetter(d amqp.Delivery) DeadLetter {
+	letter := DeadLetter{
+		MessageID:  d.MessageId,
+		RoutingKey: d.RoutingKey,
+		Retries:    retryCount(d.Headers) - 1,
+		Body:       string(d.Body),
+	}
+	letter.Queue, _ = d.Headers[headerQueue].(string)
+	letter.Error, _ = d.Headers[headerError].(string)
+	if deadAt, ok := d.Headers[headerDeadAt].(int64); ok {
+		letter.DeadAt = time.Unix(deadAt, 0)
+	}
+	return letter
+}
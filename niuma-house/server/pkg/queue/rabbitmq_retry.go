@@ -0,0 +1,84 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// 消息头
+const (
+	headerRetryCount = "x-retry-count" // 已重试次数
+	headerQueue      = "x-queue"       // 失败时所在的业务队列
+	headerError      = "x-error"       // 最后一次失败原因
+	headerDeadAt     = "x-dead-at"     // 转入死信队列的时间（Unix 秒）
+)
+
+// 重试/死信投递的超时
+const republishTimeout = 5 * time.Second
+
+func retryExchange(delay time.Duration) string {
+	return fmt.Sprintf("%s.retry.%ds", ExchangeActivity, int(delay/time.Second))
+}
+
+// retry 将处理失败的消息投递到下一档重试队列（RabbitMQ），重试耗尽或永久性错误时投递到死信队列
+// 投递成功后确认原消息；投递失败（broker 异常）时原消息重新入队
+func (b *rabbitMQBackend) retry(queueName string, d amqp.Delivery, cause error) {
+	count := retryCount(d.Headers)
+
+	var perm *permanentError
+	exchange := ExchangeDeadLetter
+	if !errors.As(cause, &perm) && count < len(retryDelays) {
+		exchange = retryExchange(retryDelays[count])
+	}
+
+	headers := amqp.Table{}
+	for k, v := range d.Headers {
+		headers[k] = v
+	}
+	headers[headerRetryCount] = int32(count + 1)
+	headers[headerQueue] = queueName
+	headers[headerError] = cause.Error()
+	if exchange == ExchangeDeadLetter {
+		headers[headerDeadAt] = time.Now().Unix()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), republishTimeout)
+	defer cancel()
+
+	err := b.publish(ctx, exchange, d.RoutingKey, amqp.Publishing{
+		Headers:      headers,
+		ContentType:  d.ContentType,
+		DeliveryMode: amqp.Persistent,
+		MessageId:    d.MessageId,
+		Timestamp:    d.Timestamp,
+		Body:         d.Body,
+	})
+	if err != nil {
+		log.Printf("Failed to republish message %s to %s: %v", d.MessageId, exchange, err)
+		d.Nack(false, true)
+		return
+	}
+
+	if exchange == ExchangeDeadLetter {
+		log.Printf("Message %s dead-lettered after %d attempts: %v", d.MessageId, count+1, cause)
+	}
+	d.Ack(false)
+}
+
+// retryCount 读取已重试次数
+func retryCount(headers amqp.Table) int {
+	switch v := headers[headerRetryCount].(type) {
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case int:
+		return v
+	}
+	return 0
+}
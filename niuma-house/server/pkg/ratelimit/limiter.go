@@ -0,0 +1,62 @@
+package ratelimit
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"niuma-house/pkg/cache"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Rule 限流规则
+type Rule struct {
+	Name    string        // 规则名，用作 Redis key 的一部分
+	Limit   int           // 每个用户（Lv.1）在窗口内允许的次数，按等级放宽；0 表示不限制
+	IPLimit int           // 每个 IP 在窗口内允许的次数；0 表示不限制
+	Window  time.Duration // 窗口长度
+}
+
+// Result 限流结果
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration // 被拒绝时距离窗口重置的时间
+}
+
+// counterScript 固定窗口计数，返回 {当前次数, 窗口剩余毫秒}
+var counterScript = redis.NewScript(`
+local n = redis.call('INCR', KEYS[1])
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl < 0 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+	ttl = tonumber(ARGV[1])
+end
+return {n, ttl}
+`)
+
+// Allow 对 key 计数一次并判断是否超出 limit
+// Redis 异常时放行，避免限流组件故障导致整站不可用
+func Allow(ctx context.Context, key string, limit int, window time.Duration) Result {
+	res, err := counterScript.Run(ctx, cache.GetRedis(), []string{"ratelimit:" + key}, window.Milliseconds()).Int64Slice()
+	if err != nil || len(res) != 2 {
+		log.Printf("Rate limit check failed: key=%s, err=%v", key, err)
+		return Result{Allowed: true, Limit: limit, Remaining: limit}
+	}
+
+	count, ttl := int(res[0]), time.Duration(res[1])*time.Millisecond
+	if count > limit {
+		return Result{Allowed: false, Limit: limit, RetryAfter: ttl}
+	}
+	return Result{Allowed: true, Limit: limit, Remaining: limit - count}
+}
+
+// ScaleByLevel 按用户等级放宽配额，每升一级增加 50%（Lv.5 为 3 倍）
+func ScaleByLevel(limit, level int) int {
+	if level < 1 {
+		level = 1
+	}
+	return limit + limit*(level-1)/2
+}
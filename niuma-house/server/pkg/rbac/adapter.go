@@ -0,0 +1,207 @@
+package rbac
+
+import (
+	"strings"
+	"time"
+
+	"github.com/casbin/casbin/v2/model"
+	"github.com/casbin/casbin/v2/persist"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CasbinRule Casbin 策略规则实体
+type CasbinRule struct {
+	ID    uint   `gorm:"primaryKey"`
+	Ptype string `gorm:"size:100;index"`
+	V0    string `gorm:"size:100"`
+	V1    string `gorm:"size:100"`
+	V2    string `gorm:"size:100"`
+	V3    string `gorm:"size:100"`
+	V4    string `gorm:"size:100"`
+	V5    string `gorm:"size:100"`
+}
+
+// TableName 表名
+func (CasbinRule) TableName() string {
+	return "casbin_rule"
+}
+
+// CasbinSeed 已从策略文件导入过的规则，之后即使被管理员删除也不会再次导入
+type CasbinSeed struct {
+	ID        uint   `gorm:"primaryKey"`
+	Rule      string `gorm:"size:191;uniqueIndex"` // 规则行，如 "p, content_admin, /api/admin/posts, GET"
+	CreatedAt time.Time
+}
+
+// TableName 表名
+func (CasbinSeed) TableName() string {
+	return "casbin_seeds"
+}
+
+// seedLine 规则的文本形式，与 CSV 中的写法一致
+func seedLine(ptype string, rule []string) string {
+	return ptype + ", " + strings.Join(rule, ", ")
+}
+
+// toArray 转换为 Casbin 策略数组（ptype 在首位）
+func (r *CasbinRule) toArray() []string {
+	values := []string{r.Ptype, r.V0, r.V1, r.V2, r.V3, r.V4, r.V5}
+	// 去掉末尾的空字段
+	end := len(values)
+	for end > 1 && values[end-1] == "" {
+		end--
+	}
+	return values[:end]
+}
+
+// newCasbinRule 根据策略构造规则
+func newCasbinRule(ptype string, rule []string) CasbinRule {
+	line := CasbinRule{Ptype: ptype}
+	fields := []*string{&line.V0, &line.V1, &line.V2, &line.V3, &line.V4, &line.V5}
+	for i, v := range rule {
+		if i >= len(fields) {
+			break
+		}
+		*fields[i] = v
+	}
+	return line
+}
+
+// GormAdapter 基于 GORM 的 Casbin 策略存储
+type GormAdapter struct {
+	db *gorm.DB
+}
+
+var _ persist.BatchAdapter = (*GormAdapter)(nil)
+
+// NewGormAdapter 创建 GORM 策略存储
+func NewGormAdapter(db *gorm.DB) (*GormAdapter, error) {
+	if err := db.AutoMigrate(&CasbinRule{}, &CasbinSeed{}); err != nil {
+		return nil, err
+	}
+	return &GormAdapter{db: db}, nil
+}
+
+// IsEmpty 数据库中是否没有任何策略
+func (a *GormAdapter) IsEmpty() bool {
+	var count int64
+	a.db.Model(&CasbinRule{}).Count(&count)
+	return count == 0
+}
+
+// Seeded 已导入过的规则行集合
+func (a *GormAdapter) Seeded() (map[string]bool, error) {
+	var lines []string
+	if err := a.db.Model(&CasbinSeed{}).Pluck("rule", &lines).Error; err != nil {
+		return nil, err
+	}
+	seeded := make(map[string]bool, len(lines))
+	for _, line := range lines {
+		seeded[line] = true
+	}
+	return seeded, nil
+}
+
+// MarkSeeded 记录规则行已导入，已记录的忽略
+func (a *GormAdapter) MarkSeeded(lines []string) error {
+	if len(lines) == 0 {
+		return nil
+	}
+	seeds := make([]CasbinSeed, 0, len(lines))
+	for _, line := range lines {
+		seeds = append(seeds, CasbinSeed{Rule: line})
+	}
+	return a.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&seeds).Error
+}
+
+// LoadPolicy 从数据库加载全部策略
+func (a *GormAdapter) LoadPolicy(m model.Model) error {
+	var rules []CasbinRule
+	if err := a.db.Order("id ASC").Find(&rules).Error; err != nil {
+		return err
+	}
+
+	for i := range rules {
+		if err := persist.LoadPolicyArray(rules[i].toArray(), m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SavePolicy 将内存中的全部策略覆盖写入数据库
+func (a *GormAdapter) SavePolicy(m model.Model) error {
+	var rules []CasbinRule
+	for _, sec := range []string{"p", "g"} {
+		for ptype, ast := range m[sec] {
+			for _, rule := range ast.Policy {
+				rules = append(rules, newCasbinRule(ptype, rule))
+			}
+		}
+	}
+
+	return a.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&CasbinRule{}).Error; err != nil {
+			return err
+		}
+		if len(rules) == 0 {
+			return nil
+		}
+		return tx.Create(&rules).Error
+	})
+}
+
+// AddPolicy 新增一条策略
+func (a *GormAdapter) AddPolicy(sec string, ptype string, rule []string) error {
+	line := newCasbinRule(ptype, rule)
+	return a.db.Create(&line).Error
+}
+
+// AddPolicies 批量新增策略
+func (a *GormAdapter) AddPolicies(sec string, ptype string, rules [][]string) error {
+	if len(rules) == 0 {
+		return nil
+	}
+	lines := make([]CasbinRule, 0, len(rules))
+	for _, rule := range rules {
+		lines = append(lines, newCasbinRule(ptype, rule))
+	}
+	return a.db.Create(&lines).Error
+}
+
+// RemovePolicy 删除一条策略
+func (a *GormAdapter) RemovePolicy(sec string, ptype string, rule []string) error {
+	line := newCasbinRule(ptype, rule)
+	return a.db.Where(&line, "Ptype", "V0", "V1", "V2", "V3", "V4", "V5").
+		Delete(&CasbinRule{}).Error
+}
+
+// RemovePolicies 批量删除策略
+func (a *GormAdapter) RemovePolicies(sec string, ptype string, rules [][]string) error {
+	return a.db.Transaction(func(tx *gorm.DB) error {
+		for _, rule := range rules {
+			line := newCasbinRule(ptype, rule)
+			if err := tx.Where(&line, "Ptype", "V0", "V1", "V2", "V3", "V4", "V5").
+				Delete(&CasbinRule{}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// RemoveFilteredPolicy 按字段过滤删除策略
+func (a *GormAdapter) RemoveFilteredPolicy(sec string, ptype string, fieldIndex int, fieldValues ...string) error {
+	columns := []string{"v0", "v1", "v2", "v3", "v4", "v5"}
+
+	query := a.db.Where("ptype = ?", ptype)
+	for i, v := range fieldValues {
+		idx := fieldIndex + i
+		if v == "" || idx < 0 || idx >= len(columns) {
+			continue
+		}
+		query = query.Where(columns[idx]+" = ?", v)
+	}
+	return query.Delete(&CasbinRule{}).Error
+}
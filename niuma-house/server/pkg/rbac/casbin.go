@@ -0,0 +1,147 @@
+package rbac
+
+import (
+	"log"
+	"sync"
+
+	"niuma-house/pkg/config"
+
+	"github.com/casbin/casbin/v2"
+	"gorm.io/gorm"
+)
+
+var (
+	enforcer *casbin.SyncedEnforcer
+	once     sync.Once
+)
+
+// InitCasbin 初始化 Casbin 权限控制
+// 策略持久化在 MySQL 中，首次启动时从 policy_path 指定的 CSV 导入；之后每次启动只导入
+// CSV 中从未导入过的规则（见 casbin_seeds），管理员删除的规则不会被重新导入
+func InitCasbin(cfg *config.CasbinConfig, db *gorm.DB) *casbin.SyncedEnforcer {
+	once.Do(func() {
+		adapter, err := NewGormAdapter(db)
+		if err != nil {
+			log.Fatalf("Failed to create casbin adapter: %v", err)
+		}
+
+		if adapter.IsEmpty() {
+			// 用 CSV 初始化后切换到数据库存储
+			enforcer, err = casbin.NewSyncedEnforcer(cfg.ModelPath, cfg.PolicyPath)
+			if err != nil {
+				log.Fatalf("Failed to load casbin policy file: %v", err)
+			}
+			enforcer.SetAdapter(adapter)
+			if err := enforcer.SavePolicy(); err != nil {
+				log.Fatalf("Failed to import casbin policy: %v", err)
+			}
+			log.Printf("Casbin policy imported from %s", cfg.PolicyPath)
+		} else {
+			enforcer, err = casbin.NewSyncedEnforcer(cfg.ModelPath, adapter)
+			if err != nil {
+				log.Fatalf("Failed to init casbin enforcer: %v", err)
+			}
+		}
+
+		if err := mergePolicyFile(enforcer, adapter, cfg); err != nil {
+			log.Fatalf("Failed to merge casbin policy file: %v", err)
+		}
+		log.Println("Casbin enforcer initialized")
+	})
+	return enforcer
+}
+
+// policyFileRule CSV 中的一条规则
+type policyFileRule struct {
+	ptype string
+	rule  []string
+}
+
+// mergePolicyFile 导入 CSV 中从未导入过的规则，并记录为已导入
+// 升级前已有策略的库第一次运行时没有导入记录，此时补齐数据库中缺少的规则后全部记录
+func mergePolicyFile(e *casbin.SyncedEnforcer, adapter *GormAdapter, cfg *config.CasbinConfig) error {
+	file, err := casbin.NewEnforcer(cfg.ModelPath, cfg.PolicyPath)
+	if err != nil {
+		return err
+	}
+	policies, err := file.GetPolicy()
+	if err != nil {
+		return err
+	}
+	groupings, err := file.GetGroupingPolicy()
+	if err != nil {
+		return err
+	}
+	rules := make([]policyFileRule, 0, len(policies)+len(groupings))
+	for _, rule := range policies {
+		rules = append(rules, policyFileRule{ptype: "p", rule: rule})
+	}
+	for _, rule := range groupings {
+		rules = append(rules, policyFileRule{ptype: "g", rule: rule})
+	}
+
+	seeded, err := adapter.Seeded()
+	if err != nil {
+		return err
+	}
+
+	var newPolicies, newGroupings [][]string
+	var lines []string
+	for _, r := range rules {
+		line := seedLine(r.ptype, r.rule)
+		if seeded[line] {
+			continue
+		}
+		lines = append(lines, line)
+
+		has := e.HasPolicy
+		if r.ptype == "g" {
+			has = e.HasGroupingPolicy
+		}
+		ok, err := has(r.rule)
+		if err != nil {
+			return err
+		}
+		if ok {
+			continue
+		}
+		if r.ptype == "g" {
+			newGroupings = append(newGroupings, r.rule)
+		} else {
+			newPolicies = append(newPolicies, r.rule)
+		}
+	}
+
+	if len(newPolicies) > 0 {
+		if _, err := e.AddPolicies(newPolicies); err != nil {
+			return err
+		}
+	}
+	if len(newGroupings) > 0 {
+		if _, err := e.AddGroupingPolicies(newGroupings); err != nil {
+			return err
+		}
+	}
+	if n := len(newPolicies) + len(newGroupings); n > 0 {
+		log.Printf("Casbin policy merged %d new rules from %s", n, cfg.PolicyPath)
+	}
+	return adapter.MarkSeeded(lines)
+}
+
+// GetEnforcer 获取 Casbin Enforcer 单例
+func GetEnforcer() *casbin.SyncedEnforcer {
+	if enforcer == nil {
+		log.Fatal("Casbin not initialized. Call InitCasbin first.")
+	}
+	return enforcer
+}
+
+// Enforce 校验角色是否可以对资源执行操作
+func Enforce(role, path, method string) (bool, error) {
+	return GetEnforcer().Enforce(role, path, method)
+}
+
+// Reload 从数据库重新加载策略
+func Reload() error {
+	return GetEnforcer().LoadPolicy()
+}
@@ -1,7 +1,10 @@
 package response
 
 import (
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -39,6 +42,15 @@ func Fail(c *gin.Context, code int, message string) {
 	})
 }
 
+// FailWithData 失败响应带数据
+func FailWithData(c *gin.Context, code int, message string, data interface{}) {
+	c.JSON(http.StatusOK, Response{
+		Code:    code,
+		Message: message,
+		Data:    data,
+	})
+}
+
 // FailWithStatus 失败响应带 HTTP 状态码
 func FailWithStatus(c *gin.Context, httpStatus int, code int, message string) {
 	c.JSON(httpStatus, Response{
@@ -63,6 +75,20 @@ func Forbidden(c *gin.Context, message string) {
 	})
 }
 
+// TooManyRequests 请求过于频繁响应，通过 Retry-After 头和 data.retry_after 返回建议的等待秒数
+func TooManyRequests(c *gin.Context, retryAfter time.Duration) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	c.JSON(http.StatusTooManyRequests, Response{
+		Code:    CodeTooManyRequests,
+		Message: "操作过于频繁，请稍后再试",
+		Data:    gin.H{"retry_after": seconds},
+	})
+}
+
 // ServerError 服务器错误响应
 func ServerError(c *gin.Context, message string) {
 	c.JSON(http.StatusInternalServerError, Response{
@@ -73,14 +99,17 @@ func ServerError(c *gin.Context, message string) {
 
 // 常用错误码
 const (
-	CodeSuccess        = 0
-	CodeInvalidParams  = 10001
-	CodeUserExists     = 10002
-	CodeUserNotFound   = 10003
-	CodeWrongPassword  = 10004
-	CodeTokenInvalid   = 10005
-	CodeTokenExpired   = 10006
-	CodePermissionDeny = 10007
-	CodeNotFound       = 10008
-	CodeServerError    = 50000
+	CodeSuccess         = 0
+	CodeInvalidParams   = 10001
+	CodeUserExists      = 10002
+	CodeUserNotFound    = 10003
+	CodeWrongPassword   = 10004
+	CodeTokenInvalid    = 10005
+	CodeTokenExpired    = 10006
+	CodePermissionDeny  = 10007
+	CodeNotFound        = 10008
+	CodeConflict        = 10009
+	CodeTooManyRequests = 10010
+	CodeUserSanctioned  = 10011 // 用户被禁言或禁私信，data 为生效中的处罚
+	CodeServerError     = 50000
 )
@@ -1,3 +1,4 @@
+import type { AxiosResponse } from 'axios'
 import request from '@/utils/request'
 
 // 登录
@@ -5,6 +6,11 @@ export const login = (data: { username: string; password: string }) => {
     return request.post('/api/auth/login', data)
 }
 
+// 退出登录
+export const logout = () => {
+    return request.post('/api/auth/logout')
+}
+
 // 获取统计数据
 export const getDashboardStats = () => {
     return request.get('/api/admin/dashboard/stats')
@@ -15,14 +21,59 @@ export const getUsers = (params?: { page?: number; size?: number }) => {
     return request.get('/api/admin/users', { params })
 }
 
-// 封禁用户
-export const banUser = (id: number) => {
-    return request.post(`/api/admin/users/${id}/ban`)
+// 封禁用户，duration 为小时数，不传时永久封禁；reason 会写入审计日志
+export const banUser = (id: number, reason?: string, duration?: number) => {
+    return request.post(`/api/admin/users/${id}/ban`, { reason, duration })
+}
+
+// 解封用户，解除其所有生效中的封禁
+export const unbanUser = (id: number, reason?: string) => {
+    return request.post(`/api/admin/users/${id}/unban`, { reason })
+}
+
+// 踢用户下线
+export const kickUser = (id: number) => {
+    return request.post(`/api/admin/users/${id}/kick`)
+}
+
+export type SanctionType = 'mute_post' | 'mute_dm' | 'ban'
+
+// 处罚用户：mute_post 禁言，mute_dm 禁私信，ban 封禁；duration 为小时数，0 表示永久
+export const issueSanction = (userId: number, data: { type: SanctionType; reason: string; duration: number }) => {
+    return request.post(`/api/admin/users/${userId}/sanctions`, data)
+}
+
+// 处罚记录，active 为 1 时只看生效中的处罚
+export const getSanctions = (params?: { user_id?: number; type?: string; active?: number; page?: number; size?: number }) => {
+    return request.get('/api/admin/sanctions', { params })
+}
+
+// 提前解除处罚，reason 会写入审计日志
+export const liftSanction = (id: number, reason?: string) => {
+    return request.post(`/api/admin/sanctions/${id}/lift`, { reason })
 }
 
-// 解封用户
-export const unbanUser = (id: number) => {
-    return request.post(`/api/admin/users/${id}/unban`)
+// 获取等级配置
+export const getLevels = () => {
+    return request.get('/api/admin/levels')
+}
+
+// 新增或修改等级，保存后会按新配置校准所有用户等级
+export const saveLevel = (level: number, data: {
+    name: string
+    min_exp: number
+    daily_post_quota: number
+    can_message: boolean
+    can_upload_image: boolean
+    badge_name?: string
+    badge_icon?: string
+}) => {
+    return request.put(`/api/admin/levels/${level}`, data)
+}
+
+// 删除等级（Lv.1 不能删除）
+export const deleteLevel = (level: number) => {
+    return request.delete(`/api/admin/levels/${level}`)
 }
 
 // 获取帖子列表
@@ -30,14 +81,14 @@ export const getPosts = (params?: { page?: number; size?: number }) => {
     return request.get('/api/admin/posts', { params })
 }
 
-// 删除帖子
-export const deletePost = (id: number) => {
-    return request.delete(`/api/admin/posts/${id}`)
+// 删除帖子，reason 会写入审计日志
+export const deletePost = (id: number, reason?: string) => {
+    return request.delete(`/api/admin/posts/${id}`, { data: { reason } })
 }
 
 // 置顶帖子
-export const topPost = (id: number) => {
-    return request.post(`/api/admin/posts/${id}/top`)
+export const topPost = (id: number, reason?: string) => {
+    return request.post(`/api/admin/posts/${id}/top`, { reason })
 }
 
 // 获取公司列表
@@ -45,7 +96,99 @@ export const getCompanies = (params?: { page?: number; size?: number }) => {
     return request.get('/api/admin/companies', { params })
 }
 
-// 删除公司
-export const deleteCompany = (id: number) => {
-    return request.delete(`/api/admin/companies/${id}`)
+// 合并公司
+export const mergeCompany = (id: number, targetId: number) => {
+    return request.post(`/api/admin/companies/${id}/merge`, { target_id: targetId })
+}
+
+// 删除公司，reason 会写入审计日志
+export const deleteCompany = (id: number, reason?: string) => {
+    return request.delete(`/api/admin/companies/${id}`, { data: { reason } })
+}
+
+// 查看死信队列
+export const getDeadLetters = (params?: { limit?: number }) => {
+    return request.get('/api/admin/mq/dead-letters', { params })
+}
+
+// 重放死信消息，不传 message_ids 时按 limit 重放队列头部的消息
+export const replayDeadLetters = (data: { message_ids?: string[]; limit?: number }) => {
+    return request.post('/api/admin/mq/dead-letters/replay', data)
+}
+
+// 查看匿名内容的真实作者，每次查看都会记录审计日志
+export const revealAuthor = (data: { target_type: 'post' | 'comment' | 'company' | 'review'; target_id: number; reason: string }) => {
+    return request.post('/api/admin/anonymous/reveal', data)
+}
+
+// 查看真实作者的审计记录
+export const getReveals = (params?: { target_type?: string; page?: number; size?: number }) => {
+    return request.get('/api/admin/anonymous/reveals', { params })
+}
+
+// 审核队列，status / target_type 为空时不过滤，mine 为 1 时只看分配给自己的工单
+export const getModerationCases = (params?: { status?: string; target_type?: string; mine?: number; page?: number; size?: number }) => {
+    return request.get('/api/admin/moderation/cases', { params })
+}
+
+// 工单详情（含全部举报记录）
+export const getModerationCase = (id: number) => {
+    return request.get(`/api/admin/moderation/cases/${id}`)
+}
+
+// 分配工单，不传 assignee_id 时分配给自己，传 0 取消分配
+export const assignModerationCase = (id: number, assigneeId?: number) => {
+    return request.post(`/api/admin/moderation/cases/${id}/assign`, { assignee_id: assigneeId })
+}
+
+// 结案：approved 举报属实并隐藏内容，rejected 驳回并恢复内容，actioned 删除内容或封禁作者
+export const resolveModerationCase = (id: number, data: { status: 'approved' | 'rejected' | 'actioned'; action?: 'delete' | 'ban'; note: string }) => {
+    return request.post(`/api/admin/moderation/cases/${id}/resolve`, data)
+}
+
+export type FilterAction = 'mask' | 'review' | 'reject'
+
+// 敏感词列表，keyword 按词模糊匹配
+export const getSensitiveWords = (params?: { keyword?: string; category?: string; page?: number; size?: number }) => {
+    return request.get('/api/admin/filter/words', { params })
+}
+
+// 批量添加敏感词，已存在的词跳过，返回新增条数
+export const addSensitiveWords = (data: { words: string[]; category: string; action: FilterAction }) => {
+    return request.post('/api/admin/filter/words', data)
+}
+
+// 修改敏感词的分类和处理方式
+export const updateSensitiveWord = (id: number, data: { category: string; action: FilterAction }) => {
+    return request.put(`/api/admin/filter/words/${id}`, data)
+}
+
+// 删除敏感词
+export const deleteSensitiveWord = (id: number) => {
+    return request.delete(`/api/admin/filter/words/${id}`)
+}
+
+// 用当前词库和个人信息检测配置检查一段文本
+export const testContentFilter = (text: string) => {
+    return request.post('/api/admin/filter/test', { text })
+}
+
+// 审计日志筛选条件，start / end 为 2006-01-02
+export interface AuditLogQuery {
+    actor_id?: number
+    action?: string
+    target_type?: string
+    target_id?: string
+    start?: string
+    end?: string
+}
+
+// 获取审计日志
+export const getAuditLogs = (params?: AuditLogQuery & { page?: number; size?: number }) => {
+    return request.get('/api/admin/audit-logs', { params })
+}
+
+// 导出审计日志 CSV（最多 10000 条）
+export const exportAuditLogs = (params?: AuditLogQuery) => {
+    return request.get<AxiosResponse<Blob>>('/api/admin/audit-logs/export', { params, responseType: 'blob' })
 }
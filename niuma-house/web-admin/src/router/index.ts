@@ -23,6 +23,12 @@ const routes: RouteRecordRaw[] = [
                 component: () => import('@/views/Users.vue'),
                 meta: { title: '用户管理' }
             },
+            {
+                path: 'sanctions',
+                name: 'Sanctions',
+                component: () => import('@/views/Sanctions.vue'),
+                meta: { title: '处罚记录' }
+            },
             {
                 path: 'posts',
                 name: 'Posts',
@@ -34,6 +40,24 @@ const routes: RouteRecordRaw[] = [
                 name: 'Companies',
                 component: () => import('@/views/Companies.vue'),
                 meta: { title: '公司管理' }
+            },
+            {
+                path: 'moderation',
+                name: 'Moderation',
+                component: () => import('@/views/Moderation.vue'),
+                meta: { title: '举报审核' }
+            },
+            {
+                path: 'sensitive-words',
+                name: 'SensitiveWords',
+                component: () => import('@/views/SensitiveWords.vue'),
+                meta: { title: '敏感词库' }
+            },
+            {
+                path: 'audit-logs',
+                name: 'AuditLogs',
+                component: () => import('@/views/AuditLogs.vue'),
+                meta: { title: '审计日志' }
             }
         ]
     }
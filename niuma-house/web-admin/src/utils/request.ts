@@ -18,23 +18,67 @@ axiosInstance.interceptors.request.use(
     (error) => Promise.reject(error)
 )
 
+// 清除登录状态并跳转登录页
+const redirectToLogin = () => {
+    localStorage.removeItem('admin_token')
+    localStorage.removeItem('admin_refresh_token')
+    window.location.href = '/login'
+}
+
+// 刷新访问令牌（并发请求共享同一次刷新）
+let refreshing: Promise<string> | null = null
+const refreshAccessToken = (): Promise<string> => {
+    if (!refreshing) {
+        const refreshToken = localStorage.getItem('admin_refresh_token')
+        refreshing = (refreshToken
+            ? axios.post('/api/auth/refresh', { refresh_token: refreshToken }).then((resp) => {
+                const res = resp.data
+                if (res.code !== 0) {
+                    throw new Error(res.message)
+                }
+                localStorage.setItem('admin_token', res.data.token)
+                localStorage.setItem('admin_refresh_token', res.data.refresh_token)
+                return res.data.token as string
+            })
+            : Promise.reject(new Error('no refresh token'))
+        ).finally(() => {
+            refreshing = null
+        })
+    }
+    return refreshing
+}
+
 axiosInstance.interceptors.response.use(
     (response: AxiosResponse) => {
+        // 文件下载直接返回原始响应
+        if (response.config.responseType === 'blob') {
+            return response
+        }
         const res = response.data
         if (res.code !== 0) {
             ElMessage.error(res.message || '请求失败')
             if (res.code === 401 || res.code === 10005 || res.code === 10006) {
-                localStorage.removeItem('admin_token')
-                window.location.href = '/login'
+                redirectToLogin()
             }
             return Promise.reject(new Error(res.message))
         }
         return res.data
     },
-    (error) => {
+    async (error) => {
+        const config = error.config
         if (error.response?.status === 401) {
-            localStorage.removeItem('admin_token')
-            window.location.href = '/login'
+            // 访问令牌过期时尝试刷新一次后重放请求
+            if (config && !config._retried) {
+                config._retried = true
+                try {
+                    const token = await refreshAccessToken()
+                    config.headers.Authorization = `Bearer ${token}`
+                    return axiosInstance(config)
+                } catch {
+                    // 刷新失败，重新登录
+                }
+            }
+            redirectToLogin()
         }
         ElMessage.error(error.message || '网络错误')
         return Promise.reject(error)
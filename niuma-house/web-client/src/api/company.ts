@@ -1,5 +1,6 @@
 import request from '@/utils/request'
 import type { User } from './user'
+import type { AnonymousAuthor } from './post'
 
 export interface Company {
     id: number
@@ -9,8 +10,11 @@ export interface Company {
     risk_level: number
     evidence: string[]
     content: string
-    creator_id: number
+    creator_id?: number // 匿名曝光不返回
     creator?: User
+    anonymous: boolean
+    author?: AnonymousAuthor
+    is_mine?: boolean
     status: number
     view_count: number
     created_at: string
@@ -30,6 +34,13 @@ export interface CreateCompanyRequest {
     risk_level: number
     evidence: string[]
     content: string
+    force?: boolean // 忽略疑似重复，强制创建
+    anonymous?: boolean // 匿名曝光，首条评价同样匿名
+}
+
+export interface CompanyCandidate {
+    company: Company
+    similarity: number
 }
 
 // 获取公司列表
@@ -37,8 +48,8 @@ export const getCompanies = (params?: { page?: number; size?: number }): Promise
     return request.get('/companies', { params })
 }
 
-// 搜索公司
-export const searchCompanies = (params: { keyword: string; page?: number; size?: number }): Promise<CompanyListResponse & { keyword: string }> => {
+// 搜索公司，支持城市、标签、最低避雷等级筛选
+export const searchCompanies = (params: { keyword: string; city?: string; tag?: string; risk_level?: number; page?: number; size?: number }): Promise<CompanyListResponse & { keyword: string }> => {
     return request.get('/companies/search', { params })
 }
 
@@ -51,3 +62,8 @@ export const getCompany = (id: number): Promise<Company> => {
 export const createCompany = (data: CreateCompanyRequest): Promise<Company> => {
     return request.post('/companies', data)
 }
+
+// 创建前查重
+export const checkCompanyDuplicates = (name: string): Promise<{ candidates: CompanyCandidate[] }> => {
+    return request.get('/companies/duplicates', { params: { name } })
+}
@@ -5,7 +5,10 @@ export interface Message {
   sender_id: number
   receiver_id: number
   content: string
+  client_msg_id?: string
   is_read: boolean
+  delivered_at?: string
+  read_at?: string
   created_at: string
   sender?: {
     id: number
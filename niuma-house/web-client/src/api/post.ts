@@ -1,10 +1,20 @@
 import request from '@/utils/request'
 import type { User } from './user'
 
+// 匿名作者：同一帖子（含评论）内化名固定，只展示职业和等级区间
+export interface AnonymousAuthor {
+    pseudonym: string
+    occupation?: string
+    level_bucket: string
+}
+
 export interface Post {
     id: number
-    user_id: number
+    user_id?: number // 匿名帖子不返回
     user?: User
+    anonymous: boolean
+    author?: AnonymousAuthor
+    is_mine?: boolean
     occupation_id: number
     occupation?: { id: number; name: string }
     title: string
@@ -26,6 +36,7 @@ export interface CreatePostRequest {
     title: string
     content: string
     occupation_id: number
+    anonymous?: boolean
 }
 
 // 获取帖子列表
@@ -33,6 +44,11 @@ export const getPosts = (params: { occupation_id?: number; page?: number; size?:
     return request.get('/posts', { params })
 }
 
+// 搜索帖子，highlight 为命中字段的高亮 HTML 片段
+export const searchPosts = (params: { keyword: string; occupation_id?: number; page?: number; size?: number }): Promise<{ list: (Post & { score: number; highlight?: Record<string, string> })[]; total: number; keyword: string }> => {
+    return request.get('/posts/search', { params })
+}
+
 // 获取帖子详情
 export const getPost = (id: number): Promise<{ post: Post; is_liked: boolean; is_favorited: boolean }> => {
     return request.get(`/posts/${id}`)
@@ -79,7 +95,7 @@ export const getComments = (postId: number, params?: { page?: number; size?: num
 }
 
 // 创建评论
-export const createComment = (postId: number, data: { content: string; parent_id?: number }): Promise<any> => {
+export const createComment = (postId: number, data: { content: string; parent_id?: number; anonymous?: boolean }): Promise<any> => {
     return request.post(`/posts/${postId}/comments`, data)
 }
 
@@ -0,0 +1,29 @@
+import request from '@/utils/request'
+
+export type ReportTargetType = 'post' | 'comment' | 'company' | 'user' | 'message'
+
+export interface ReportReason {
+    code: string
+    name: string
+}
+
+// 举报理由分类
+export const getReportReasons = (): Promise<ReportReason[]> => {
+    return request.get('/reports/reasons')
+}
+
+// 举报内容，同一内容只能举报一次
+export const submitReport = (
+    targetType: ReportTargetType,
+    targetId: number,
+    data: { reason: string; detail?: string }
+): Promise<void> => {
+    const path = {
+        post: 'posts',
+        comment: 'comments',
+        company: 'companies',
+        user: 'users',
+        message: 'messages'
+    }[targetType]
+    return request.post(`/${path}/${targetId}/report`, data)
+}
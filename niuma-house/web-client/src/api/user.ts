@@ -8,9 +8,22 @@ export interface User {
     occupation_id: number
     occupation?: { id: number; name: string }
     level: number
+    level_name?: string
     exp: number
     role: string
     status: number
+    message_privacy?: MessagePrivacy
+    message_min_level?: number
+    sanctions?: UserSanction[]
+    created_at: string
+}
+
+// 生效中的处罚：mute_post 禁言，mute_dm 禁私信，ban 封禁；expires_at 为空表示永久
+export interface UserSanction {
+    id: number
+    type: 'mute_post' | 'mute_dm' | 'ban'
+    reason: string
+    expires_at: string | null
     created_at: string
 }
 
@@ -27,7 +40,10 @@ export interface RegisterRequest {
 
 export interface LoginResponse {
     token: string
+    refresh_token: string
+    expires_in: number
     user: User
+    checkin?: CheckinResult // 当天首次登录时自动签到
 }
 
 export interface UpdateProfileRequest {
@@ -41,6 +57,16 @@ export const login = (data: LoginRequest): Promise<LoginResponse> => {
     return request.post('/auth/login', data)
 }
 
+// 退出登录
+export const logout = (): Promise<void> => {
+    return request.post('/auth/logout')
+}
+
+// 修改密码
+export const changePassword = (data: { old_password: string; new_password: string }): Promise<void> => {
+    return request.put('/user/password', data)
+}
+
 // 注册
 export const register = (data: RegisterRequest): Promise<User> => {
     return request.post('/auth/register', data)
@@ -65,3 +91,100 @@ export const getOccupations = (): Promise<{ id: number; name: string }[]> => {
 export const getAvatarUploadUrl = (filename: string): Promise<{ upload_url: string; access_url: string; object_key: string }> => {
     return request.post('/user/avatar', { filename })
 }
+
+// 私信隐私设置：everyone 所有人, level 指定等级以上, nobody 不接收
+export type MessagePrivacy = 'everyone' | 'level' | 'nobody'
+
+// 更新私信隐私设置
+export const updatePrivacy = (data: { message_privacy: MessagePrivacy; message_min_level?: number }): Promise<void> => {
+    return request.put('/user/privacy', data)
+}
+
+// 获取黑名单
+export const getBlocks = (params?: { page?: number; size?: number }): Promise<{ list: { id: number; blocked_id: number; blocked?: User; created_at: string }[]; total: number }> => {
+    return request.get('/user/blocks', { params })
+}
+
+// 拉黑用户
+export const blockUser = (userId: number): Promise<void> => {
+    return request.post('/user/blocks', { user_id: userId })
+}
+
+// 取消拉黑
+export const unblockUser = (userId: number): Promise<void> => {
+    return request.delete(`/user/blocks/${userId}`)
+}
+
+// 经验流水：amount 为实际发放值（超出每日上限时小于 requested），revoked_at 非空表示已撤销
+export interface ExpLedgerEntry {
+    id: number
+    action: 'login' | 'post' | 'liked' | 'commented' | 'replied'
+    subject: string
+    requested: number
+    amount: number
+    day: string
+    revoked_at?: string
+    created_at: string
+}
+
+// 获取经验流水
+export const getExpHistory = (params?: { page?: number; size?: number }): Promise<{ list: ExpLedgerEntry[]; total: number }> => {
+    return request.get('/user/exp/history', { params })
+}
+
+// 等级配置及权益，daily_post_quota 为 0 表示不限
+export interface Level {
+    level: number
+    name: string
+    min_exp: number
+    daily_post_quota: number
+    can_message: boolean
+    can_upload_image: boolean
+    badge_name: string
+    badge_icon: string
+}
+
+export interface UserBadge {
+    id: number
+    level: number
+    name: string
+    icon: string
+    created_at: string
+}
+
+// 获取等级列表
+export const getLevels = (): Promise<Level[]> => {
+    return request.get('/levels')
+}
+
+// 获取我的徽章
+export const getMyBadges = (): Promise<UserBadge[]> => {
+    return request.get('/user/badges')
+}
+
+// 签到结果
+export interface CheckinResult {
+    day: string
+    streak: number
+    reward: number
+}
+
+// 签到月历：days 为已签到的日期（几号），rewards[i] 为连续签到第 i+1 天的奖励
+export interface CheckinCalendar {
+    month: string
+    days: number[]
+    streak: number
+    checked_today: boolean
+    next_reward: number
+    rewards: number[]
+}
+
+// 每日签到
+export const checkin = (): Promise<CheckinResult> => {
+    return request.post('/user/checkin')
+}
+
+// 获取签到月历，month 格式为 2006-01
+export const getCheckinCalendar = (month?: string): Promise<CheckinCalendar> => {
+    return request.get('/user/checkin/calendar', { params: { month } })
+}
@@ -1,6 +1,6 @@
 import { defineStore } from 'pinia'
 import { ref, computed } from 'vue'
-import { login, register, getProfile, type User, type LoginRequest, type RegisterRequest } from '@/api/user'
+import { login, logout as logoutApi, register, getProfile, type User, type LoginRequest, type RegisterRequest } from '@/api/user'
 
 export const useUserStore = defineStore('user', () => {
     const token = ref<string>(localStorage.getItem('token') || '')
@@ -8,20 +8,22 @@ export const useUserStore = defineStore('user', () => {
 
     const isLoggedIn = computed(() => !!token.value)
 
-    const setToken = (newToken: string) => {
+    const setToken = (newToken: string, refreshToken: string) => {
         token.value = newToken
         localStorage.setItem('token', newToken)
+        localStorage.setItem('refresh_token', refreshToken)
     }
 
     const clearToken = () => {
         token.value = ''
         user.value = null
         localStorage.removeItem('token')
+        localStorage.removeItem('refresh_token')
     }
 
     const loginAction = async (data: LoginRequest) => {
         const res = await login(data)
-        setToken(res.token)
+        setToken(res.token, res.refresh_token)
         user.value = res.user
         return res
     }
@@ -41,21 +43,16 @@ export const useUserStore = defineStore('user', () => {
         }
     }
 
-    const logout = () => {
-        clearToken()
+    const logout = async () => {
+        try {
+            await logoutApi()
+        } finally {
+            clearToken()
+        }
     }
 
-    // 等级名称映射
-    const levelName = computed(() => {
-        const names: Record<number, string> = {
-            1: '普通牛马',
-            2: '内卷牛马',
-            3: '精英牛马',
-            4: '天选牛马',
-            5: '核动力牛马'
-        }
-        return names[user.value?.level || 1] || '普通牛马'
-    })
+    // 等级名称，由服务端按等级配置返回
+    const levelName = computed(() => user.value?.level_name || `Lv.${user.value?.level || 1}`)
 
     return {
         token,
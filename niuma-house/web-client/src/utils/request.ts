@@ -21,6 +21,36 @@ axiosInstance.interceptors.request.use(
     }
 )
 
+// 清除登录状态并跳转登录页
+const redirectToLogin = () => {
+    localStorage.removeItem('token')
+    localStorage.removeItem('refresh_token')
+    window.location.href = '/login'
+}
+
+// 刷新访问令牌（并发请求共享同一次刷新）
+let refreshing: Promise<string> | null = null
+const refreshAccessToken = (): Promise<string> => {
+    if (!refreshing) {
+        const refreshToken = localStorage.getItem('refresh_token')
+        refreshing = (refreshToken
+            ? axios.post('/api/auth/refresh', { refresh_token: refreshToken }).then((resp) => {
+                const res = resp.data
+                if (res.code !== 0) {
+                    throw new Error(res.message)
+                }
+                localStorage.setItem('token', res.data.token)
+                localStorage.setItem('refresh_token', res.data.refresh_token)
+                return res.data.token as string
+            })
+            : Promise.reject(new Error('no refresh token'))
+        ).finally(() => {
+            refreshing = null
+        })
+    }
+    return refreshing
+}
+
 // 响应拦截器
 axiosInstance.interceptors.response.use(
     (response: AxiosResponse) => {
@@ -30,18 +60,34 @@ axiosInstance.interceptors.response.use(
 
             // Token 过期或无效
             if (res.code === 401 || res.code === 10005 || res.code === 10006) {
-                localStorage.removeItem('token')
-                window.location.href = '/login'
+                redirectToLogin()
             }
 
             return Promise.reject(new Error(res.message))
         }
         return res.data
     },
-    (error) => {
+    async (error) => {
+        const config = error.config
         if (error.response?.status === 401) {
-            localStorage.removeItem('token')
-            window.location.href = '/login'
+            // 访问令牌过期时尝试刷新一次后重放请求
+            if (config && !config._retried) {
+                config._retried = true
+                try {
+                    const token = await refreshAccessToken()
+                    config.headers.Authorization = `Bearer ${token}`
+                    return axiosInstance(config)
+                } catch {
+                    // 刷新失败，重新登录
+                }
+            }
+            redirectToLogin()
+        }
+        if (error.response?.status === 429) {
+            // 限流：提示服务端建议的等待时间
+            const retryAfter = error.response.data?.data?.retry_after
+            ElMessage.warning(retryAfter ? `操作过于频繁，请 ${retryAfter} 秒后再试` : '操作过于频繁，请稍后再试')
+            return Promise.reject(error)
         }
         ElMessage.error(error.message || '网络错误')
         return Promise.reject(error)